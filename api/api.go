@@ -2,6 +2,7 @@ package api
 
 import (
 	"github.com/jerbob92/hoppscotch-backend/api/controllers"
+	"github.com/jerbob92/hoppscotch-backend/api/controllers/mock"
 	"github.com/jerbob92/hoppscotch-backend/db"
 	"github.com/jerbob92/hoppscotch-backend/helpers/responses"
 
@@ -55,9 +56,41 @@ func StartAPI() error {
 		return err
 	}
 
+	if viper.GetBool("api.mock.enabled") && viper.GetString("api.mock.port") != "" {
+		go func() {
+			if err := startMockAPI(); err != nil {
+				log.Fatal(err)
+			}
+		}()
+	}
+
 	if viper.GetBool("api.ssl.enabled") {
 		return r.RunTLS(":"+viper.GetString("api.port"), viper.GetString("api.ssl.certificate"), viper.GetString("api.ssl.key"))
 	}
 
 	return r.Run(":" + viper.GetString("api.port"))
 }
+
+// startMockAPI serves the mock servers on their own port, where requests from
+// all origins are allowed, so frontends on any origin can use them.
+func startMockAPI() error {
+	r := gin.New()
+	r.Use(gin.Recovery())
+
+	corsConfig := cors.DefaultConfig()
+	corsConfig.AllowAllOrigins = true
+	corsConfig.AllowHeaders = []string{"*"}
+	r.Use(cors.New(corsConfig))
+
+	r.Use(db.AttachRequestSession())
+
+	if err := mock.AttachControllers(&r.RouterGroup); err != nil {
+		return err
+	}
+
+	if viper.GetBool("api.ssl.enabled") {
+		return r.RunTLS(":"+viper.GetString("api.mock.port"), viper.GetString("api.ssl.certificate"), viper.GetString("api.ssl.key"))
+	}
+
+	return r.Run(":" + viper.GetString("api.mock.port"))
+}
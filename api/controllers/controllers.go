@@ -2,8 +2,11 @@ package controllers
 
 import (
 	"github.com/jerbob92/hoppscotch-backend/api/controllers/graphql"
+	"github.com/jerbob92/hoppscotch-backend/api/controllers/mock"
+	"github.com/jerbob92/hoppscotch-backend/api/controllers/proxy"
 
 	"github.com/gin-gonic/gin"
+	"github.com/spf13/viper"
 )
 
 func AttachControllers(engine *gin.Engine) error {
@@ -11,5 +14,17 @@ func AttachControllers(engine *gin.Engine) error {
 		return err
 	}
 
+	if viper.GetBool("api.proxy.enabled") {
+		if err := proxy.AttachControllers(engine.RouterGroup.Group("/proxy")); err != nil {
+			return err
+		}
+	}
+
+	if viper.GetBool("api.mock.enabled") {
+		if err := mock.AttachControllers(engine.RouterGroup.Group("/mock")); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
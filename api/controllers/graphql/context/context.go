@@ -8,6 +8,7 @@ import (
 	"strings"
 	"sync"
 
+	"github.com/jerbob92/hoppscotch-backend/api/controllers/graphql/loaders"
 	"github.com/jerbob92/hoppscotch-backend/fb"
 	"github.com/jerbob92/hoppscotch-backend/models"
 
@@ -25,6 +26,9 @@ type Context struct {
 
 	// DisableResponses is mainly used for the graphql routes because the library handles error messages and we don't want to return custom errors
 	DisableResponses bool
+
+	// Loaders batch and cache lookups within a single GraphQL operation.
+	Loaders *loaders.Loaders
 }
 
 func GetContext(c *gin.Context) *Context {
@@ -55,6 +59,7 @@ func (c *Context) Clone() *Context {
 		loggingMeta:      newLoggingMeta,
 		locking:          sync.Mutex{},
 		DisableResponses: c.DisableResponses,
+		Loaders:          c.Loaders,
 	}
 
 	if c.ReqUser != nil {
@@ -83,6 +88,18 @@ func (c *Context) LogErr(err error, meta ...map[string]interface{}) {
 	log.WithFields(data).Error(err)
 }
 
+// GetLoaders returns the loaders of the operation, contexts that weren't
+// created for an operation get their own.
+func (c *Context) GetLoaders() *loaders.Loaders {
+	c.locking.Lock()
+	defer c.locking.Unlock()
+
+	if c.Loaders == nil {
+		c.Loaders = loaders.New(c.GetDB())
+	}
+	return c.Loaders
+}
+
 func (c *Context) GetDB() *gorm.DB {
 	if c.GinContext == nil {
 		return nil
@@ -3,13 +3,20 @@ package graphql
 import (
 	goctx "context"
 	"net/http"
+	"strings"
 
 	"github.com/jerbob92/hoppscotch-backend/api/controllers/graphql/context"
+	"github.com/jerbob92/hoppscotch-backend/api/controllers/graphql/resolvers"
 
 	"github.com/gin-gonic/gin"
+	"github.com/graph-gophers/graphql-go"
 	"github.com/graph-gophers/graphql-transport-ws/graphqlws"
 )
 
+// maxUploadRequestSize is the maximum size of a multipart request: an uploaded
+// document and the operation.
+const maxUploadRequestSize = resolvers.MaxImportDocumentSize + 1024*1024
+
 func AttachControllers(r *gin.RouterGroup) error {
 	r.Any("", graphqlRequest())
 	r.Any("ws", graphqlRequest())
@@ -30,6 +37,18 @@ func graphqlRequest() gin.HandlerFunc {
 	graphQLHandler := graphqlws.NewHandlerFunc(Handler.Schema, Handler, graphqlws.WithContextGenerator(&contextGenerator{}))
 
 	return func(c *gin.Context) {
+		// File uploads are sent as multipart/form-data, which the default
+		// handler doesn't understand.
+		if strings.HasPrefix(c.GetHeader("Content-Type"), "multipart/form-data") {
+			c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxUploadRequestSize)
+			reqC := context.GetContext(c)
+			reqC.DisableResponses = true
+			Handle(reqC, func(req *Request) *graphql.Response {
+				return Handler.Schema.Exec(goctx.WithValue(req.Context, "graphqlC", reqC), req.Query, req.OperationName, req.Variables)
+			})
+			return
+		}
+
 		clonedRequest := c.Request.WithContext(goctx.WithValue(c.Request.Context(), "ginctx", c))
 		graphQLHandler.ServeHTTP(c.Writer, clonedRequest)
 	}
@@ -0,0 +1,118 @@
+package loaders
+
+import (
+	"sync"
+	"time"
+)
+
+const (
+	// batchWait is how long a loader waits for more keys before it fetches.
+	batchWait = 2 * time.Millisecond
+
+	// maxBatchSize keeps the IN lists below the parameter limits of the
+	// databases.
+	maxBatchSize = 500
+)
+
+// Loader batches lookups that happen around the same time into a single
+// fetch, and caches the results for its lifetime.
+type Loader[K comparable, V any] struct {
+	fetch func(keys []K) (map[K]V, error)
+
+	mu      sync.Mutex
+	cache   map[K]*entry[V]
+	pending map[K]*entry[V]
+}
+
+type entry[V any] struct {
+	done  chan struct{}
+	value V
+	found bool
+	err   error
+}
+
+// NewLoader creates a loader, fetch returns the values of the keys it could
+// find.
+func NewLoader[K comparable, V any](fetch func(keys []K) (map[K]V, error)) *Loader[K, V] {
+	return &Loader[K, V]{
+		fetch:   fetch,
+		cache:   map[K]*entry[V]{},
+		pending: map[K]*entry[V]{},
+	}
+}
+
+// Load returns the value for the key, found is false when it doesn't exist.
+func (l *Loader[K, V]) Load(key K) (V, bool, error) {
+	l.mu.Lock()
+	e, ok := l.cache[key]
+	if !ok {
+		e = &entry[V]{done: make(chan struct{})}
+		l.cache[key] = e
+		l.pending[key] = e
+
+		if len(l.pending) == 1 {
+			time.AfterFunc(batchWait, l.dispatch)
+		} else if len(l.pending) >= maxBatchSize {
+			go l.dispatch()
+		}
+	}
+	l.mu.Unlock()
+
+	<-e.done
+	return e.value, e.found, e.err
+}
+
+// Prime adds a value to the cache, so it doesn't have to be fetched.
+func (l *Loader[K, V]) Prime(key K, value V) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if _, ok := l.cache[key]; ok {
+		return
+	}
+
+	e := &entry[V]{done: make(chan struct{}), value: value, found: true}
+	close(e.done)
+	l.cache[key] = e
+}
+
+// Clear removes a key from the cache, after the value has been changed.
+func (l *Loader[K, V]) Clear(key K) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if e, ok := l.cache[key]; ok && l.pending[key] != e {
+		delete(l.cache, key)
+	}
+}
+
+func (l *Loader[K, V]) dispatch() {
+	l.mu.Lock()
+	pending := l.pending
+	l.pending = map[K]*entry[V]{}
+	l.mu.Unlock()
+
+	if len(pending) == 0 {
+		return
+	}
+
+	keys := make([]K, 0, len(pending))
+	for key := range pending {
+		keys = append(keys, key)
+	}
+
+	values, err := l.fetch(keys)
+
+	l.mu.Lock()
+	for key, e := range pending {
+		if err != nil {
+			e.err = err
+			// Don't cache errors, the next load can try again.
+			delete(l.cache, key)
+		} else {
+			e.value, e.found = values[key]
+		}
+		close(e.done)
+	}
+	l.mu.Unlock()
+}
@@ -0,0 +1,106 @@
+package loaders
+
+import (
+	"github.com/jerbob92/hoppscotch-backend/models"
+
+	"gorm.io/gorm"
+)
+
+// Loaders are the batching loaders of a single GraphQL operation.
+type Loaders struct {
+	User                 *Loader[uint, *models.User]
+	Team                 *Loader[uint, *models.Team]
+	TeamCollection       *Loader[uint, *models.TeamCollection]
+	ChildCollections     *Loader[uint, []*models.TeamCollection]
+	RequestsInCollection *Loader[uint, []*models.TeamRequest]
+}
+
+// New creates empty loaders, nothing is shared between operations so there is
+// no stale data.
+func New(db *gorm.DB) *Loaders {
+	return &Loaders{
+		User: NewLoader(func(ids []uint) (map[uint]*models.User, error) {
+			users := []*models.User{}
+			err := db.Model(&models.User{}).Where("id IN ?", ids).Find(&users).Error
+			if err != nil {
+				return nil, err
+			}
+
+			output := map[uint]*models.User{}
+			for i := range users {
+				output[users[i].ID] = users[i]
+			}
+			return output, nil
+		}),
+		Team: NewLoader(func(ids []uint) (map[uint]*models.Team, error) {
+			teams := []*models.Team{}
+			err := db.Model(&models.Team{}).Where("id IN ?", ids).Find(&teams).Error
+			if err != nil {
+				return nil, err
+			}
+
+			output := map[uint]*models.Team{}
+			for i := range teams {
+				output[teams[i].ID] = teams[i]
+			}
+			return output, nil
+		}),
+		TeamCollection: NewLoader(func(ids []uint) (map[uint]*models.TeamCollection, error) {
+			collections := []*models.TeamCollection{}
+			err := db.Model(&models.TeamCollection{}).Where("id IN ?", ids).Find(&collections).Error
+			if err != nil {
+				return nil, err
+			}
+
+			output := map[uint]*models.TeamCollection{}
+			for i := range collections {
+				output[collections[i].ID] = collections[i]
+			}
+			return output, nil
+		}),
+		ChildCollections: NewLoader(func(parentIDs []uint) (map[uint][]*models.TeamCollection, error) {
+			collections := []*models.TeamCollection{}
+			err := db.Model(&models.TeamCollection{}).Where("parent_id IN ?", parentIDs).Order("id").Find(&collections).Error
+			if err != nil {
+				return nil, err
+			}
+
+			return GroupCollectionsByParent(parentIDs, collections), nil
+		}),
+		RequestsInCollection: NewLoader(func(collectionIDs []uint) (map[uint][]*models.TeamRequest, error) {
+			requests := []*models.TeamRequest{}
+			err := db.Model(&models.TeamRequest{}).Where("team_collection_id IN ?", collectionIDs).Order("id").Find(&requests).Error
+			if err != nil {
+				return nil, err
+			}
+
+			return GroupRequestsByCollection(collectionIDs, requests), nil
+		}),
+	}
+}
+
+// GroupCollectionsByParent groups the collections by parent, every parent
+// gets a (possibly empty) list.
+func GroupCollectionsByParent(parentIDs []uint, collections []*models.TeamCollection) map[uint][]*models.TeamCollection {
+	output := map[uint][]*models.TeamCollection{}
+	for _, parentID := range parentIDs {
+		output[parentID] = []*models.TeamCollection{}
+	}
+	for i := range collections {
+		output[collections[i].ParentID] = append(output[collections[i].ParentID], collections[i])
+	}
+	return output
+}
+
+// GroupRequestsByCollection groups the requests by collection, every
+// collection gets a (possibly empty) list.
+func GroupRequestsByCollection(collectionIDs []uint, requests []*models.TeamRequest) map[uint][]*models.TeamRequest {
+	output := map[uint][]*models.TeamRequest{}
+	for _, collectionID := range collectionIDs {
+		output[collectionID] = []*models.TeamRequest{}
+	}
+	for i := range requests {
+		output[requests[i].TeamCollectionID] = append(output[requests[i].TeamCollectionID], requests[i])
+	}
+	return output
+}
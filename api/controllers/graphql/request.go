@@ -3,13 +3,13 @@ package graphql
 import (
 	"context"
 	"encoding/json"
-	"mime/multipart"
 	"net/http"
 	"regexp"
 	"strconv"
 	"strings"
 
 	graphql_context "github.com/jerbob92/hoppscotch-backend/api/controllers/graphql/context"
+	"github.com/jerbob92/hoppscotch-backend/helpers/scalars"
 
 	"github.com/graph-gophers/graphql-go"
 )
@@ -54,12 +54,6 @@ func set(v interface{}, m interface{}, path string) error {
 	return nil
 }
 
-type File struct {
-	File     multipart.File
-	Filename string
-	Size     int64
-}
-
 func Handle(c *graphql_context.Context, exec func(req *Request) *graphql.Response) {
 	c.GinContext.Writer.Header().Set("Content-Type", "application/json")
 
@@ -145,10 +139,11 @@ func Handle(c *graphql_context.Context, exec func(req *Request) *graphql.Respons
 			}
 
 			// Unmarshal uploads
-			var uploads = map[File][]string{}
+			var uploads = map[scalars.Upload][]string{}
 			var uploadsMap = map[string][]string{}
 			if err := json.Unmarshal([]byte(c.GinContext.Request.Form.Get("map")), &uploadsMap); err != nil {
-				panic(err)
+				http.Error(c.GinContext.Writer, "the request form map field doesn't exist or has invalid json data", http.StatusBadRequest)
+				return
 			} else {
 				for key, path := range uploadsMap {
 					file, header, err := c.GinContext.Request.FormFile(key)
@@ -156,7 +151,7 @@ func Handle(c *graphql_context.Context, exec func(req *Request) *graphql.Respons
 						http.Error(c.GinContext.Writer, "Could not access uploaded file", http.StatusInternalServerError)
 						return
 					}
-					uploads[File{
+					uploads[scalars.Upload{
 						File:     file,
 						Size:     header.Size,
 						Filename: header.Filename,
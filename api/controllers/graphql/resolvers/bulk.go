@@ -0,0 +1,727 @@
+package resolvers
+
+import (
+	"context"
+	"errors"
+	"strconv"
+
+	graphql_context "github.com/jerbob92/hoppscotch-backend/api/controllers/graphql/context"
+	"github.com/jerbob92/hoppscotch-backend/models"
+
+	"github.com/graph-gophers/graphql-go"
+	"gorm.io/gorm"
+)
+
+// maxBulkItems is the maximum amount of items in a single bulk operation.
+const maxBulkItems = 1000
+
+const (
+	BulkItemTypeRequest    = "REQUEST"
+	BulkItemTypeCollection = "COLLECTION"
+)
+
+type BulkItemsInput struct {
+	RequestIDs    *[]graphql.ID
+	CollectionIDs *[]graphql.ID
+}
+
+type bulkItem struct {
+	itemType   string
+	id         graphql.ID
+	request    *models.TeamRequest
+	collection *models.TeamCollection
+	err        error
+	newID      *uint
+}
+
+type BulkItemResultResolver struct {
+	item *bulkItem
+}
+
+func (r *BulkItemResultResolver) ID() graphql.ID {
+	return r.item.id
+}
+
+func (r *BulkItemResultResolver) Type() string {
+	return r.item.itemType
+}
+
+func (r *BulkItemResultResolver) Success() bool {
+	return r.item.err == nil
+}
+
+func (r *BulkItemResultResolver) Error() *string {
+	if r.item.err == nil {
+		return nil
+	}
+	message := r.item.err.Error()
+	return &message
+}
+
+func (r *BulkItemResultResolver) NewID() *graphql.ID {
+	if r.item.newID == nil {
+		return nil
+	}
+	id := graphql.ID(strconv.Itoa(int(*r.item.newID)))
+	return &id
+}
+
+type BulkOperationResultResolver struct {
+	applied bool
+	items   []*bulkItem
+}
+
+func (r *BulkOperationResultResolver) Applied() bool {
+	return r.applied
+}
+
+func (r *BulkOperationResultResolver) Items() []*BulkItemResultResolver {
+	itemResolvers := []*BulkItemResultResolver{}
+	for i := range r.items {
+		itemResolvers = append(itemResolvers, &BulkItemResultResolver{item: r.items[i]})
+	}
+	return itemResolvers
+}
+
+func (r *BulkOperationResultResolver) Succeeded() int32 {
+	succeeded := int32(0)
+	for i := range r.items {
+		if r.items[i].err == nil {
+			succeeded++
+		}
+	}
+	return succeeded
+}
+
+func (r *BulkOperationResultResolver) Failed() int32 {
+	return int32(len(r.items)) - r.Succeeded()
+}
+
+// bulkOperation checks all items before anything is changed, so a bulk
+// operation is applied completely or not at all.
+type bulkOperation struct {
+	ctx   context.Context
+	c     *graphql_context.Context
+	roles map[uint]*models.TeamMemberRole
+	items []*bulkItem
+}
+
+func newBulkOperation(ctx context.Context, c *graphql_context.Context, input BulkItemsInput) (*bulkOperation, error) {
+	db := c.GetDB()
+	operation := &bulkOperation{
+		ctx:   ctx,
+		c:     c,
+		roles: map[uint]*models.TeamMemberRole{},
+		items: []*bulkItem{},
+	}
+
+	if input.RequestIDs != nil {
+		for _, id := range *input.RequestIDs {
+			item := &bulkItem{itemType: BulkItemTypeRequest, id: id}
+			request := &models.TeamRequest{}
+			err := db.Model(&models.TeamRequest{}).Where("id = ?", id).First(request).Error
+			if err != nil && err == gorm.ErrRecordNotFound {
+				item.err = errors.New("you do not have access to this request")
+			} else if err != nil {
+				return nil, err
+			} else {
+				item.request = request
+				item.err = operation.checkEditor(request.TeamID)
+			}
+			operation.items = append(operation.items, item)
+		}
+	}
+
+	if input.CollectionIDs != nil {
+		for _, id := range *input.CollectionIDs {
+			item := &bulkItem{itemType: BulkItemTypeCollection, id: id}
+			collection := &models.TeamCollection{}
+			err := db.Model(&models.TeamCollection{}).Where("id = ?", id).First(collection).Error
+			if err != nil && err == gorm.ErrRecordNotFound {
+				item.err = errors.New("you do not have access to this collection")
+			} else if err != nil {
+				return nil, err
+			} else {
+				item.collection = collection
+				item.err = operation.checkEditor(collection.TeamID)
+			}
+			operation.items = append(operation.items, item)
+		}
+	}
+
+	if len(operation.items) == 0 {
+		return nil, errors.New("no requests or collections given")
+	}
+
+	if len(operation.items) > maxBulkItems {
+		return nil, errors.New("a bulk operation can have at most " + strconv.Itoa(maxBulkItems) + " items")
+	}
+
+	return operation, nil
+}
+
+// checkEditor returns an error when the current user can't change the team,
+// the roles are looked up once per team.
+func (o *bulkOperation) checkEditor(teamID uint) error {
+	userRole, ok := o.roles[teamID]
+	if !ok {
+		var err error
+		userRole, err = getUserRoleInTeam(o.ctx, o.c, teamID)
+		if err != nil {
+			return err
+		}
+		o.roles[teamID] = userRole
+	}
+
+	if userRole == nil {
+		return errors.New("you do not have access to this team")
+	}
+
+	if *userRole != models.Owner && *userRole != models.Editor {
+		return errors.New("you are not allowed to change this team")
+	}
+
+	return nil
+}
+
+// checkLocks marks the items with a request that someone else is editing,
+// for collections the requests in the whole tree are checked.
+func (o *bulkOperation) checkLocks() error {
+	db := o.c.GetDB()
+	currentUser, err := o.c.GetUser(o.ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, item := range o.items {
+		if item.err != nil {
+			continue
+		}
+
+		if item.request != nil {
+			item.err = checkRequestLock(o.c, item.request.ID, currentUser.ID, nil)
+		}
+
+		if item.collection != nil {
+			descendantIDs, err := getCollectionTreeIDs(db, item.collection.TeamID, item.collection.ID)
+			if err != nil {
+				return err
+			}
+
+			item.err = checkCollectionLocks(o.c, append([]uint{item.collection.ID}, descendantIDs...), currentUser.ID)
+		}
+	}
+	return nil
+}
+
+func (o *bulkOperation) failed() bool {
+	for i := range o.items {
+		if o.items[i].err != nil {
+			return true
+		}
+	}
+	return false
+}
+
+func (o *bulkOperation) result(applied bool) *BulkOperationResultResolver {
+	return &BulkOperationResultResolver{applied: applied, items: o.items}
+}
+
+// destination loads the collection or team the items are moved or copied to,
+// a parent ID of 0 is the root of the team.
+func (o *bulkOperation) destination(destCollectionID *graphql.ID, destTeamID *graphql.ID) (uint, uint, error) {
+	db := o.c.GetDB()
+
+	if destCollectionID != nil {
+		collection := &models.TeamCollection{}
+		err := db.Model(&models.TeamCollection{}).Where("id = ?", *destCollectionID).First(collection).Error
+		if err != nil && err == gorm.ErrRecordNotFound {
+			return 0, 0, errors.New("you do not have access to the destination collection")
+		}
+		if err != nil {
+			return 0, 0, err
+		}
+
+		if err := o.checkEditor(collection.TeamID); err != nil {
+			return 0, 0, err
+		}
+
+		return collection.TeamID, collection.ID, nil
+	}
+
+	if destTeamID != nil {
+		teamID, err := strconv.Atoi(string(*destTeamID))
+		if err != nil {
+			return 0, 0, errors.New("you do not have access to this team")
+		}
+
+		if err := o.checkEditor(uint(teamID)); err != nil {
+			return 0, 0, err
+		}
+
+		return uint(teamID), 0, nil
+	}
+
+	return 0, 0, errors.New("a destination collection or team is required")
+}
+
+// checkDestination marks the items that can't be placed in the destination:
+// requests need a collection, and a collection can't be placed in itself.
+func (o *bulkOperation) checkDestination(parentID uint) error {
+	db := o.c.GetDB()
+	for _, item := range o.items {
+		if item.err != nil {
+			continue
+		}
+
+		if item.request != nil && parentID == 0 {
+			item.err = errors.New("a request can only be placed in a collection")
+		}
+
+		if item.collection != nil && parentID != 0 {
+			if item.collection.ID == parentID {
+				item.err = errors.New("a collection can not be placed in itself")
+				continue
+			}
+
+			descendantIDs, err := getCollectionTreeIDs(db, item.collection.TeamID, item.collection.ID)
+			if err != nil {
+				return err
+			}
+			for _, descendantID := range descendantIDs {
+				if descendantID == parentID {
+					item.err = errors.New("a collection can not be placed in one of its children")
+					break
+				}
+			}
+		}
+	}
+	return nil
+}
+
+type BulkMoveItemsArgs struct {
+	Items            BulkItemsInput
+	DestCollectionID *graphql.ID
+	DestTeamID       *graphql.ID
+}
+
+// BulkMoveItems moves requests and collections to a collection, collections
+// can also be moved to the root of a team.
+func (b *BaseQuery) BulkMoveItems(ctx context.Context, args *BulkMoveItemsArgs) (*BulkOperationResultResolver, error) {
+	c := b.GetReqC(ctx)
+	operation, err := newBulkOperation(ctx, c, args.Items)
+	if err != nil {
+		return nil, err
+	}
+
+	teamID, parentID, err := operation.destination(args.DestCollectionID, args.DestTeamID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = operation.checkDestination(parentID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = operation.checkLocks()
+	if err != nil {
+		return nil, err
+	}
+
+	if operation.failed() {
+		return operation.result(false), nil
+	}
+
+	events := &eventQueue{}
+	err = c.GetDB().Transaction(func(tx *gorm.DB) error {
+		for _, item := range operation.items {
+			if item.request != nil {
+				err := moveRequestInTransaction(c, tx, events, item.request, teamID, parentID)
+				if err != nil {
+					return err
+				}
+			}
+			if item.collection != nil {
+				err := moveCollectionInTransaction(c, tx, events, item.collection, teamID, parentID)
+				if err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	events.Flush()
+
+	return operation.result(true), nil
+}
+
+func moveRequestInTransaction(c *graphql_context.Context, tx *gorm.DB, events *eventQueue, request *models.TeamRequest, teamID uint, collectionID uint) error {
+	oldTeamID := request.TeamID
+	_, err := updateVersioned(tx, &models.TeamRequest{}, request.ID, nil, map[string]interface{}{
+		"team_id":            teamID,
+		"team_collection_id": collectionID,
+	})
+	if err != nil {
+		return err
+	}
+	if oldTeamID != teamID {
+		err = moveCommentsInTransaction(tx, "team_request_id", []uint{request.ID}, teamID)
+		if err != nil {
+			return err
+		}
+	}
+	request.TeamID = teamID
+	request.TeamCollectionID = collectionID
+	request.Version++
+
+	resolver, err := NewTeamRequestResolver(c, request)
+	if err != nil {
+		return err
+	}
+
+	if oldTeamID != teamID {
+		events.Publish("team:"+strconv.Itoa(int(oldTeamID))+":requests:deleted", graphql.ID(strconv.Itoa(int(request.ID))))
+		events.Publish("team:"+strconv.Itoa(int(teamID))+":requests:added", resolver)
+	} else {
+		events.Publish("team:"+strconv.Itoa(int(teamID))+":requests:updated", resolver)
+	}
+
+	return nil
+}
+
+func moveCollectionInTransaction(c *graphql_context.Context, tx *gorm.DB, events *eventQueue, collection *models.TeamCollection, teamID uint, parentID uint) error {
+	oldTeamID := collection.TeamID
+	_, err := updateVersioned(tx, &models.TeamCollection{}, collection.ID, nil, map[string]interface{}{
+		"team_id":   teamID,
+		"parent_id": parentID,
+	})
+	if err != nil {
+		return err
+	}
+	collection.TeamID = teamID
+	collection.ParentID = parentID
+	collection.Version++
+
+	if oldTeamID == teamID {
+		resolver, err := NewTeamCollectionResolver(c, collection)
+		if err != nil {
+			return err
+		}
+		events.Publish("team:"+strconv.Itoa(int(teamID))+":collections:updated", resolver)
+		return nil
+	}
+
+	// The whole tree moves to the other team.
+	descendantIDs, err := getCollectionTreeIDs(tx, oldTeamID, collection.ID)
+	if err != nil {
+		return err
+	}
+	collectionIDs := append([]uint{collection.ID}, descendantIDs...)
+
+	requestIDs := []uint{}
+	err = tx.Model(&models.TeamRequest{}).Where("team_collection_id IN ?", collectionIDs).Pluck("id", &requestIDs).Error
+	if err != nil {
+		return err
+	}
+
+	if len(descendantIDs) > 0 {
+		err = tx.Model(&models.TeamCollection{}).Where("id IN ?", descendantIDs).Updates(map[string]interface{}{
+			"team_id": teamID,
+			"version": gorm.Expr("version + 1"),
+		}).Error
+		if err != nil {
+			return err
+		}
+	}
+	if len(requestIDs) > 0 {
+		err = tx.Model(&models.TeamRequest{}).Where("id IN ?", requestIDs).Updates(map[string]interface{}{
+			"team_id": teamID,
+			"version": gorm.Expr("version + 1"),
+		}).Error
+		if err != nil {
+			return err
+		}
+	}
+	err = moveCommentsInTransaction(tx, "team_collection_id", collectionIDs, teamID)
+	if err != nil {
+		return err
+	}
+	err = moveCommentsInTransaction(tx, "team_request_id", requestIDs, teamID)
+	if err != nil {
+		return err
+	}
+
+	for _, collectionID := range collectionIDs {
+		events.Publish("team:"+strconv.Itoa(int(oldTeamID))+":collections:removed", graphql.ID(strconv.Itoa(int(collectionID))))
+	}
+	for _, requestID := range requestIDs {
+		events.Publish("team:"+strconv.Itoa(int(oldTeamID))+":requests:deleted", graphql.ID(strconv.Itoa(int(requestID))))
+	}
+
+	movedCollections := []*models.TeamCollection{}
+	err = tx.Model(&models.TeamCollection{}).Where("id IN ?", collectionIDs).Find(&movedCollections).Error
+	if err != nil {
+		return err
+	}
+	// Parents before their children, in the order of the tree.
+	for _, collectionID := range collectionIDs {
+		for i := range movedCollections {
+			if movedCollections[i].ID != collectionID {
+				continue
+			}
+			resolver, err := NewTeamCollectionResolver(c, movedCollections[i])
+			if err != nil {
+				return err
+			}
+			events.Publish("team:"+strconv.Itoa(int(teamID))+":collections:added", resolver)
+		}
+	}
+
+	movedRequests := []*models.TeamRequest{}
+	err = tx.Model(&models.TeamRequest{}).Where("id IN ?", requestIDs).Find(&movedRequests).Error
+	if err != nil {
+		return err
+	}
+	for i := range movedRequests {
+		resolver, err := NewTeamRequestResolver(c, movedRequests[i])
+		if err != nil {
+			return err
+		}
+		events.Publish("team:"+strconv.Itoa(int(teamID))+":requests:added", resolver)
+	}
+
+	return nil
+}
+
+type BulkDeleteItemsArgs struct {
+	Items BulkItemsInput
+}
+
+// BulkDeleteItems deletes requests and collections, a collection is deleted
+// with everything in it.
+func (b *BaseQuery) BulkDeleteItems(ctx context.Context, args *BulkDeleteItemsArgs) (*BulkOperationResultResolver, error) {
+	c := b.GetReqC(ctx)
+	operation, err := newBulkOperation(ctx, c, args.Items)
+	if err != nil {
+		return nil, err
+	}
+
+	err = operation.checkLocks()
+	if err != nil {
+		return nil, err
+	}
+
+	if operation.failed() {
+		return operation.result(false), nil
+	}
+
+	events := &eventQueue{}
+	deletedRequests := map[uint]bool{}
+	err = c.GetDB().Transaction(func(tx *gorm.DB) error {
+		deletedCollections := map[uint]bool{}
+
+		deleteRequests := func(teamID uint, requestIDs []uint) error {
+			if len(requestIDs) == 0 {
+				return nil
+			}
+			err := deleteTeamRequestsInTransaction(tx, events, requestIDs)
+			if err != nil {
+				return err
+			}
+			for _, requestID := range requestIDs {
+				if !deletedRequests[requestID] {
+					deletedRequests[requestID] = true
+					events.Publish("team:"+strconv.Itoa(int(teamID))+":requests:deleted", graphql.ID(strconv.Itoa(int(requestID))))
+				}
+			}
+			return nil
+		}
+
+		for _, item := range operation.items {
+			if item.collection == nil || deletedCollections[item.collection.ID] {
+				continue
+			}
+
+			descendantIDs, err := getCollectionTreeIDs(tx, item.collection.TeamID, item.collection.ID)
+			if err != nil {
+				return err
+			}
+			collectionIDs := append([]uint{item.collection.ID}, descendantIDs...)
+
+			// Collections and requests that were deleted with an earlier item
+			// are not found again.
+			requestIDs, err := deleteTeamCollectionsInTransaction(tx, events, item.collection.TeamID, collectionIDs)
+			if err != nil {
+				return err
+			}
+			for _, requestID := range requestIDs {
+				deletedRequests[requestID] = true
+			}
+			for _, collectionID := range collectionIDs {
+				deletedCollections[collectionID] = true
+			}
+		}
+
+		for _, item := range operation.items {
+			if item.request == nil || deletedRequests[item.request.ID] {
+				continue
+			}
+			err := deleteRequests(item.request.TeamID, []uint{item.request.ID})
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	events.Flush()
+
+	for requestID := range deletedRequests {
+		requestLocks.Remove(requestID)
+	}
+
+	return operation.result(true), nil
+}
+
+type BulkCopyItemsArgs struct {
+	Items            BulkItemsInput
+	DestCollectionID *graphql.ID
+	DestTeamID       *graphql.ID
+}
+
+// BulkCopyItems copies requests and collections to a collection, collections
+// can also be copied to the root of a team. Collections are copied with
+// everything in it, requests with their examples.
+func (b *BaseQuery) BulkCopyItems(ctx context.Context, args *BulkCopyItemsArgs) (*BulkOperationResultResolver, error) {
+	c := b.GetReqC(ctx)
+	operation, err := newBulkOperation(ctx, c, args.Items)
+	if err != nil {
+		return nil, err
+	}
+
+	teamID, parentID, err := operation.destination(args.DestCollectionID, args.DestTeamID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = operation.checkDestination(parentID)
+	if err != nil {
+		return nil, err
+	}
+
+	if operation.failed() {
+		return operation.result(false), nil
+	}
+
+	events := &eventQueue{}
+	err = c.GetDB().Transaction(func(tx *gorm.DB) error {
+		for _, item := range operation.items {
+			if item.request != nil {
+				newRequest, err := copyRequestInTransaction(c, tx, events, item.request, teamID, parentID)
+				if err != nil {
+					return err
+				}
+				item.newID = &newRequest.ID
+			}
+			if item.collection != nil {
+				newCollection, err := copyCollectionInTransaction(c, tx, events, item.collection, teamID, parentID)
+				if err != nil {
+					return err
+				}
+				item.newID = &newCollection.ID
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	events.Flush()
+
+	return operation.result(true), nil
+}
+
+func copyRequestInTransaction(c *graphql_context.Context, tx *gorm.DB, events *eventQueue, request *models.TeamRequest, teamID uint, collectionID uint) (*models.TeamRequest, error) {
+	newRequest := &models.TeamRequest{
+		TeamID:           teamID,
+		TeamCollectionID: collectionID,
+		Title:            request.Title,
+		Request:          request.Request,
+	}
+	err := tx.Save(newRequest).Error
+	if err != nil {
+		return nil, err
+	}
+
+	err = copyRequestExamples(tx, request.ID, newRequest.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	resolver, err := NewTeamRequestResolver(c, newRequest)
+	if err != nil {
+		return nil, err
+	}
+
+	events.Publish("team:"+strconv.Itoa(int(teamID))+":requests:added", resolver)
+
+	return newRequest, nil
+}
+
+func copyCollectionInTransaction(c *graphql_context.Context, tx *gorm.DB, events *eventQueue, collection *models.TeamCollection, teamID uint, parentID uint) (*models.TeamCollection, error) {
+	children := []*models.TeamCollection{}
+	err := tx.Model(&models.TeamCollection{}).Where("team_id = ? AND parent_id = ?", collection.TeamID, collection.ID).Order("id").Find(&children).Error
+	if err != nil {
+		return nil, err
+	}
+
+	requests := []*models.TeamRequest{}
+	err = tx.Model(&models.TeamRequest{}).Where("team_collection_id = ?", collection.ID).Order("id").Find(&requests).Error
+	if err != nil {
+		return nil, err
+	}
+
+	newCollection := &models.TeamCollection{
+		TeamID:     teamID,
+		ParentID:   parentID,
+		Title:      collection.Title,
+		Properties: collection.Properties,
+	}
+	err = tx.Save(newCollection).Error
+	if err != nil {
+		return nil, err
+	}
+
+	resolver, err := NewTeamCollectionResolver(c, newCollection)
+	if err != nil {
+		return nil, err
+	}
+
+	events.Publish("team:"+strconv.Itoa(int(teamID))+":collections:added", resolver)
+
+	for i := range requests {
+		_, err := copyRequestInTransaction(c, tx, events, requests[i], teamID, newCollection.ID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for i := range children {
+		_, err := copyCollectionInTransaction(c, tx, events, children[i], teamID, newCollection.ID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return newCollection, nil
+}
@@ -27,3 +27,30 @@ func subscribeUntilDone(ctx context.Context, topic string, eventHandler interfac
 
 	return nil
 }
+
+// eventQueue collects events that may only be published once the transaction
+// that caused them has been committed.
+type eventQueue struct {
+	events []queuedEvent
+}
+
+type queuedEvent struct {
+	topic string
+	args  []interface{}
+}
+
+func (q *eventQueue) Publish(topic string, args ...interface{}) {
+	q.events = append(q.events, queuedEvent{topic: topic, args: args})
+}
+
+// Flush publishes the queued events in order.
+func (q *eventQueue) Flush() {
+	events := q.events
+	q.events = nil
+
+	go func() {
+		for i := range events {
+			bus.Publish(events[i].topic, events[i].args...)
+		}
+	}()
+}
@@ -0,0 +1,23 @@
+package resolvers
+
+import (
+	"context"
+
+	"github.com/jerbob92/hoppscotch-backend/helpers/codegen"
+
+	"github.com/graph-gophers/graphql-go"
+)
+
+type CodeSnippetArgs struct {
+	Language      string
+	EnvironmentID *graphql.ID
+}
+
+func (r *TeamRequestResolver) CodeSnippet(ctx context.Context, args *CodeSnippetArgs) (string, error) {
+	prepared, err := prepareTeamRequest(r.c, r.team_request, args.EnvironmentID)
+	if err != nil {
+		return "", err
+	}
+
+	return codegen.Render(args.Language, prepared)
+}
@@ -0,0 +1,172 @@
+package resolvers
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jerbob92/hoppscotch-backend/config"
+	"github.com/jerbob92/hoppscotch-backend/helpers/executor"
+	"github.com/jerbob92/hoppscotch-backend/helpers/hoppscotch"
+	"github.com/jerbob92/hoppscotch-backend/helpers/runner"
+	"github.com/jerbob92/hoppscotch-backend/models"
+
+	"github.com/graph-gophers/graphql-go"
+	"gorm.io/gorm"
+)
+
+type CollectionRunReportResolver struct {
+	report *runner.Report
+}
+
+func (r *CollectionRunReportResolver) Passed() bool {
+	return r.report.Passed
+}
+
+func (r *CollectionRunReportResolver) Total() int32 {
+	return int32(r.report.Total)
+}
+
+func (r *CollectionRunReportResolver) Failed() int32 {
+	return int32(r.report.Failed)
+}
+
+func (r *CollectionRunReportResolver) Duration() float64 {
+	return r.report.Duration
+}
+
+func (r *CollectionRunReportResolver) JSON() (string, error) {
+	return r.report.JSON()
+}
+
+func (r *CollectionRunReportResolver) JUnit() (string, error) {
+	return r.report.JUnit()
+}
+
+// collectionRunSteps returns the requests of the collection and its child
+// collections in the order they are run: first the requests of a
+// collection, then its child collections.
+func collectionRunSteps(db *gorm.DB, collection *models.TeamCollection, properties hoppscotch.CollectionProperties, path string) ([]runner.Step, error) {
+	steps := []runner.Step{}
+
+	requests := []*models.TeamRequest{}
+	err := db.Model(&models.TeamRequest{}).Where("team_id = ? AND team_collection_id = ?", collection.TeamID, collection.ID).Order("id").Find(&requests).Error
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range requests {
+		request, err := hoppscotch.ParseRESTRequest(currentRequestJSON(requests[i]))
+		if err != nil {
+			return nil, err
+		}
+		if request.Name == "" {
+			request.Name = requests[i].Title
+		}
+		properties.ApplyTo(request)
+
+		steps = append(steps, runner.Step{
+			ID:        requests[i].ID,
+			Path:      path,
+			Request:   request,
+			Variables: hoppscotch.VariableMap(properties.Variables),
+		})
+	}
+
+	children := []*models.TeamCollection{}
+	err = db.Model(&models.TeamCollection{}).Where("team_id = ? AND parent_id = ?", collection.TeamID, collection.ID).Order("id").Find(&children).Error
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range children {
+		childProperties, err := hoppscotch.ParseCollectionProperties(children[i].Properties)
+		if err != nil {
+			return nil, err
+		}
+
+		childSteps, err := collectionRunSteps(db, children[i], properties.Inherit(childProperties), path+" / "+children[i].Title)
+		if err != nil {
+			return nil, err
+		}
+		steps = append(steps, childSteps...)
+	}
+
+	return steps, nil
+}
+
+// RunTeamCollection runs the requests of the collection and its child
+// collections, with the variables of the environment when it's given.
+func RunTeamCollection(ctx context.Context, db *gorm.DB, collection *models.TeamCollection, environment *models.TeamEnvironment, options executor.Options) (*runner.Report, error) {
+	properties, err := getEffectiveCollectionProperties(db, collection)
+	if err != nil {
+		return nil, err
+	}
+
+	steps, err := collectionRunSteps(db, collection, properties, collection.Title)
+	if err != nil {
+		return nil, err
+	}
+
+	variables := map[string]string{}
+	if environment != nil {
+		environmentVariables, err := hoppscotch.ParseEnvironmentVariables(environment.Variables)
+		if err != nil {
+			return nil, err
+		}
+		variables = hoppscotch.VariableMap(environmentVariables)
+	}
+
+	return runner.Run(ctx, collection.Title, steps, variables, options), nil
+}
+
+type RunCollectionArgs struct {
+	CollectionID  graphql.ID
+	EnvironmentID *graphql.ID
+}
+
+func (b *BaseQuery) RunCollection(ctx context.Context, args *RunCollectionArgs) (*CollectionRunReportResolver, error) {
+	c := b.GetReqC(ctx)
+	db := c.GetDB()
+
+	collection := &models.TeamCollection{}
+	err := db.Model(&models.TeamCollection{}).Where("id = ?", args.CollectionID).First(collection).Error
+	if err != nil && err == gorm.ErrRecordNotFound {
+		return nil, errors.New("you do not have access to this collection")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	userRole, err := getUserRoleInTeam(ctx, c, collection.TeamID)
+	if err != nil {
+		return nil, err
+	}
+
+	if userRole == nil {
+		return nil, errors.New("you do not have access to this collection")
+	}
+
+	var environment *models.TeamEnvironment
+	if args.EnvironmentID != nil {
+		environment = &models.TeamEnvironment{}
+		err := db.Model(&models.TeamEnvironment{}).Where("id = ? AND team_id = ?", args.EnvironmentID, collection.TeamID).First(environment).Error
+		if err != nil && err == gorm.ErrRecordNotFound {
+			return nil, errors.New("you do not have access to this environment")
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	options, err := config.ExecutorOptions()
+	if err != nil {
+		return nil, err
+	}
+
+	report, err := RunTeamCollection(ctx, db, collection, environment, options)
+	if err != nil {
+		return nil, err
+	}
+
+	return &CollectionRunReportResolver{report: report}, nil
+}
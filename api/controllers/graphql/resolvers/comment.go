@@ -0,0 +1,616 @@
+package resolvers
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	graphql_context "github.com/jerbob92/hoppscotch-backend/api/controllers/graphql/context"
+	"github.com/jerbob92/hoppscotch-backend/models"
+
+	"github.com/graph-gophers/graphql-go"
+	"github.com/sanae10001/graphql-go-extension-scalars"
+	"gorm.io/gorm"
+)
+
+type CommentResolver struct {
+	c       *graphql_context.Context
+	comment *models.Comment
+}
+
+func NewCommentResolver(c *graphql_context.Context, comment *models.Comment) (*CommentResolver, error) {
+	if comment == nil {
+		return nil, nil
+	}
+
+	return &CommentResolver{c: c, comment: comment}, nil
+}
+
+func (r *CommentResolver) ID() (graphql.ID, error) {
+	id := graphql.ID(strconv.Itoa(int(r.comment.ID)))
+	return id, nil
+}
+
+func (r *CommentResolver) TeamID() (graphql.ID, error) {
+	return graphql.ID(strconv.Itoa(int(r.comment.TeamID))), nil
+}
+
+func (r *CommentResolver) RequestID() (*graphql.ID, error) {
+	if r.comment.TeamRequestID == nil {
+		return nil, nil
+	}
+	id := graphql.ID(strconv.Itoa(int(*r.comment.TeamRequestID)))
+	return &id, nil
+}
+
+func (r *CommentResolver) CollectionID() (*graphql.ID, error) {
+	if r.comment.TeamCollectionID == nil {
+		return nil, nil
+	}
+	id := graphql.ID(strconv.Itoa(int(*r.comment.TeamCollectionID)))
+	return &id, nil
+}
+
+func (r *CommentResolver) ParentID() (*graphql.ID, error) {
+	if r.comment.ParentID == nil {
+		return nil, nil
+	}
+	id := graphql.ID(strconv.Itoa(int(*r.comment.ParentID)))
+	return &id, nil
+}
+
+func (r *CommentResolver) Body() (string, error) {
+	return r.comment.Body, nil
+}
+
+func (r *CommentResolver) Author() (*UserResolver, error) {
+	author, found, err := r.c.GetLoaders().User.Load(r.comment.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, nil
+	}
+	return NewUserResolver(r.c, author)
+}
+
+func (r *CommentResolver) Mentions() ([]*UserResolver, error) {
+	db := r.c.GetDB()
+	userIDs := []uint{}
+	err := db.Model(&models.CommentMention{}).Where("comment_id = ?", r.comment.ID).Order("id").Pluck("user_id", &userIDs).Error
+	if err != nil {
+		return nil, err
+	}
+
+	userResolvers := []*UserResolver{}
+	for _, userID := range userIDs {
+		user, found, err := r.c.GetLoaders().User.Load(userID)
+		if err != nil {
+			return nil, err
+		}
+		if !found {
+			continue
+		}
+		userResolver, err := NewUserResolver(r.c, user)
+		if err != nil {
+			return nil, err
+		}
+		userResolvers = append(userResolvers, userResolver)
+	}
+
+	return userResolvers, nil
+}
+
+func (r *CommentResolver) Replies() ([]*CommentResolver, error) {
+	if r.comment.ParentID != nil {
+		return []*CommentResolver{}, nil
+	}
+
+	db := r.c.GetDB()
+	replies := []*models.Comment{}
+	err := db.Model(&models.Comment{}).Where("parent_id = ?", r.comment.ID).Order("id").Find(&replies).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return newCommentResolvers(r.c, replies)
+}
+
+func (r *CommentResolver) Resolved() (bool, error) {
+	return r.comment.ResolvedAt != nil, nil
+}
+
+func (r *CommentResolver) ResolvedBy() (*UserResolver, error) {
+	if r.comment.ResolvedByID == nil {
+		return nil, nil
+	}
+
+	user, found, err := r.c.GetLoaders().User.Load(*r.comment.ResolvedByID)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, nil
+	}
+	return NewUserResolver(r.c, user)
+}
+
+func (r *CommentResolver) ResolvedOn() (*scalars.DateTime, error) {
+	if r.comment.ResolvedAt == nil {
+		return nil, nil
+	}
+	return scalars.NewDateTime(*r.comment.ResolvedAt), nil
+}
+
+func (r *CommentResolver) CreatedOn() (scalars.DateTime, error) {
+	return *scalars.NewDateTime(r.comment.CreatedAt), nil
+}
+
+func (r *CommentResolver) EditedOn() (*scalars.DateTime, error) {
+	if r.comment.EditedAt == nil {
+		return nil, nil
+	}
+	return scalars.NewDateTime(*r.comment.EditedAt), nil
+}
+
+func newCommentResolvers(c *graphql_context.Context, comments []*models.Comment) ([]*CommentResolver, error) {
+	commentResolvers := []*CommentResolver{}
+	for i := range comments {
+		newResolver, err := NewCommentResolver(c, comments[i])
+		if err != nil {
+			return nil, err
+		}
+		commentResolvers = append(commentResolvers, newResolver)
+	}
+	return commentResolvers, nil
+}
+
+type CommentsArgs struct {
+	IncludeResolved *bool
+}
+
+// threadsQuery returns the query for the threads on a request or collection,
+// resolved threads are left out unless they are asked for.
+func threadsQuery(db *gorm.DB, args *CommentsArgs) *gorm.DB {
+	query := db.Model(&models.Comment{}).Where("parent_id IS NULL")
+	if args.IncludeResolved == nil || !*args.IncludeResolved {
+		query = query.Where("resolved_at IS NULL")
+	}
+	return query.Order("id")
+}
+
+func (r *TeamRequestResolver) Comments(args *CommentsArgs) ([]*CommentResolver, error) {
+	comments := []*models.Comment{}
+	err := threadsQuery(r.c.GetDB(), args).Where("team_request_id = ?", r.team_request.ID).Find(&comments).Error
+	if err != nil {
+		return nil, err
+	}
+	return newCommentResolvers(r.c, comments)
+}
+
+func (r *TeamCollectionResolver) Comments(args *CommentsArgs) ([]*CommentResolver, error) {
+	comments := []*models.Comment{}
+	err := threadsQuery(r.c.GetDB(), args).Where("team_collection_id = ?", r.team_collection.ID).Find(&comments).Error
+	if err != nil {
+		return nil, err
+	}
+	return newCommentResolvers(r.c, comments)
+}
+
+// getMentionedMembers returns the IDs of the mentioned users, they must be
+// members of the team.
+func getMentionedMembers(db *gorm.DB, teamID uint, mentions *[]graphql.ID) ([]uint, error) {
+	userIDs := []uint{}
+	if mentions == nil || len(*mentions) == 0 {
+		return userIDs, nil
+	}
+
+	for _, uid := range *mentions {
+		member := &models.TeamMember{}
+		err := db.Model(&models.TeamMember{}).
+			Joins("INNER JOIN users ON users.id = team_members.user_id AND users.deleted_at IS NULL").
+			Where("team_members.team_id = ? AND users.fb_uid = ?", teamID, string(uid)).
+			First(member).Error
+		if err != nil && err == gorm.ErrRecordNotFound {
+			return nil, errors.New("mentioned user " + string(uid) + " is not a member of this team")
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		duplicate := false
+		for _, userID := range userIDs {
+			if userID == member.UserID {
+				duplicate = true
+				break
+			}
+		}
+		if !duplicate {
+			userIDs = append(userIDs, member.UserID)
+		}
+	}
+
+	return userIDs, nil
+}
+
+// saveComment stores the comment with its mentions, existing mentions of the
+// comment are replaced.
+func saveComment(db *gorm.DB, comment *models.Comment, mentionedUserIDs []uint) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Save(comment).Error
+		if err != nil {
+			return err
+		}
+
+		err = tx.Unscoped().Where("comment_id = ?", comment.ID).Delete(&models.CommentMention{}).Error
+		if err != nil {
+			return err
+		}
+
+		for _, userID := range mentionedUserIDs {
+			err := tx.Create(&models.CommentMention{CommentID: comment.ID, UserID: userID}).Error
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// addComment adds a comment to a request, a collection or a thread. Every
+// member of the team may comment.
+func addComment(ctx context.Context, c *graphql_context.Context, comment *models.Comment, body string, mentions *[]graphql.ID) (*CommentResolver, error) {
+	db := c.GetDB()
+
+	userRole, err := getUserRoleInTeam(ctx, c, comment.TeamID)
+	if err != nil {
+		return nil, err
+	}
+
+	if userRole == nil {
+		return nil, errors.New("you do not have access to comment in this team")
+	}
+
+	currentUser, err := c.GetUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	comment.Body = strings.TrimSpace(body)
+	if comment.Body == "" {
+		return nil, errors.New("a comment can not be empty")
+	}
+	comment.UserID = currentUser.ID
+
+	mentionedUserIDs, err := getMentionedMembers(db, comment.TeamID, mentions)
+	if err != nil {
+		return nil, err
+	}
+
+	err = saveComment(db, comment, mentionedUserIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	resolver, err := NewCommentResolver(c, comment)
+	if err != nil {
+		return nil, err
+	}
+
+	go bus.Publish("team:"+strconv.Itoa(int(comment.TeamID))+":comments:added", resolver)
+
+	return resolver, nil
+}
+
+type AddCommentToRequestArgs struct {
+	RequestID graphql.ID
+	Body      string
+	Mentions  *[]graphql.ID
+}
+
+func (b *BaseQuery) AddCommentToRequest(ctx context.Context, args *AddCommentToRequestArgs) (*CommentResolver, error) {
+	c := b.GetReqC(ctx)
+	db := c.GetDB()
+	request := &models.TeamRequest{}
+	err := db.Model(&models.TeamRequest{}).Where("id = ?", args.RequestID).First(request).Error
+	if err != nil && err == gorm.ErrRecordNotFound {
+		return nil, errors.New("you do not have access to this request")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return addComment(ctx, c, &models.Comment{
+		TeamID:        request.TeamID,
+		TeamRequestID: &request.ID,
+	}, args.Body, args.Mentions)
+}
+
+type AddCommentToCollectionArgs struct {
+	CollectionID graphql.ID
+	Body         string
+	Mentions     *[]graphql.ID
+}
+
+func (b *BaseQuery) AddCommentToCollection(ctx context.Context, args *AddCommentToCollectionArgs) (*CommentResolver, error) {
+	c := b.GetReqC(ctx)
+	db := c.GetDB()
+	collection := &models.TeamCollection{}
+	err := db.Model(&models.TeamCollection{}).Where("id = ?", args.CollectionID).First(collection).Error
+	if err != nil && err == gorm.ErrRecordNotFound {
+		return nil, errors.New("you do not have access to this collection")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return addComment(ctx, c, &models.Comment{
+		TeamID:           collection.TeamID,
+		TeamCollectionID: &collection.ID,
+	}, args.Body, args.Mentions)
+}
+
+// getComment loads the comment, when the current user is a member of its
+// team.
+func getComment(ctx context.Context, c *graphql_context.Context, commentID graphql.ID) (*models.Comment, error) {
+	db := c.GetDB()
+	comment := &models.Comment{}
+	err := db.Model(&models.Comment{}).Where("id = ?", commentID).First(comment).Error
+	if err != nil && err == gorm.ErrRecordNotFound {
+		return nil, errors.New("you do not have access to this comment")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	userRole, err := getUserRoleInTeam(ctx, c, comment.TeamID)
+	if err != nil {
+		return nil, err
+	}
+
+	if userRole == nil {
+		return nil, errors.New("you do not have access to this comment")
+	}
+
+	return comment, nil
+}
+
+type ReplyToCommentArgs struct {
+	CommentID graphql.ID
+	Body      string
+	Mentions  *[]graphql.ID
+}
+
+func (b *BaseQuery) ReplyToComment(ctx context.Context, args *ReplyToCommentArgs) (*CommentResolver, error) {
+	c := b.GetReqC(ctx)
+	comment, err := getComment(ctx, c, args.CommentID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Threads are one level deep, a reply to a reply is added to the thread.
+	threadID := comment.ID
+	if comment.ParentID != nil {
+		threadID = *comment.ParentID
+	}
+
+	return addComment(ctx, c, &models.Comment{
+		TeamID:           comment.TeamID,
+		TeamRequestID:    comment.TeamRequestID,
+		TeamCollectionID: comment.TeamCollectionID,
+		ParentID:         &threadID,
+	}, args.Body, args.Mentions)
+}
+
+type EditCommentArgs struct {
+	CommentID graphql.ID
+	Body      string
+	Mentions  *[]graphql.ID
+}
+
+func (b *BaseQuery) EditComment(ctx context.Context, args *EditCommentArgs) (*CommentResolver, error) {
+	c := b.GetReqC(ctx)
+	db := c.GetDB()
+	comment, err := getComment(ctx, c, args.CommentID)
+	if err != nil {
+		return nil, err
+	}
+
+	currentUser, err := c.GetUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if comment.UserID != currentUser.ID {
+		return nil, errors.New("you are not allowed to edit a comment of someone else")
+	}
+
+	comment.Body = strings.TrimSpace(args.Body)
+	if comment.Body == "" {
+		return nil, errors.New("a comment can not be empty")
+	}
+	now := time.Now()
+	comment.EditedAt = &now
+
+	mentionedUserIDs, err := getMentionedMembers(db, comment.TeamID, args.Mentions)
+	if err != nil {
+		return nil, err
+	}
+
+	err = saveComment(db, comment, mentionedUserIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	resolver, err := NewCommentResolver(c, comment)
+	if err != nil {
+		return nil, err
+	}
+
+	go bus.Publish("team:"+strconv.Itoa(int(comment.TeamID))+":comments:updated", resolver)
+
+	return resolver, nil
+}
+
+type DeleteCommentArgs struct {
+	CommentID graphql.ID
+}
+
+func (b *BaseQuery) DeleteComment(ctx context.Context, args *DeleteCommentArgs) (bool, error) {
+	c := b.GetReqC(ctx)
+	db := c.GetDB()
+	comment, err := getComment(ctx, c, args.CommentID)
+	if err != nil {
+		return false, err
+	}
+
+	currentUser, err := c.GetUser(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	if comment.UserID != currentUser.ID {
+		return false, errors.New("you are not allowed to delete a comment of someone else")
+	}
+
+	// Deleting the first comment of a thread deletes the thread.
+	err = db.Where("id = ? OR parent_id = ?", comment.ID, comment.ID).Delete(&models.Comment{}).Error
+	if err != nil {
+		return false, err
+	}
+
+	go bus.Publish("team:"+strconv.Itoa(int(comment.TeamID))+":comments:deleted", graphql.ID(strconv.Itoa(int(comment.ID))))
+
+	return true, nil
+}
+
+type ResolveCommentArgs struct {
+	CommentID graphql.ID
+}
+
+// setCommentResolved resolves or unresolves the thread of the comment.
+func setCommentResolved(ctx context.Context, c *graphql_context.Context, commentID graphql.ID, resolved bool) (*CommentResolver, error) {
+	db := c.GetDB()
+	comment, err := getComment(ctx, c, commentID)
+	if err != nil {
+		return nil, err
+	}
+
+	if comment.ParentID != nil {
+		threadID := *comment.ParentID
+		comment = &models.Comment{}
+		err := db.Model(&models.Comment{}).Where("id = ?", threadID).First(comment).Error
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	currentUser, err := c.GetUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	updates := map[string]interface{}{
+		"resolved_at":    nil,
+		"resolved_by_id": nil,
+	}
+	if resolved {
+		updates["resolved_at"] = time.Now()
+		updates["resolved_by_id"] = currentUser.ID
+	}
+
+	err = db.Model(comment).Updates(updates).Error
+	if err != nil {
+		return nil, err
+	}
+
+	err = db.Model(&models.Comment{}).Where("id = ?", comment.ID).First(comment).Error
+	if err != nil {
+		return nil, err
+	}
+
+	resolver, err := NewCommentResolver(c, comment)
+	if err != nil {
+		return nil, err
+	}
+
+	go bus.Publish("team:"+strconv.Itoa(int(comment.TeamID))+":comments:resolved", resolver)
+
+	return resolver, nil
+}
+
+func (b *BaseQuery) ResolveComment(ctx context.Context, args *ResolveCommentArgs) (*CommentResolver, error) {
+	c := b.GetReqC(ctx)
+	return setCommentResolved(ctx, c, args.CommentID, true)
+}
+
+func (b *BaseQuery) UnresolveComment(ctx context.Context, args *ResolveCommentArgs) (*CommentResolver, error) {
+	c := b.GetReqC(ctx)
+	return setCommentResolved(ctx, c, args.CommentID, false)
+}
+
+// subscribeToComments subscribes to a comment topic of the team.
+func subscribeToComments(ctx context.Context, c *graphql_context.Context, teamID graphql.ID, event string) (<-chan *CommentResolver, error) {
+	userRole, err := getUserRoleInTeam(ctx, c, teamID)
+	if err != nil {
+		return nil, err
+	}
+	if userRole == nil {
+		return nil, errors.New("no access to team")
+	}
+
+	parsedTeamID, _ := strconv.Atoi(string(teamID))
+	notificationChannel := make(chan *CommentResolver)
+	eventHandler := func(resolver *CommentResolver) {
+		notificationChannel <- &CommentResolver{c: eventContext(c), comment: resolver.comment}
+	}
+
+	err = subscribeUntilDone(ctx, "team:"+strconv.Itoa(parsedTeamID)+":comments:"+event, eventHandler)
+	if err != nil {
+		return nil, err
+	}
+
+	return notificationChannel, nil
+}
+
+func (b *BaseQuery) CommentAdded(ctx context.Context, args *SubscriptionArgs) (<-chan *CommentResolver, error) {
+	c := b.GetReqC(ctx)
+	return subscribeToComments(ctx, c, args.TeamID, "added")
+}
+
+func (b *BaseQuery) CommentUpdated(ctx context.Context, args *SubscriptionArgs) (<-chan *CommentResolver, error) {
+	c := b.GetReqC(ctx)
+	return subscribeToComments(ctx, c, args.TeamID, "updated")
+}
+
+func (b *BaseQuery) CommentResolved(ctx context.Context, args *SubscriptionArgs) (<-chan *CommentResolver, error) {
+	c := b.GetReqC(ctx)
+	return subscribeToComments(ctx, c, args.TeamID, "resolved")
+}
+
+func (b *BaseQuery) CommentDeleted(ctx context.Context, args *SubscriptionArgs) (<-chan graphql.ID, error) {
+	c := b.GetReqC(ctx)
+	userRole, err := getUserRoleInTeam(ctx, c, args.TeamID)
+	if err != nil {
+		return nil, err
+	}
+	if userRole == nil {
+		return nil, errors.New("no access to team")
+	}
+
+	teamID, _ := strconv.Atoi(string(args.TeamID))
+	notificationChannel := make(chan graphql.ID)
+	eventHandler := func(id graphql.ID) {
+		notificationChannel <- id
+	}
+
+	err = subscribeUntilDone(ctx, "team:"+strconv.Itoa(teamID)+":comments:deleted", eventHandler)
+	if err != nil {
+		return nil, err
+	}
+
+	return notificationChannel, nil
+}
@@ -0,0 +1,49 @@
+package resolvers
+
+import (
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+// ConflictError is returned when a write expected a version of a record that
+// is no longer the current version. The current state is sent in the error
+// extensions, so the client can merge without fetching it again.
+type ConflictError struct {
+	resource       string
+	currentVersion uint
+	current        map[string]interface{}
+}
+
+func (e *ConflictError) Error() string {
+	return fmt.Sprintf("the %s was changed by someone else, it is now at version %d", e.resource, e.currentVersion)
+}
+
+func (e *ConflictError) Extensions() map[string]interface{} {
+	return map[string]interface{}{
+		"code":           "CONFLICT",
+		"resource":       e.resource,
+		"currentVersion": e.currentVersion,
+		"current":        e.current,
+	}
+}
+
+// updateVersioned updates the columns of the record with the given ID and
+// increments its version. When an expected version is given, the record is
+// only updated when it is still at that version, and false is returned when
+// it isn't.
+func updateVersioned(db *gorm.DB, model interface{}, id uint, expectedVersion *int32, updates map[string]interface{}) (bool, error) {
+	updates["version"] = gorm.Expr("version + 1")
+
+	query := db.Model(model).Where("id = ?", id)
+	if expectedVersion != nil {
+		query = query.Where("version = ?", *expectedVersion)
+	}
+
+	result := query.Updates(updates)
+	if result.Error != nil {
+		return false, result.Error
+	}
+
+	return result.RowsAffected > 0, nil
+}
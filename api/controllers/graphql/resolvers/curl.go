@@ -0,0 +1,102 @@
+package resolvers
+
+import (
+	"context"
+	"errors"
+	"strconv"
+
+	"github.com/jerbob92/hoppscotch-backend/helpers/curl"
+	"github.com/jerbob92/hoppscotch-backend/models"
+
+	"github.com/graph-gophers/graphql-go"
+	"gorm.io/gorm"
+)
+
+type AsCurlArgs struct {
+	EnvironmentID *graphql.ID
+}
+
+func (r *TeamRequestResolver) AsCurl(ctx context.Context, args *AsCurlArgs) (string, error) {
+	prepared, err := prepareTeamRequest(r.c, r.team_request, args.EnvironmentID)
+	if err != nil {
+		return "", err
+	}
+
+	return curl.Render(prepared), nil
+}
+
+type ImportRequestFromCurlArgs struct {
+	CollectionID graphql.ID
+	Curl         string
+	Title        *string
+}
+
+func (b *BaseQuery) ImportRequestFromCurl(ctx context.Context, args *ImportRequestFromCurlArgs) (*TeamRequestResolver, error) {
+	c := b.GetReqC(ctx)
+	db := c.GetDB()
+	collection := &models.TeamCollection{}
+	err := db.Model(&models.TeamCollection{}).Where("id = ?", args.CollectionID).First(collection).Error
+	if err != nil && err == gorm.ErrRecordNotFound {
+		return nil, errors.New("you do not have access to this collection")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	userRole, err := getUserRoleInTeam(ctx, c, collection.TeamID)
+	if err != nil {
+		return nil, err
+	}
+
+	if userRole == nil {
+		return nil, errors.New("you do not have access to this collection")
+	}
+
+	if *userRole == models.Owner || *userRole == models.Editor {
+		currentUser, err := c.GetUser(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		request, err := curl.Parse(args.Curl)
+		if err != nil {
+			return nil, err
+		}
+
+		if args.Title != nil && *args.Title != "" {
+			request.Name = *args.Title
+		}
+
+		requestJSON, err := request.JSON()
+		if err != nil {
+			return nil, err
+		}
+
+		normalized, err := normalizeTeamRequest(requestJSON, request.Endpoint)
+		if err != nil {
+			return nil, err
+		}
+
+		newRequest := &models.TeamRequest{
+			TeamCollectionID: collection.ID,
+			TeamID:           collection.TeamID,
+			Title:            normalized.Name,
+			Request:          normalized.JSON,
+		}
+		err = createRequestWithRevision(db, newRequest, currentUser.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		resolver, err := NewTeamRequestResolver(c, newRequest)
+		if err != nil {
+			return nil, err
+		}
+
+		go bus.Publish("team:"+strconv.Itoa(int(newRequest.TeamID))+":requests:added", resolver)
+
+		return resolver, nil
+	}
+
+	return nil, errors.New("you are not allowed to create a request in this team")
+}
@@ -0,0 +1,123 @@
+package resolvers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	graphql_context "github.com/jerbob92/hoppscotch-backend/api/controllers/graphql/context"
+	"github.com/jerbob92/hoppscotch-backend/helpers/har"
+	"github.com/jerbob92/hoppscotch-backend/helpers/hoppscotch"
+	"github.com/jerbob92/hoppscotch-backend/helpers/insomnia"
+	"github.com/jerbob92/hoppscotch-backend/helpers/scalars"
+
+	"github.com/graph-gophers/graphql-go"
+	"gorm.io/gorm"
+)
+
+// errDryRun rolls back the transaction of a dry run import.
+var errDryRun = errors.New("dry run")
+
+// importCollections stores the collections the same way as a JSON import
+// and returns the JSON export of what was imported. A dry run does the same
+// import, including the validation, but rolls it back.
+func importCollections(c *graphql_context.Context, teamID uint, parentCollectionID uint, userID uint, collections []hoppscotch.Collection, dryRun bool) (string, error) {
+	importData, err := exportJSONFromCollections(collections)
+	if err != nil {
+		return "", err
+	}
+
+	events := &eventQueue{}
+	err = c.GetDB().Transaction(func(tx *gorm.DB) error {
+		_, err := importJSONInTransaction(c, tx, events, teamID, parentCollectionID, userID, importData)
+		if err != nil {
+			return err
+		}
+		if dryRun {
+			return errDryRun
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, errDryRun) {
+		return "", err
+	}
+
+	if !dryRun {
+		events.Flush()
+	}
+
+	collectionsJSON, err := json.Marshal(importData)
+	if err != nil {
+		return "", err
+	}
+
+	return string(collectionsJSON), nil
+}
+
+type ImportCollectionsFromInsomniaArgs struct {
+	Document           *string
+	File               *scalars.Upload
+	ParentCollectionID *graphql.ID
+	TeamID             graphql.ID
+	DryRun             *bool
+}
+
+func (b *BaseQuery) ImportCollectionsFromInsomnia(ctx context.Context, args *ImportCollectionsFromInsomniaArgs) (string, error) {
+	c := b.GetReqC(ctx)
+
+	teamID, parentCollectionID, err := getImportTarget(ctx, c, args.TeamID, args.ParentCollectionID)
+	if err != nil {
+		return "", err
+	}
+
+	document, err := readImportDocument(args.Document, args.File)
+	if err != nil {
+		return "", err
+	}
+
+	collections, err := insomnia.Import(document)
+	if err != nil {
+		return "", err
+	}
+
+	currentUser, err := c.GetUser(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	return importCollections(c, teamID, parentCollectionID, currentUser.ID, collections, args.DryRun != nil && *args.DryRun)
+}
+
+type ImportCollectionsFromHARArgs struct {
+	Document           *string
+	File               *scalars.Upload
+	ParentCollectionID *graphql.ID
+	TeamID             graphql.ID
+	DryRun             *bool
+}
+
+func (b *BaseQuery) ImportCollectionsFromHAR(ctx context.Context, args *ImportCollectionsFromHARArgs) (string, error) {
+	c := b.GetReqC(ctx)
+
+	teamID, parentCollectionID, err := getImportTarget(ctx, c, args.TeamID, args.ParentCollectionID)
+	if err != nil {
+		return "", err
+	}
+
+	document, err := readImportDocument(args.Document, args.File)
+	if err != nil {
+		return "", err
+	}
+
+	collection, err := har.Import(document)
+	if err != nil {
+		return "", err
+	}
+
+	currentUser, err := c.GetUser(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	return importCollections(c, teamID, parentCollectionID, currentUser.ID, []hoppscotch.Collection{*collection}, args.DryRun != nil && *args.DryRun)
+}
@@ -0,0 +1,51 @@
+package resolvers
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+
+	"github.com/spf13/viper"
+	"gopkg.in/gomail.v2"
+)
+
+// sendTemplateMail renders the subject and body of the mail template with the
+// given name from mailTemplates in the config, and sends it to the recipients.
+func sendTemplateMail(to []string, templateName string, templateVariables interface{}) error {
+	from := fmt.Sprintf("%s <%s>", viper.GetString("smtp.from.name"), viper.GetString("smtp.from.email"))
+
+	m := gomail.NewMessage()
+	m.SetHeader("From", from)
+	m.SetHeader("To", to...)
+
+	subjectTemplate := template.New("Subject")
+	subjectTemplate, err := subjectTemplate.Parse(viper.GetString("mailTemplates." + templateName + ".subject"))
+	if err != nil {
+		return err
+	}
+
+	var subject bytes.Buffer
+	err = subjectTemplate.Execute(&subject, templateVariables)
+	if err != nil {
+		return err
+	}
+
+	m.SetHeader("Subject", subject.String())
+
+	bodyTemplate := template.New("Body")
+	bodyTemplate, err = bodyTemplate.Parse(viper.GetString("mailTemplates." + templateName + ".body"))
+	if err != nil {
+		return err
+	}
+
+	var body bytes.Buffer
+	err = bodyTemplate.Execute(&body, templateVariables)
+	if err != nil {
+		return err
+	}
+
+	m.SetBody("text/html", body.String())
+
+	d := gomail.NewDialer(viper.GetString("smtp.host"), viper.GetInt("smtp.port"), viper.GetString("smtp.username"), viper.GetString("smtp.password"))
+	return d.DialAndSend(m)
+}
@@ -0,0 +1,355 @@
+package resolvers
+
+import (
+	"context"
+	"errors"
+	"strconv"
+
+	graphql_context "github.com/jerbob92/hoppscotch-backend/api/controllers/graphql/context"
+	"github.com/jerbob92/hoppscotch-backend/helpers/hoppscotch"
+	"github.com/jerbob92/hoppscotch-backend/helpers/mock"
+	"github.com/jerbob92/hoppscotch-backend/models"
+
+	"github.com/graph-gophers/graphql-go"
+	"github.com/sanae10001/graphql-go-extension-scalars"
+	"github.com/spf13/viper"
+	"gorm.io/gorm"
+)
+
+type MockServerResolver struct {
+	c           *graphql_context.Context
+	mock_server *models.MockServer
+}
+
+func NewMockServerResolver(c *graphql_context.Context, mock_server *models.MockServer) (*MockServerResolver, error) {
+	if mock_server == nil {
+		return nil, nil
+	}
+
+	return &MockServerResolver{c: c, mock_server: mock_server}, nil
+}
+
+func (r *MockServerResolver) ID() (graphql.ID, error) {
+	id := graphql.ID(strconv.Itoa(int(r.mock_server.ID)))
+	return id, nil
+}
+
+func (r *MockServerResolver) TeamID() (graphql.ID, error) {
+	return graphql.ID(strconv.Itoa(int(r.mock_server.TeamID))), nil
+}
+
+func (r *MockServerResolver) CollectionID() (*graphql.ID, error) {
+	if r.mock_server.TeamCollectionID == 0 {
+		return nil, nil
+	}
+	id := graphql.ID(strconv.Itoa(int(r.mock_server.TeamCollectionID)))
+	return &id, nil
+}
+
+func (r *MockServerResolver) Name() (string, error) {
+	return r.mock_server.Name, nil
+}
+
+func (r *MockServerResolver) Slug() (string, error) {
+	return r.mock_server.Slug, nil
+}
+
+func (r *MockServerResolver) Path() (string, error) {
+	return "/mock/" + r.mock_server.Slug, nil
+}
+
+func (r *MockServerResolver) Enabled() (bool, error) {
+	return r.mock_server.Enabled, nil
+}
+
+func (r *MockServerResolver) Delay() (int32, error) {
+	return int32(r.mock_server.Delay), nil
+}
+
+func (r *MockServerResolver) MatchHeaders() (bool, error) {
+	return r.mock_server.MatchHeaders, nil
+}
+
+func (r *MockServerResolver) CreatedOn() (scalars.DateTime, error) {
+	return *scalars.NewDateTime(r.mock_server.CreatedAt), nil
+}
+
+// LoadMockRoutes loads the requests of the mock server with their examples,
+// in the order of their IDs.
+func LoadMockRoutes(db *gorm.DB, mockServer *models.MockServer) ([]*mock.Route, error) {
+	query := db.Model(&models.TeamRequest{}).Where("team_id = ?", mockServer.TeamID)
+	if mockServer.TeamCollectionID != 0 {
+		collectionIDs, err := getCollectionTreeIDs(db, mockServer.TeamID, mockServer.TeamCollectionID)
+		if err != nil {
+			return nil, err
+		}
+		query = query.Where("team_collection_id IN ?", append(collectionIDs, mockServer.TeamCollectionID))
+	}
+
+	requests := []*models.TeamRequest{}
+	err := query.Order("id").Find(&requests).Error
+	if err != nil {
+		return nil, err
+	}
+
+	requestIDs := []uint{}
+	for i := range requests {
+		requestIDs = append(requestIDs, requests[i].ID)
+	}
+
+	examples := []*models.TeamRequestExample{}
+	if len(requestIDs) > 0 {
+		err = db.Model(&models.TeamRequestExample{}).Where("team_request_id IN ?", requestIDs).Order("id").Find(&examples).Error
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	requestExamples := map[uint][]hoppscotch.ExampleResponse{}
+	for i := range examples {
+		headers, err := hoppscotch.ParseExampleHeaders(examples[i].Headers)
+		if err != nil {
+			return nil, err
+		}
+
+		requestExamples[examples[i].TeamRequestID] = append(requestExamples[examples[i].TeamRequestID], hoppscotch.ExampleResponse{
+			Name:    examples[i].Name,
+			Status:  examples[i].Status,
+			Headers: headers,
+			Body:    examples[i].Body,
+		})
+	}
+
+	routes := []*mock.Route{}
+	for i := range requests {
+		request, err := hoppscotch.ParseRESTRequest(currentRequestJSON(requests[i]))
+		if err != nil {
+			// A request the mock server can't read can't be matched.
+			continue
+		}
+		if request.Name == "" {
+			request.Name = requests[i].Title
+		}
+
+		routes = append(routes, mock.NewRoute(requests[i].ID, request, requestExamples[requests[i].ID]))
+	}
+
+	return routes, nil
+}
+
+// validateMockServerDelay checks the delay against the configured maximum.
+func validateMockServerDelay(delay *int32) (int, error) {
+	if delay == nil {
+		return 0, nil
+	}
+
+	maxDelay := viper.GetInt("api.mock.maxDelay")
+	if *delay < 0 || int(*delay) > maxDelay {
+		return 0, errors.New("the delay must be between 0 and " + strconv.Itoa(maxDelay) + " milliseconds")
+	}
+
+	return int(*delay), nil
+}
+
+// getEditableMockServer loads the mock server when the current user is an
+// owner or editor of its team.
+func getEditableMockServer(ctx context.Context, c *graphql_context.Context, mockServerID graphql.ID) (*models.MockServer, error) {
+	db := c.GetDB()
+	mockServer := &models.MockServer{}
+	err := db.Model(&models.MockServer{}).Where("id = ?", mockServerID).First(mockServer).Error
+	if err != nil && err == gorm.ErrRecordNotFound {
+		return nil, errors.New("you do not have access to this mock server")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	userRole, err := getUserRoleInTeam(ctx, c, mockServer.TeamID)
+	if err != nil {
+		return nil, err
+	}
+
+	if userRole == nil {
+		return nil, errors.New("you do not have access to this mock server")
+	}
+
+	if *userRole != models.Owner && *userRole != models.Editor {
+		return nil, errors.New("you are not allowed to change mock servers in this team")
+	}
+
+	return mockServer, nil
+}
+
+type MockServersOfTeamArgs struct {
+	TeamID graphql.ID
+}
+
+func (b *BaseQuery) MockServersOfTeam(ctx context.Context, args *MockServersOfTeamArgs) ([]*MockServerResolver, error) {
+	c := b.GetReqC(ctx)
+	db := c.GetDB()
+
+	userRole, err := getUserRoleInTeam(ctx, c, args.TeamID)
+	if err != nil {
+		return nil, err
+	}
+
+	if userRole == nil {
+		return nil, errors.New("you do not have access to this team")
+	}
+
+	mockServers := []*models.MockServer{}
+	err = db.Model(&models.MockServer{}).Where("team_id = ?", args.TeamID).Order("id").Find(&mockServers).Error
+	if err != nil {
+		return nil, err
+	}
+
+	mockServerResolvers := []*MockServerResolver{}
+	for i := range mockServers {
+		newResolver, err := NewMockServerResolver(c, mockServers[i])
+		if err != nil {
+			return nil, err
+		}
+		mockServerResolvers = append(mockServerResolvers, newResolver)
+	}
+
+	return mockServerResolvers, nil
+}
+
+type CreateMockServerArgs struct {
+	TeamID       graphql.ID
+	CollectionID *graphql.ID
+	Name         string
+	Delay        *int32
+	MatchHeaders *bool
+}
+
+func (b *BaseQuery) CreateMockServer(ctx context.Context, args *CreateMockServerArgs) (*MockServerResolver, error) {
+	c := b.GetReqC(ctx)
+	db := c.GetDB()
+
+	userRole, err := getUserRoleInTeam(ctx, c, args.TeamID)
+	if err != nil {
+		return nil, err
+	}
+
+	if userRole == nil {
+		return nil, errors.New("you do not have access to this team")
+	}
+
+	if *userRole != models.Owner && *userRole != models.Editor {
+		return nil, errors.New("you are not allowed to create mock servers in this team")
+	}
+
+	if args.Name == "" {
+		return nil, errors.New("the name of a mock server can't be empty")
+	}
+
+	delay, err := validateMockServerDelay(args.Delay)
+	if err != nil {
+		return nil, err
+	}
+
+	parsedTeamID, _ := strconv.Atoi(string(args.TeamID))
+	mockServer := &models.MockServer{
+		TeamID:       uint(parsedTeamID),
+		Name:         args.Name,
+		Slug:         RandString(16),
+		Enabled:      true,
+		Delay:        delay,
+		MatchHeaders: args.MatchHeaders != nil && *args.MatchHeaders,
+	}
+
+	if args.CollectionID != nil {
+		collection := &models.TeamCollection{}
+		err := db.Model(&models.TeamCollection{}).Where("id = ? AND team_id = ?", args.CollectionID, args.TeamID).First(collection).Error
+		if err != nil && err == gorm.ErrRecordNotFound {
+			return nil, errors.New("you do not have access to this collection")
+		}
+		if err != nil {
+			return nil, err
+		}
+		mockServer.TeamCollectionID = collection.ID
+	}
+
+	currentUser, err := c.GetUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	mockServer.CreatedByID = currentUser.ID
+
+	err = db.Save(mockServer).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return NewMockServerResolver(c, mockServer)
+}
+
+type UpdateMockServerArgs struct {
+	MockServerID graphql.ID
+	Name         *string
+	Delay        *int32
+	MatchHeaders *bool
+	Enabled      *bool
+}
+
+func (b *BaseQuery) UpdateMockServer(ctx context.Context, args *UpdateMockServerArgs) (*MockServerResolver, error) {
+	c := b.GetReqC(ctx)
+	db := c.GetDB()
+
+	mockServer, err := getEditableMockServer(ctx, c, args.MockServerID)
+	if err != nil {
+		return nil, err
+	}
+
+	updates := map[string]interface{}{}
+	if args.Name != nil {
+		if *args.Name == "" {
+			return nil, errors.New("the name of a mock server can't be empty")
+		}
+		updates["name"] = *args.Name
+	}
+	if args.Delay != nil {
+		delay, err := validateMockServerDelay(args.Delay)
+		if err != nil {
+			return nil, err
+		}
+		updates["delay"] = delay
+	}
+	if args.MatchHeaders != nil {
+		updates["match_headers"] = *args.MatchHeaders
+	}
+	if args.Enabled != nil {
+		updates["enabled"] = *args.Enabled
+	}
+
+	if len(updates) > 0 {
+		err = db.Model(mockServer).Updates(updates).Error
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return NewMockServerResolver(c, mockServer)
+}
+
+type DeleteMockServerArgs struct {
+	MockServerID graphql.ID
+}
+
+func (b *BaseQuery) DeleteMockServer(ctx context.Context, args *DeleteMockServerArgs) (bool, error) {
+	c := b.GetReqC(ctx)
+	db := c.GetDB()
+
+	mockServer, err := getEditableMockServer(ctx, c, args.MockServerID)
+	if err != nil {
+		return false, err
+	}
+
+	err = db.Delete(mockServer).Error
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
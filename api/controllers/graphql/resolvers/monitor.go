@@ -0,0 +1,556 @@
+package resolvers
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	graphql_context "github.com/jerbob92/hoppscotch-backend/api/controllers/graphql/context"
+	"github.com/jerbob92/hoppscotch-backend/helpers/cron"
+	"github.com/jerbob92/hoppscotch-backend/models"
+
+	"github.com/graph-gophers/graphql-go"
+	"github.com/sanae10001/graphql-go-extension-scalars"
+	"gorm.io/gorm"
+)
+
+type MonitorResolver struct {
+	c       *graphql_context.Context
+	monitor *models.Monitor
+}
+
+func NewMonitorResolver(c *graphql_context.Context, monitor *models.Monitor) (*MonitorResolver, error) {
+	if monitor == nil {
+		return nil, nil
+	}
+
+	return &MonitorResolver{c: c, monitor: monitor}, nil
+}
+
+func (r *MonitorResolver) ID() (graphql.ID, error) {
+	id := graphql.ID(strconv.Itoa(int(r.monitor.ID)))
+	return id, nil
+}
+
+func (r *MonitorResolver) TeamID() (graphql.ID, error) {
+	return graphql.ID(strconv.Itoa(int(r.monitor.TeamID))), nil
+}
+
+func (r *MonitorResolver) Name() (string, error) {
+	return r.monitor.Name, nil
+}
+
+func (r *MonitorResolver) Schedule() (string, error) {
+	return r.monitor.Schedule, nil
+}
+
+func (r *MonitorResolver) CollectionID() (graphql.ID, error) {
+	return graphql.ID(strconv.Itoa(int(r.monitor.TeamCollectionID))), nil
+}
+
+func (r *MonitorResolver) Collection() (*TeamCollectionResolver, error) {
+	collection, found, err := r.c.GetLoaders().TeamCollection.Load(r.monitor.TeamCollectionID)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, nil
+	}
+	return NewTeamCollectionResolver(r.c, collection)
+}
+
+func (r *MonitorResolver) EnvironmentID() (*graphql.ID, error) {
+	if r.monitor.TeamEnvironmentID == nil {
+		return nil, nil
+	}
+	id := graphql.ID(strconv.Itoa(int(*r.monitor.TeamEnvironmentID)))
+	return &id, nil
+}
+
+func (r *MonitorResolver) Enabled() (bool, error) {
+	return r.monitor.Enabled, nil
+}
+
+func (r *MonitorResolver) Status() (models.MonitorStatus, error) {
+	return r.monitor.Status, nil
+}
+
+func (r *MonitorResolver) NextRunOn() (*scalars.DateTime, error) {
+	if !r.monitor.Enabled || r.monitor.NextRunAt == nil {
+		return nil, nil
+	}
+	return scalars.NewDateTime(*r.monitor.NextRunAt), nil
+}
+
+func (r *MonitorResolver) LastRunOn() (*scalars.DateTime, error) {
+	if r.monitor.LastRunAt == nil {
+		return nil, nil
+	}
+	return scalars.NewDateTime(*r.monitor.LastRunAt), nil
+}
+
+func (r *MonitorResolver) CreatedOn() (scalars.DateTime, error) {
+	return *scalars.NewDateTime(r.monitor.CreatedAt), nil
+}
+
+type MonitorRunsArgs struct {
+	From   *scalars.DateTime
+	To     *scalars.DateTime
+	Cursor *graphql.ID
+	Take   *int32
+}
+
+func (r *MonitorResolver) Runs(args *MonitorRunsArgs) ([]*MonitorRunResolver, error) {
+	runs := []*models.MonitorRun{}
+	db := r.c.GetDB()
+
+	query := db.Model(&models.MonitorRun{}).Where("monitor_id = ?", r.monitor.ID)
+	if args.From != nil {
+		query = query.Where("started_at >= ?", args.From.Time)
+	}
+	if args.To != nil {
+		query = query.Where("started_at < ?", args.To.Time)
+	}
+	if args.Cursor != nil && *args.Cursor != "" {
+		query = query.Where("id > ?", args.Cursor)
+	}
+	query = orderAndLimit(query, "id", args.Take)
+
+	err := query.Find(&runs).Error
+	if err != nil {
+		return nil, err
+	}
+
+	runResolvers := []*MonitorRunResolver{}
+	for i := range runs {
+		newResolver, err := NewMonitorRunResolver(r.c, runs[i])
+		if err != nil {
+			return nil, err
+		}
+		runResolvers = append(runResolvers, newResolver)
+	}
+
+	return runResolvers, nil
+}
+
+type MonitorRunResolver struct {
+	c   *graphql_context.Context
+	run *models.MonitorRun
+}
+
+func NewMonitorRunResolver(c *graphql_context.Context, run *models.MonitorRun) (*MonitorRunResolver, error) {
+	if run == nil {
+		return nil, nil
+	}
+
+	return &MonitorRunResolver{c: c, run: run}, nil
+}
+
+func (r *MonitorRunResolver) ID() (graphql.ID, error) {
+	id := graphql.ID(strconv.Itoa(int(r.run.ID)))
+	return id, nil
+}
+
+func (r *MonitorRunResolver) MonitorID() (graphql.ID, error) {
+	return graphql.ID(strconv.Itoa(int(r.run.MonitorID))), nil
+}
+
+func (r *MonitorRunResolver) StartedOn() (scalars.DateTime, error) {
+	return *scalars.NewDateTime(r.run.StartedAt), nil
+}
+
+func (r *MonitorRunResolver) FinishedOn() (scalars.DateTime, error) {
+	return *scalars.NewDateTime(r.run.FinishedAt), nil
+}
+
+func (r *MonitorRunResolver) Passed() (bool, error) {
+	return r.run.Passed, nil
+}
+
+func (r *MonitorRunResolver) Total() (int32, error) {
+	return int32(r.run.Total), nil
+}
+
+func (r *MonitorRunResolver) Failed() (int32, error) {
+	return int32(r.run.Failed), nil
+}
+
+func (r *MonitorRunResolver) Duration() (float64, error) {
+	return r.run.Duration, nil
+}
+
+func (r *MonitorRunResolver) Error() (*string, error) {
+	if r.run.Error == "" {
+		return nil, nil
+	}
+	return &r.run.Error, nil
+}
+
+func (r *MonitorRunResolver) Requests() ([]*MonitorRunRequestResolver, error) {
+	requests := []*models.MonitorRunRequest{}
+	db := r.c.GetDB()
+
+	err := db.Model(&models.MonitorRunRequest{}).Where("monitor_run_id = ?", r.run.ID).Order("id").Find(&requests).Error
+	if err != nil {
+		return nil, err
+	}
+
+	requestResolvers := []*MonitorRunRequestResolver{}
+	for i := range requests {
+		requestResolvers = append(requestResolvers, &MonitorRunRequestResolver{request: requests[i]})
+	}
+
+	return requestResolvers, nil
+}
+
+type MonitorRunRequestResolver struct {
+	request *models.MonitorRunRequest
+}
+
+func (r *MonitorRunRequestResolver) RequestID() (graphql.ID, error) {
+	return graphql.ID(strconv.Itoa(int(r.request.TeamRequestID))), nil
+}
+
+func (r *MonitorRunRequestResolver) Name() (string, error) {
+	return r.request.Name, nil
+}
+
+func (r *MonitorRunRequestResolver) Path() (string, error) {
+	return r.request.Path, nil
+}
+
+func (r *MonitorRunRequestResolver) Method() (string, error) {
+	return r.request.Method, nil
+}
+
+func (r *MonitorRunRequestResolver) URL() (string, error) {
+	return r.request.URL, nil
+}
+
+func (r *MonitorRunRequestResolver) Status() (int32, error) {
+	return int32(r.request.Status), nil
+}
+
+func (r *MonitorRunRequestResolver) Duration() (float64, error) {
+	return r.request.Duration, nil
+}
+
+func (r *MonitorRunRequestResolver) Passed() (bool, error) {
+	return r.request.Passed, nil
+}
+
+func (r *MonitorRunRequestResolver) Error() (*string, error) {
+	if r.request.Error == "" {
+		return nil, nil
+	}
+	return &r.request.Error, nil
+}
+
+func (r *MonitorRunRequestResolver) Unsupported() (*string, error) {
+	if r.request.Unsupported == "" {
+		return nil, nil
+	}
+	return &r.request.Unsupported, nil
+}
+
+// nextMonitorRun parses the schedule and returns the time of its first run
+// after now, nil when it never runs.
+func nextMonitorRun(expression string, now time.Time) (*time.Time, error) {
+	schedule, err := cron.Parse(expression)
+	if err != nil {
+		return nil, err
+	}
+
+	next := schedule.Next(now)
+	if next.IsZero() {
+		return nil, nil
+	}
+
+	return &next, nil
+}
+
+// getMonitor loads the monitor with the role of the current user in its team.
+func getMonitor(ctx context.Context, c *graphql_context.Context, monitorID graphql.ID) (*models.Monitor, *models.TeamMemberRole, error) {
+	db := c.GetDB()
+	monitor := &models.Monitor{}
+	err := db.Model(&models.Monitor{}).Where("id = ?", monitorID).First(monitor).Error
+	if err != nil && err == gorm.ErrRecordNotFound {
+		return nil, nil, errors.New("you do not have access to this monitor")
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	userRole, err := getUserRoleInTeam(ctx, c, monitor.TeamID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if userRole == nil {
+		return nil, nil, errors.New("you do not have access to this monitor")
+	}
+
+	return monitor, userRole, nil
+}
+
+// checkMonitorEnvironment checks that the environment belongs to the team.
+func checkMonitorEnvironment(db *gorm.DB, teamID uint, environmentID *graphql.ID) (*uint, error) {
+	if environmentID == nil {
+		return nil, nil
+	}
+
+	environment := &models.TeamEnvironment{}
+	err := db.Model(&models.TeamEnvironment{}).Where("id = ? AND team_id = ?", *environmentID, teamID).First(environment).Error
+	if err != nil && err == gorm.ErrRecordNotFound {
+		return nil, errors.New("you do not have access to this environment")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &environment.ID, nil
+}
+
+type MonitorsOfTeamArgs struct {
+	TeamID graphql.ID
+}
+
+func (b *BaseQuery) MonitorsOfTeam(ctx context.Context, args *MonitorsOfTeamArgs) ([]*MonitorResolver, error) {
+	c := b.GetReqC(ctx)
+	db := c.GetDB()
+
+	userRole, err := getUserRoleInTeam(ctx, c, args.TeamID)
+	if err != nil {
+		return nil, err
+	}
+
+	if userRole == nil {
+		return nil, errors.New("you do not have access to this team")
+	}
+
+	monitors := []*models.Monitor{}
+	err = db.Model(&models.Monitor{}).Where("team_id = ?", args.TeamID).Order("id").Find(&monitors).Error
+	if err != nil {
+		return nil, err
+	}
+
+	monitorResolvers := []*MonitorResolver{}
+	for i := range monitors {
+		newResolver, err := NewMonitorResolver(c, monitors[i])
+		if err != nil {
+			return nil, err
+		}
+		monitorResolvers = append(monitorResolvers, newResolver)
+	}
+
+	return monitorResolvers, nil
+}
+
+type MonitorArgs struct {
+	MonitorID graphql.ID
+}
+
+func (b *BaseQuery) Monitor(ctx context.Context, args *MonitorArgs) (*MonitorResolver, error) {
+	c := b.GetReqC(ctx)
+
+	monitor, _, err := getMonitor(ctx, c, args.MonitorID)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewMonitorResolver(c, monitor)
+}
+
+type CreateMonitorArgs struct {
+	CollectionID  graphql.ID
+	EnvironmentID *graphql.ID
+	Name          string
+	Schedule      string
+	Enabled       *bool
+}
+
+func (b *BaseQuery) CreateMonitor(ctx context.Context, args *CreateMonitorArgs) (*MonitorResolver, error) {
+	c := b.GetReqC(ctx)
+	db := c.GetDB()
+
+	collection := &models.TeamCollection{}
+	err := db.Model(&models.TeamCollection{}).Where("id = ?", args.CollectionID).First(collection).Error
+	if err != nil && err == gorm.ErrRecordNotFound {
+		return nil, errors.New("you do not have access to this collection")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	userRole, err := getUserRoleInTeam(ctx, c, collection.TeamID)
+	if err != nil {
+		return nil, err
+	}
+
+	if userRole == nil {
+		return nil, errors.New("you do not have access to this collection")
+	}
+
+	if *userRole != models.Owner {
+		return nil, errors.New("only owners can create monitors")
+	}
+
+	if args.Name == "" {
+		return nil, errors.New("the name of a monitor can't be empty")
+	}
+
+	nextRunAt, err := nextMonitorRun(args.Schedule, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	if nextRunAt == nil {
+		return nil, errors.New("the schedule of a monitor must have a next run")
+	}
+
+	environmentID, err := checkMonitorEnvironment(db, collection.TeamID, args.EnvironmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	currentUser, err := c.GetUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	monitor := &models.Monitor{
+		TeamID:            collection.TeamID,
+		TeamCollectionID:  collection.ID,
+		TeamEnvironmentID: environmentID,
+		Name:              args.Name,
+		Schedule:          args.Schedule,
+		Enabled:           args.Enabled == nil || *args.Enabled,
+		Status:            models.MonitorPending,
+		CreatedByID:       currentUser.ID,
+		NextRunAt:         nextRunAt,
+	}
+
+	err = db.Save(monitor).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return NewMonitorResolver(c, monitor)
+}
+
+type UpdateMonitorArgs struct {
+	MonitorID     graphql.ID
+	EnvironmentID *graphql.ID
+	Name          string
+	Schedule      string
+	Enabled       bool
+}
+
+func (b *BaseQuery) UpdateMonitor(ctx context.Context, args *UpdateMonitorArgs) (*MonitorResolver, error) {
+	c := b.GetReqC(ctx)
+	db := c.GetDB()
+
+	monitor, userRole, err := getMonitor(ctx, c, args.MonitorID)
+	if err != nil {
+		return nil, err
+	}
+
+	if *userRole != models.Owner {
+		return nil, errors.New("only owners can update monitors")
+	}
+
+	if args.Name == "" {
+		return nil, errors.New("the name of a monitor can't be empty")
+	}
+
+	nextRunAt, err := nextMonitorRun(args.Schedule, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	if nextRunAt == nil {
+		return nil, errors.New("the schedule of a monitor must have a next run")
+	}
+
+	environmentID, err := checkMonitorEnvironment(db, monitor.TeamID, args.EnvironmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = updateVersioned(db, &models.Monitor{}, monitor.ID, nil, map[string]interface{}{
+		"team_environment_id": environmentID,
+		"name":                args.Name,
+		"schedule":            args.Schedule,
+		"enabled":             args.Enabled,
+		"next_run_at":         nextRunAt,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = db.Model(&models.Monitor{}).Where("id = ?", monitor.ID).First(monitor).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return NewMonitorResolver(c, monitor)
+}
+
+func (b *BaseQuery) DeleteMonitor(ctx context.Context, args *MonitorArgs) (bool, error) {
+	c := b.GetReqC(ctx)
+	db := c.GetDB()
+
+	monitor, userRole, err := getMonitor(ctx, c, args.MonitorID)
+	if err != nil {
+		return false, err
+	}
+
+	if *userRole != models.Owner {
+		return false, errors.New("only owners can delete monitors")
+	}
+
+	err = db.Transaction(func(tx *gorm.DB) error {
+		runIDs := tx.Model(&models.MonitorRun{}).Select("id").Where("monitor_id = ?", monitor.ID)
+		if err := tx.Where("monitor_run_id IN (?)", runIDs).Delete(&models.MonitorRunRequest{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("monitor_id = ?", monitor.ID).Delete(&models.MonitorRun{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(monitor).Error
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+func (b *BaseQuery) MonitorFailing(ctx context.Context, args *SubscriptionArgs) (<-chan *MonitorResolver, error) {
+	c := b.GetReqC(ctx)
+
+	userRole, err := getUserRoleInTeam(ctx, c, args.TeamID)
+	if err != nil {
+		return nil, err
+	}
+	if userRole == nil {
+		return nil, errors.New("no access to team")
+	}
+
+	teamID, _ := strconv.Atoi(string(args.TeamID))
+	notificationChannel := make(chan *MonitorResolver)
+	eventHandler := func(monitor *models.Monitor) {
+		resolver, err := NewMonitorResolver(eventContext(c), monitor)
+		if err != nil {
+			c.LogErr(err)
+			return
+		}
+		notificationChannel <- resolver
+	}
+
+	err = subscribeUntilDone(ctx, "team:"+strconv.Itoa(teamID)+":monitors:failing", eventHandler)
+	if err != nil {
+		return nil, err
+	}
+
+	return notificationChannel, nil
+}
@@ -0,0 +1,268 @@
+package resolvers
+
+import (
+	"context"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/jerbob92/hoppscotch-backend/config"
+	"github.com/jerbob92/hoppscotch-backend/db"
+	"github.com/jerbob92/hoppscotch-backend/helpers/runner"
+	"github.com/jerbob92/hoppscotch-backend/models"
+
+	"github.com/spf13/viper"
+	"gorm.io/gorm"
+)
+
+// StartMonitorScheduler runs the monitors that are due in the background.
+// Every replica can run a scheduler, a run is claimed by moving the next run
+// of the monitor forward at the version it was loaded with, so only one
+// replica runs it.
+func StartMonitorScheduler() {
+	if !viper.GetBool("api.monitors.enabled") {
+		return
+	}
+
+	pollInterval := time.Duration(viper.GetInt("api.monitors.pollInterval")) * time.Second
+	if pollInterval <= 0 {
+		pollInterval = time.Minute
+	}
+
+	concurrency := viper.GetInt("api.monitors.concurrency")
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	slots := make(chan struct{}, concurrency)
+
+	go func() {
+		ticker := time.NewTicker(pollInterval)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			if err := runDueMonitors(db.DB, slots); err != nil {
+				log.Printf("could not run monitors: %s", err)
+			}
+		}
+	}()
+}
+
+// runDueMonitors claims and starts the monitors that are due, it waits for a
+// free slot before claiming a monitor so claimed runs start right away.
+func runDueMonitors(db *gorm.DB, slots chan struct{}) error {
+	monitors := []*models.Monitor{}
+	err := db.Model(&models.Monitor{}).Where("enabled = ? AND next_run_at <= ?", true, time.Now()).Order("next_run_at").Find(&monitors).Error
+	if err != nil {
+		return err
+	}
+
+	for i := range monitors {
+		slots <- struct{}{}
+
+		claimed, err := claimMonitorRun(db, monitors[i])
+		if err != nil || !claimed {
+			<-slots
+			if err != nil {
+				log.Printf("could not claim run of monitor %d: %s", monitors[i].ID, err)
+			}
+			continue
+		}
+
+		go func(monitor *models.Monitor) {
+			defer func() { <-slots }()
+			if err := runMonitor(db, monitor); err != nil {
+				log.Printf("could not run monitor %d: %s", monitor.ID, err)
+			}
+		}(monitors[i])
+	}
+
+	return nil
+}
+
+// claimMonitorRun moves the next run of the monitor forward. It returns false
+// when the monitor was claimed or changed by someone else since it was loaded.
+func claimMonitorRun(db *gorm.DB, monitor *models.Monitor) (bool, error) {
+	now := time.Now()
+	nextRunAt, err := nextMonitorRun(monitor.Schedule, now)
+	if err != nil {
+		return false, err
+	}
+
+	expectedVersion := int32(monitor.Version)
+	return updateVersioned(db, &models.Monitor{}, monitor.ID, &expectedVersion, map[string]interface{}{
+		"next_run_at": nextRunAt,
+		"last_run_at": now,
+	})
+}
+
+// runMonitor runs the collection of the monitor, stores the run and notifies
+// the team when the monitor starts failing.
+func runMonitor(db *gorm.DB, monitor *models.Monitor) error {
+	run := &models.MonitorRun{
+		MonitorID: monitor.ID,
+		StartedAt: time.Now(),
+	}
+
+	report, err := runMonitorCollection(db, monitor)
+	if err != nil {
+		run.Error = err.Error()
+	} else {
+		run.Passed = report.Passed
+		run.Total = report.Total
+		run.Failed = report.Failed
+		run.Duration = report.Duration
+	}
+	run.FinishedAt = time.Now()
+
+	status := models.MonitorPassing
+	if !run.Passed {
+		status = models.MonitorFailing
+	}
+
+	err = db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(run).Error; err != nil {
+			return err
+		}
+
+		if report != nil {
+			for _, result := range report.Requests {
+				request := &models.MonitorRunRequest{
+					MonitorRunID:  run.ID,
+					TeamRequestID: result.ID,
+					Name:          result.Name,
+					Path:          result.Path,
+					Method:        result.Method,
+					URL:           result.URL,
+					Status:        result.Status,
+					Duration:      result.Duration,
+					Passed:        result.Passed,
+					Error:         result.Error,
+					Unsupported:   result.Unsupported,
+				}
+				if err := tx.Save(request).Error; err != nil {
+					return err
+				}
+			}
+		}
+
+		if err := tx.Model(&models.Monitor{}).Where("id = ?", monitor.ID).Update("status", status).Error; err != nil {
+			return err
+		}
+
+		return pruneMonitorRuns(tx, monitor.ID)
+	})
+	if err != nil {
+		return err
+	}
+
+	if status == models.MonitorFailing && monitor.Status != models.MonitorFailing {
+		monitor.Status = status
+		monitor.LastRunAt = &run.StartedAt
+		notifyMonitorFailing(db, monitor, run)
+	}
+
+	return nil
+}
+
+// runMonitorCollection runs the collection of the monitor with its
+// environment.
+func runMonitorCollection(db *gorm.DB, monitor *models.Monitor) (*runner.Report, error) {
+	collection := &models.TeamCollection{}
+	err := db.Model(&models.TeamCollection{}).Where("id = ? AND team_id = ?", monitor.TeamCollectionID, monitor.TeamID).First(collection).Error
+	if err != nil {
+		return nil, err
+	}
+
+	var environment *models.TeamEnvironment
+	if monitor.TeamEnvironmentID != nil {
+		environment = &models.TeamEnvironment{}
+		err := db.Model(&models.TeamEnvironment{}).Where("id = ? AND team_id = ?", *monitor.TeamEnvironmentID, monitor.TeamID).First(environment).Error
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	options, err := config.ExecutorOptions()
+	if err != nil {
+		return nil, err
+	}
+
+	return RunTeamCollection(context.Background(), db, collection, environment, options)
+}
+
+// pruneMonitorRuns removes the oldest runs of the monitor when it has more
+// than the configured maximum.
+func pruneMonitorRuns(db *gorm.DB, monitorID uint) error {
+	maxRuns := viper.GetInt("api.monitors.maxRunsPerMonitor")
+	if maxRuns <= 0 {
+		return nil
+	}
+
+	keepIDs := []uint{}
+	err := db.Model(&models.MonitorRun{}).Where("monitor_id = ?", monitorID).Order("id DESC").Limit(maxRuns).Pluck("id", &keepIDs).Error
+	if err != nil {
+		return err
+	}
+
+	if len(keepIDs) < maxRuns {
+		return nil
+	}
+
+	removeIDs := db.Model(&models.MonitorRun{}).Select("id").Where("monitor_id = ? AND id NOT IN ?", monitorID, keepIDs)
+	err = db.Unscoped().Where("monitor_run_id IN (?)", removeIDs).Delete(&models.MonitorRunRequest{}).Error
+	if err != nil {
+		return err
+	}
+
+	return db.Unscoped().Where("monitor_id = ? AND id NOT IN ?", monitorID, keepIDs).Delete(&models.MonitorRun{}).Error
+}
+
+// notifyMonitorFailing publishes the monitor to the monitorFailing
+// subscription and mails the owners of the team.
+func notifyMonitorFailing(db *gorm.DB, monitor *models.Monitor, run *models.MonitorRun) {
+	go bus.Publish("team:"+strconv.Itoa(int(monitor.TeamID))+":monitors:failing", monitor)
+
+	if viper.GetString("smtp.host") == "" {
+		return
+	}
+
+	team := &models.Team{}
+	err := db.Model(&models.Team{}).Where("id = ?", monitor.TeamID).First(team).Error
+	if err != nil {
+		log.Printf("could not load team of monitor %d: %s", monitor.ID, err)
+		return
+	}
+
+	emails := []string{}
+	err = db.Model(&models.User{}).
+		Joins("JOIN team_members ON team_members.user_id = users.id AND team_members.deleted_at IS NULL").
+		Where("team_members.team_id = ? AND team_members.role = ? AND users.email <> ''", monitor.TeamID, models.Owner).
+		Pluck("users.email", &emails).Error
+	if err != nil {
+		log.Printf("could not load owners of team %d: %s", monitor.TeamID, err)
+		return
+	}
+
+	if len(emails) == 0 {
+		return
+	}
+
+	templateVariables := struct {
+		MonitorName string
+		TeamName    string
+		Failed      int
+		Total       int
+		Error       string
+	}{
+		MonitorName: monitor.Name,
+		TeamName:    team.Name,
+		Failed:      run.Failed,
+		Total:       run.Total,
+		Error:       run.Error,
+	}
+
+	err = sendTemplateMail(emails, "monitorFailing", templateVariables)
+	if err != nil {
+		log.Printf("could not mail owners of team %d about monitor %d: %s", monitor.TeamID, monitor.ID, err)
+	}
+}
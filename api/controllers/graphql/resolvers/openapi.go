@@ -0,0 +1,212 @@
+package resolvers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/jerbob92/hoppscotch-backend/helpers/hoppscotch"
+	"github.com/jerbob92/hoppscotch-backend/helpers/openapi"
+	"github.com/jerbob92/hoppscotch-backend/helpers/scalars"
+	"github.com/jerbob92/hoppscotch-backend/models"
+
+	"github.com/graph-gophers/graphql-go"
+	"gorm.io/gorm"
+)
+
+// MaxImportDocumentSize is the maximum size of an uploaded document.
+const MaxImportDocumentSize = 20 * 1024 * 1024
+
+// readImportDocument returns the pasted document or the contents of the
+// uploaded file.
+func readImportDocument(document *string, file *scalars.Upload) ([]byte, error) {
+	if document != nil && *document != "" {
+		return []byte(*document), nil
+	}
+
+	if file == nil || file.File == nil {
+		return nil, errors.New("either a document or a file is required")
+	}
+	defer file.File.Close()
+
+	data, err := io.ReadAll(io.LimitReader(file.File, MaxImportDocumentSize+1))
+	if err != nil {
+		return nil, err
+	}
+
+	if len(data) > MaxImportDocumentSize {
+		return nil, errors.New("the uploaded file is too large")
+	}
+
+	return data, nil
+}
+
+type ImportCollectionsFromOpenAPIArgs struct {
+	Document           *string
+	File               *scalars.Upload
+	ParentCollectionID *graphql.ID
+	TeamID             graphql.ID
+}
+
+func (b *BaseQuery) ImportCollectionsFromOpenAPI(ctx context.Context, args *ImportCollectionsFromOpenAPIArgs) (bool, error) {
+	c := b.GetReqC(ctx)
+	db := c.GetDB()
+
+	teamID, parentCollectionID, err := getImportTarget(ctx, c, args.TeamID, args.ParentCollectionID)
+	if err != nil {
+		return false, err
+	}
+
+	document, err := readImportDocument(args.Document, args.File)
+	if err != nil {
+		return false, err
+	}
+
+	result, err := openapi.Import(document)
+	if err != nil {
+		return false, err
+	}
+
+	importData, err := exportJSONFromCollections([]hoppscotch.Collection{result.Collection})
+	if err != nil {
+		return false, err
+	}
+
+	var variables string
+	if len(result.Variables) > 0 {
+		variables, err = hoppscotch.EnvironmentVariablesJSON(result.Variables)
+		if err != nil {
+			return false, err
+		}
+	}
+
+	currentUser, err := c.GetUser(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	// The environment is created together with the collections.
+	events := &eventQueue{}
+	err = db.Transaction(func(tx *gorm.DB) error {
+		_, err := importJSONInTransaction(c, tx, events, teamID, parentCollectionID, currentUser.ID, importData)
+		if err != nil {
+			return err
+		}
+
+		if len(result.Variables) == 0 {
+			return nil
+		}
+
+		newTeamEnvironment := &models.TeamEnvironment{
+			TeamID:    teamID,
+			Name:      result.Collection.Name,
+			Variables: variables,
+		}
+
+		err = tx.Save(newTeamEnvironment).Error
+		if err != nil {
+			return err
+		}
+
+		resolver, err := NewTeamEnvironmentResolver(c, newTeamEnvironment)
+		if err != nil {
+			return err
+		}
+
+		events.Publish("team:"+strconv.Itoa(int(teamID))+":environments:created", resolver)
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
+
+	events.Flush()
+
+	return true, nil
+}
+
+type OpenAPIExportResolver struct {
+	document string
+	warnings []string
+}
+
+func (r *OpenAPIExportResolver) Document() string {
+	return r.document
+}
+
+func (r *OpenAPIExportResolver) Warnings() []string {
+	return r.warnings
+}
+
+type ExportCollectionToOpenAPIArgs struct {
+	CollectionID graphql.ID
+}
+
+func (b *BaseQuery) ExportCollectionToOpenAPI(ctx context.Context, args *ExportCollectionToOpenAPIArgs) (*OpenAPIExportResolver, error) {
+	c := b.GetReqC(ctx)
+	db := c.GetDB()
+	collection := &models.TeamCollection{}
+	err := db.Model(&models.TeamCollection{}).Where("id = ?", args.CollectionID).First(collection).Error
+	if err != nil && err == gorm.ErrRecordNotFound {
+		return nil, errors.New("you do not have access to this collection")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	userRole, err := getUserRoleInTeam(ctx, c, collection.TeamID)
+	if err != nil {
+		return nil, err
+	}
+
+	if userRole == nil {
+		return nil, errors.New("you do not have access to this collection")
+	}
+
+	collectionExport, err := GetCollectionExportJSON(c, collection)
+	if err != nil {
+		return nil, err
+	}
+
+	warnings := []string{}
+	result := openapi.Export(collectionFromExportJSON(*collectionExport, collectionExport.Name, &warnings))
+
+	documentJSON, err := json.MarshalIndent(result.Document, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	return &OpenAPIExportResolver{
+		document: string(documentJSON),
+		warnings: append(warnings, result.Warnings...),
+	}, nil
+}
+
+// collectionFromExportJSON converts an exported collection back into parsed
+// requests, requests that can't be parsed are added to the warnings.
+func collectionFromExportJSON(export ExportJSONCollection, path string, warnings *[]string) hoppscotch.Collection {
+	collection := hoppscotch.Collection{
+		Name:     export.Name,
+		Folders:  []hoppscotch.Collection{},
+		Requests: []*hoppscotch.RESTRequest{},
+	}
+
+	for i := range export.Requests {
+		request, err := hoppscotch.ParseRESTRequestMap(export.Requests[i])
+		if err != nil {
+			name, _ := export.Requests[i]["name"].(string)
+			*warnings = append(*warnings, fmt.Sprintf("%s / %s: the request could not be read: %s", path, name, err.Error()))
+			continue
+		}
+		collection.Requests = append(collection.Requests, request)
+	}
+
+	for i := range export.Folders {
+		collection.Folders = append(collection.Folders, collectionFromExportJSON(export.Folders[i], path+" / "+export.Folders[i].Name, warnings))
+	}
+
+	return collection
+}
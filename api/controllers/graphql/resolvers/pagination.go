@@ -0,0 +1,158 @@
+package resolvers
+
+import (
+	"encoding/base64"
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/viper"
+	"gorm.io/gorm"
+)
+
+const cursorPrefix = "id:"
+
+// encodeCursor creates an opaque cursor for the given ID.
+func encodeCursor(id uint) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(cursorPrefix + strconv.Itoa(int(id))))
+}
+
+// decodeCursor returns the ID in a cursor created by encodeCursor.
+func decodeCursor(cursor string) (uint, error) {
+	decoded, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil || !strings.HasPrefix(string(decoded), cursorPrefix) {
+		return 0, errors.New("invalid cursor")
+	}
+
+	id, err := strconv.Atoi(strings.TrimPrefix(string(decoded), cursorPrefix))
+	if err != nil || id < 0 {
+		return 0, errors.New("invalid cursor")
+	}
+
+	return uint(id), nil
+}
+
+// pageSize returns the requested page size, limited to the configured
+// maximum. Without a requested size the fallback is used, a fallback of 0
+// means no limit.
+func pageSize(requested *int32, fallback int) int {
+	maxPageSize := viper.GetInt("api.pagination.maxPageSize")
+	size := fallback
+	if requested != nil {
+		size = int(*requested)
+		if size < 1 {
+			size = 1
+		}
+		if maxPageSize > 0 && size > maxPageSize {
+			size = maxPageSize
+		}
+	}
+	return size
+}
+
+// orderAndLimit applies a stable order and the take argument to the query of
+// an upstream compatible list field.
+func orderAndLimit(query *gorm.DB, idColumn string, take *int32) *gorm.DB {
+	query = query.Order(idColumn)
+
+	if size := pageSize(take, 0); size > 0 {
+		query = query.Limit(size)
+	}
+
+	return query
+}
+
+type PageInfoResolver struct {
+	hasNextPage bool
+	endCursor   *string
+}
+
+func (r *PageInfoResolver) HasNextPage() bool {
+	return r.hasNextPage
+}
+
+func (r *PageInfoResolver) EndCursor() *string {
+	return r.endCursor
+}
+
+type EdgeResolver[R any] struct {
+	cursor string
+	node   R
+}
+
+func (r *EdgeResolver[R]) Cursor() string {
+	return r.cursor
+}
+
+func (r *EdgeResolver[R]) Node() R {
+	return r.node
+}
+
+type ConnectionResolver[R any] struct {
+	edges    []*EdgeResolver[R]
+	pageInfo *PageInfoResolver
+}
+
+func (r *ConnectionResolver[R]) Edges() []*EdgeResolver[R] {
+	return r.edges
+}
+
+func (r *ConnectionResolver[R]) Nodes() []R {
+	nodes := []R{}
+	for i := range r.edges {
+		nodes = append(nodes, r.edges[i].node)
+	}
+	return nodes
+}
+
+func (r *ConnectionResolver[R]) PageInfo() *PageInfoResolver {
+	return r.pageInfo
+}
+
+type ConnectionArgs struct {
+	First *int32
+	After *string
+}
+
+// paginate loads a single page of the query, ordered by ID.
+func paginate[M any, R any](query *gorm.DB, idColumn string, args ConnectionArgs, getID func(*M) uint, newResolver func(*M) (R, error)) (*ConnectionResolver[R], error) {
+	if args.After != nil && *args.After != "" {
+		after, err := decodeCursor(*args.After)
+		if err != nil {
+			return nil, err
+		}
+		query = query.Where(idColumn+" > ?", after)
+	}
+
+	size := pageSize(args.First, viper.GetInt("api.pagination.defaultPageSize"))
+
+	// Load one extra item to know whether there is a next page.
+	models := []*M{}
+	err := query.Order(idColumn).Limit(size + 1).Find(&models).Error
+	if err != nil {
+		return nil, err
+	}
+
+	connection := &ConnectionResolver[R]{
+		edges:    []*EdgeResolver[R]{},
+		pageInfo: &PageInfoResolver{},
+	}
+
+	if len(models) > size {
+		connection.pageInfo.hasNextPage = true
+		models = models[:size]
+	}
+
+	for i := range models {
+		resolver, err := newResolver(models[i])
+		if err != nil {
+			return nil, err
+		}
+
+		cursor := encodeCursor(getID(models[i]))
+		connection.edges = append(connection.edges, &EdgeResolver[R]{cursor: cursor, node: resolver})
+		connection.pageInfo.endCursor = &cursor
+	}
+
+	return connection, nil
+}
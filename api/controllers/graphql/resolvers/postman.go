@@ -0,0 +1,457 @@
+package resolvers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/jerbob92/hoppscotch-backend/helpers/hoppscotch"
+	"github.com/jerbob92/hoppscotch-backend/helpers/postman"
+	"github.com/jerbob92/hoppscotch-backend/models"
+
+	"github.com/google/uuid"
+	"github.com/graph-gophers/graphql-go"
+	"gorm.io/gorm"
+)
+
+type ExportCollectionsToPostmanArgs struct {
+	TeamID       graphql.ID
+	CollectionID *graphql.ID
+}
+
+func (b *BaseQuery) ExportCollectionsToPostman(ctx context.Context, args *ExportCollectionsToPostmanArgs) (string, error) {
+	c := b.GetReqC(ctx)
+	db := c.GetDB()
+
+	userRole, err := getUserRoleInTeam(ctx, c, args.TeamID)
+	if err != nil {
+		return "", err
+	}
+
+	if userRole == nil {
+		return "", errors.New("you do not have access to this team")
+	}
+
+	postmanCollection := &postman.Collection{
+		Info: postman.Info{
+			PostmanID: uuid.New().String(),
+			Schema:    postman.CollectionSchema,
+		},
+		Item: []postman.Item{},
+	}
+
+	if args.CollectionID != nil {
+		collection := &models.TeamCollection{}
+		err := db.Model(&models.TeamCollection{}).Where("id = ? AND team_id = ?", args.CollectionID, args.TeamID).First(collection).Error
+		if err != nil && err == gorm.ErrRecordNotFound {
+			return "", errors.New("you do not have access to this collection")
+		}
+		if err != nil {
+			return "", err
+		}
+
+		collectionExport, err := GetCollectionExportJSON(c, collection)
+		if err != nil {
+			return "", err
+		}
+
+		postmanCollection.Info.Name = collectionExport.Name
+		postmanCollection.Item, err = postmanItemsFromExport(collectionExport.Name, collectionExport.Folders, collectionExport.Requests)
+		if err != nil {
+			return "", err
+		}
+		if collectionExport.Auth != nil {
+			postmanCollection.Auth = postmanAuth(*collectionExport.Auth)
+		}
+		for _, variable := range collectionExport.Variables {
+			postmanCollection.Variable = append(postmanCollection.Variable, postman.Variable{
+				Key:   variable.Key,
+				Value: postmanVariables(variable.Value),
+			})
+		}
+	} else {
+		team := &models.Team{}
+		err := db.Model(&models.Team{}).Where("id = ?", args.TeamID).First(team).Error
+		if err != nil {
+			return "", err
+		}
+
+		teamExport, err := GetTeamExportJSON(c, args.TeamID, 0)
+		if err != nil {
+			return "", err
+		}
+
+		postmanCollection.Info.Name = team.Name
+		postmanCollection.Item, err = postmanItemsFromExport("", teamExport, nil)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	exportJSON, err := json.MarshalIndent(postmanCollection, "", "  ")
+	if err != nil {
+		return "", err
+	}
+
+	return string(exportJSON), nil
+}
+
+type ExportTeamEnvironmentToPostmanArgs struct {
+	ID graphql.ID
+}
+
+func (b *BaseQuery) ExportTeamEnvironmentToPostman(ctx context.Context, args *ExportTeamEnvironmentToPostmanArgs) (string, error) {
+	c := b.GetReqC(ctx)
+	db := c.GetDB()
+
+	teamEnvironment := &models.TeamEnvironment{}
+	err := db.Model(&models.TeamEnvironment{}).Where("id = ?", args.ID).First(teamEnvironment).Error
+	if err != nil && err == gorm.ErrRecordNotFound {
+		return "", errors.New("you do not have access to this team")
+	}
+	if err != nil {
+		return "", err
+	}
+
+	userRole, err := getUserRoleInTeam(ctx, c, teamEnvironment.TeamID)
+	if err != nil {
+		return "", err
+	}
+
+	if userRole == nil {
+		return "", errors.New("you do not have access to this team")
+	}
+
+	variables, err := hoppscotch.ParseEnvironmentVariables(teamEnvironment.Variables)
+	if err != nil {
+		return "", err
+	}
+
+	postmanEnvironment := &postman.Environment{
+		ID:     uuid.New().String(),
+		Name:   teamEnvironment.Name,
+		Values: []postman.EnvironmentValue{},
+		Scope:  "environment",
+	}
+
+	for i := range variables {
+		postmanEnvironment.Values = append(postmanEnvironment.Values, postman.EnvironmentValue{
+			Key:     variables[i].Key,
+			Value:   variables[i].Value,
+			Type:    "default",
+			Enabled: true,
+		})
+	}
+
+	exportJSON, err := json.MarshalIndent(postmanEnvironment, "", "  ")
+	if err != nil {
+		return "", err
+	}
+
+	return string(exportJSON), nil
+}
+
+// postmanItemsFromExport converts the exported folders and requests, the
+// path is the path of the parent collection for errors.
+func postmanItemsFromExport(path string, folders []ExportJSONCollection, requests []ExportJSONCollectionRequest) ([]postman.Item, error) {
+	items := []postman.Item{}
+	for i := range folders {
+		folderPath := folders[i].Name
+		if path != "" {
+			folderPath = path + " / " + folders[i].Name
+		}
+
+		folderItems, err := postmanItemsFromExport(folderPath, folders[i].Folders, folders[i].Requests)
+		if err != nil {
+			return nil, err
+		}
+
+		item := postman.Item{
+			Name: folders[i].Name,
+			Item: folderItems,
+		}
+		// Postman has no collection headers, only the auth is inherited.
+		if folders[i].Auth != nil {
+			item.Auth = postmanAuth(*folders[i].Auth)
+		}
+		items = append(items, item)
+	}
+
+	for i := range requests {
+		name, _ := requests[i]["name"].(string)
+		request, err := hoppscotch.ParseRESTRequestMap(requests[i])
+		if err != nil {
+			return nil, fmt.Errorf("%s / %s: the request could not be read: %w", path, name, err)
+		}
+
+		item := postmanItemFromRequest(request)
+
+		examples, err := hoppscotch.ParseExamples(requests[i][hoppscotch.ExamplesField])
+		if err != nil {
+			return nil, fmt.Errorf("%s / %s: the examples could not be read: %w", path, name, err)
+		}
+		for _, example := range examples {
+			item.Response = append(item.Response, postmanResponse(example, item.Request))
+		}
+
+		items = append(items, item)
+	}
+
+	return items, nil
+}
+
+func postmanResponse(example hoppscotch.ExampleResponse, request *postman.Request) postman.Response {
+	response := postman.Response{
+		Name:            example.Name,
+		OriginalRequest: request,
+		Status:          http.StatusText(example.Status),
+		Code:            example.Status,
+		Header:          []postman.Header{},
+		Body:            example.Body,
+	}
+
+	for _, header := range example.Headers {
+		response.Header = append(response.Header, postman.Header{
+			Key:   header.Key,
+			Value: header.Value,
+		})
+	}
+
+	return response
+}
+
+// postmanVariables converts Hoppscotch <<variables>> to Postman {{variables}}.
+func postmanVariables(input string) string {
+	return hoppscotch.VariablePattern.ReplaceAllString(input, "{{$1}}")
+}
+
+func postmanItemFromRequest(request *hoppscotch.RESTRequest) postman.Item {
+	item := postman.Item{
+		Name: request.Name,
+		Request: &postman.Request{
+			Method: strings.ToUpper(request.Method),
+			Header: []postman.Header{},
+			URL:    postmanURL(request.Endpoint, request.Params),
+		},
+		Response: []postman.Response{},
+	}
+
+	for _, header := range request.Headers {
+		if header.Key == "" {
+			continue
+		}
+		item.Request.Header = append(item.Request.Header, postman.Header{
+			Key:      postmanVariables(header.Key),
+			Value:    postmanVariables(header.Value),
+			Disabled: !header.Active,
+		})
+	}
+
+	item.Request.Auth = postmanAuth(request.Auth)
+	item.Request.Body = postmanBody(request.Body)
+
+	if request.PreRequestScript != "" {
+		item.Event = append(item.Event, postman.Event{
+			Listen: "prerequest",
+			Script: postman.Script{
+				Type: "text/javascript",
+				Exec: strings.Split(request.PreRequestScript, "\n"),
+			},
+		})
+	}
+
+	if request.TestScript != "" {
+		item.Event = append(item.Event, postman.Event{
+			Listen: "test",
+			Script: postman.Script{
+				Type: "text/javascript",
+				Exec: strings.Split(request.TestScript, "\n"),
+			},
+		})
+	}
+
+	return item
+}
+
+func postmanURL(endpoint string, params []hoppscotch.KeyValue) postman.URL {
+	endpoint = postmanVariables(endpoint)
+	output := postman.URL{
+		Raw: endpoint,
+	}
+
+	rest := endpoint
+	if index := strings.Index(rest, "://"); index > -1 {
+		output.Protocol = rest[:index]
+		rest = rest[index+3:]
+	}
+
+	// Query parameters in the endpoint itself are kept in the raw URL only.
+	if index := strings.IndexAny(rest, "?#"); index > -1 {
+		rest = rest[:index]
+	}
+
+	host := rest
+	if index := strings.Index(rest, "/"); index > -1 {
+		host = rest[:index]
+		for _, segment := range strings.Split(rest[index+1:], "/") {
+			output.Path = append(output.Path, segment)
+		}
+	}
+
+	if index := strings.LastIndex(host, ":"); index > -1 && !strings.HasSuffix(host, "}}") {
+		output.Port = host[index+1:]
+		host = host[:index]
+	}
+
+	if host != "" {
+		output.Host = strings.Split(host, ".")
+	}
+
+	activeParams := []string{}
+	for _, param := range params {
+		if param.Key == "" {
+			continue
+		}
+
+		output.Query = append(output.Query, postman.QueryParam{
+			Key:      postmanVariables(param.Key),
+			Value:    postmanVariables(param.Value),
+			Disabled: !param.Active,
+		})
+
+		if param.Active {
+			activeParams = append(activeParams, postmanVariables(param.Key)+"="+postmanVariables(param.Value))
+		}
+	}
+
+	if len(activeParams) > 0 {
+		separator := "?"
+		if strings.Contains(output.Raw, "?") {
+			separator = "&"
+		}
+		output.Raw += separator + strings.Join(activeParams, "&")
+	}
+
+	return output
+}
+
+func postmanAuth(auth hoppscotch.Auth) *postman.Auth {
+	if !auth.AuthActive || auth.AuthType == hoppscotch.AuthTypeInherit {
+		return nil
+	}
+
+	switch auth.AuthType {
+	case hoppscotch.AuthTypeBasic:
+		return &postman.Auth{
+			Type: "basic",
+			Basic: []postman.AuthAttribute{
+				{Key: "username", Value: postmanVariables(auth.Username), Type: "string"},
+				{Key: "password", Value: postmanVariables(auth.Password), Type: "string"},
+			},
+		}
+	case hoppscotch.AuthTypeBearer:
+		return &postman.Auth{
+			Type: "bearer",
+			Bearer: []postman.AuthAttribute{
+				{Key: "token", Value: postmanVariables(auth.Token), Type: "string"},
+			},
+		}
+	case hoppscotch.AuthTypeAPIKey:
+		in := "header"
+		if auth.AddTo == "Query params" {
+			in = "query"
+		}
+		return &postman.Auth{
+			Type: "apikey",
+			APIKey: []postman.AuthAttribute{
+				{Key: "key", Value: postmanVariables(auth.Key), Type: "string"},
+				{Key: "value", Value: postmanVariables(auth.Value), Type: "string"},
+				{Key: "in", Value: in, Type: "string"},
+			},
+		}
+	case hoppscotch.AuthTypeOAuth2:
+		return &postman.Auth{
+			Type: "oauth2",
+			OAuth2: []postman.AuthAttribute{
+				{Key: "accessToken", Value: postmanVariables(auth.Token), Type: "string"},
+				{Key: "authUrl", Value: postmanVariables(auth.AuthURL), Type: "string"},
+				{Key: "accessTokenUrl", Value: postmanVariables(auth.AccessTokenURL), Type: "string"},
+				{Key: "clientId", Value: postmanVariables(auth.ClientID), Type: "string"},
+				{Key: "scope", Value: postmanVariables(auth.Scope), Type: "string"},
+			},
+		}
+	}
+
+	return &postman.Auth{Type: "noauth"}
+}
+
+func postmanBody(body hoppscotch.Body) *postman.Body {
+	if body.ContentType == nil {
+		return nil
+	}
+
+	switch *body.ContentType {
+	case hoppscotch.ContentTypeMultipart:
+		output := &postman.Body{
+			Mode:     "formdata",
+			FormData: []postman.FormParam{},
+		}
+		for _, field := range body.FormData {
+			param := postman.FormParam{
+				Key:      postmanVariables(field.Key),
+				Type:     "text",
+				Disabled: !field.Active,
+			}
+			if field.IsFile {
+				param.Type = "file"
+			} else {
+				param.Value = postmanVariables(field.Value)
+			}
+			output.FormData = append(output.FormData, param)
+		}
+		return output
+	case hoppscotch.ContentTypeForm:
+		output := &postman.Body{
+			Mode:       "urlencoded",
+			URLEncoded: []postman.FormParam{},
+		}
+		if body.Raw != nil {
+			for _, field := range hoppscotch.ParseRawKeyValue(*body.Raw) {
+				output.URLEncoded = append(output.URLEncoded, postman.FormParam{
+					Key:      postmanVariables(field.Key),
+					Value:    postmanVariables(field.Value),
+					Type:     "text",
+					Disabled: !field.Active,
+				})
+			}
+		}
+		return output
+	}
+
+	if body.Raw == nil {
+		return nil
+	}
+
+	language := "text"
+	switch *body.ContentType {
+	case hoppscotch.ContentTypeJSON, hoppscotch.ContentTypeLDJSON, hoppscotch.ContentTypeHALJSON, hoppscotch.ContentTypeVNDAPIJSON:
+		language = "json"
+	case hoppscotch.ContentTypeXML:
+		language = "xml"
+	case hoppscotch.ContentTypeHTML:
+		language = "html"
+	}
+
+	return &postman.Body{
+		Mode: "raw",
+		Raw:  postmanVariables(*body.Raw),
+		Options: &postman.BodyOptions{
+			Raw: postman.BodyRawOptions{
+				Language: language,
+			},
+		},
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 
 	graphql_context "github.com/jerbob92/hoppscotch-backend/api/controllers/graphql/context"
+	"github.com/jerbob92/hoppscotch-backend/api/controllers/graphql/loaders"
 
 	"github.com/gin-gonic/gin"
 )
@@ -13,6 +14,26 @@ type BaseQuery struct {
 	c *graphql_context.Context // Only set/overwrite this context when constructing new baseQueries.
 }
 
+// OperationContext returns ctx with a request context that has its own
+// loaders, it is created once per operation so the loaders batch every field
+// of the operation.
+func OperationContext(ctx context.Context) context.Context {
+	c := (&BaseQuery{}).GetReqC(ctx)
+	if c == nil {
+		return ctx
+	}
+	c.Loaders = loaders.New(c.GetDB())
+	return context.WithValue(ctx, "graphqlC", c)
+}
+
+// eventContext returns a copy of the context of a subscription with its own
+// loaders, every delivered event is resolved with fresh data.
+func eventContext(c *graphql_context.Context) *graphql_context.Context {
+	eventC := c.Clone()
+	eventC.Loaders = loaders.New(eventC.GetDB())
+	return eventC
+}
+
 // GetReqC returns the request context
 func (b *BaseQuery) GetReqC(ctx context.Context) *graphql_context.Context {
 	if b.c == nil {
@@ -0,0 +1,410 @@
+package resolvers
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync"
+	"time"
+
+	graphql_context "github.com/jerbob92/hoppscotch-backend/api/controllers/graphql/context"
+	"github.com/jerbob92/hoppscotch-backend/db"
+	"github.com/jerbob92/hoppscotch-backend/models"
+
+	"github.com/graph-gophers/graphql-go"
+	"github.com/sanae10001/graphql-go-extension-scalars"
+	"github.com/spf13/viper"
+	"gorm.io/gorm"
+)
+
+// requestLock is a soft lock on a request, held by the user that is editing
+// it. Locks are kept in memory, like the event bus.
+type requestLock struct {
+	requestID  uint
+	teamID     uint
+	userID     uint
+	acquiredAt time.Time
+	expiresAt  time.Time
+	timer      *time.Timer
+}
+
+type lockSessionKey struct {
+	userID uint
+	teamID uint
+}
+
+type requestLockRegistry struct {
+	lock     sync.Mutex
+	locks    map[uint]*requestLock
+	sessions map[lockSessionKey]int
+}
+
+var requestLocks = &requestLockRegistry{
+	locks:    map[uint]*requestLock{},
+	sessions: map[lockSessionKey]int{},
+}
+
+// LockedError is returned when a request is locked by someone else.
+type LockedError struct {
+	holder    *models.User
+	expiresAt time.Time
+}
+
+func (e *LockedError) Error() string {
+	name := e.holder.DisplayName
+	if name == "" {
+		name = e.holder.Email
+	}
+	return "this request is being edited by " + name
+}
+
+func (e *LockedError) Extensions() map[string]interface{} {
+	return map[string]interface{}{
+		"code":      "LOCKED",
+		"holder":    e.holder.FBUID,
+		"expiresOn": e.expiresAt.Format(time.RFC3339),
+	}
+}
+
+// lockTTL returns the requested TTL in seconds, limited to the configured
+// maximum.
+func lockTTL(requested *int32) time.Duration {
+	ttl := viper.GetInt("api.locks.ttl")
+	if requested != nil && *requested > 0 {
+		ttl = int(*requested)
+	}
+	if maxTTL := viper.GetInt("api.locks.maxTTL"); maxTTL > 0 && ttl > maxTTL {
+		ttl = maxTTL
+	}
+	return time.Duration(ttl) * time.Second
+}
+
+// Get returns the lock on the request, or nil when it isn't locked.
+func (r *requestLockRegistry) Get(requestID uint) *requestLock {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	lock, ok := r.locks[requestID]
+	if !ok || time.Now().After(lock.expiresAt) {
+		return nil
+	}
+	copied := *lock
+	return &copied
+}
+
+// Acquire locks the request for the user, or extends the lock when the user
+// already holds it. When someone else holds the lock, that lock is returned
+// with false.
+func (r *requestLockRegistry) Acquire(request *models.TeamRequest, userID uint, ttl time.Duration) (*requestLock, bool) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	now := time.Now()
+	lock, ok := r.locks[request.ID]
+	if ok && lock.userID != userID && now.Before(lock.expiresAt) {
+		copied := *lock
+		return &copied, false
+	}
+
+	if !ok || lock.userID != userID || now.After(lock.expiresAt) {
+		if ok {
+			lock.timer.Stop()
+		}
+		lock = &requestLock{
+			requestID:  request.ID,
+			teamID:     request.TeamID,
+			userID:     userID,
+			acquiredAt: now,
+		}
+		r.locks[request.ID] = lock
+	} else {
+		lock.timer.Stop()
+	}
+
+	lock.expiresAt = now.Add(ttl)
+	expiredLock := lock
+	lock.timer = time.AfterFunc(ttl, func() {
+		r.expire(expiredLock)
+	})
+
+	copied := *lock
+	return &copied, true
+}
+
+// Release removes the lock of the user on the request, it returns false when
+// the user doesn't hold it.
+func (r *requestLockRegistry) Release(requestID uint, userID uint) bool {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	lock, ok := r.locks[requestID]
+	if !ok || lock.userID != userID {
+		return false
+	}
+
+	lock.timer.Stop()
+	delete(r.locks, requestID)
+	return time.Now().Before(lock.expiresAt)
+}
+
+// Remove removes the lock on a deleted request, whoever holds it.
+func (r *requestLockRegistry) Remove(requestID uint) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	lock, ok := r.locks[requestID]
+	if !ok {
+		return
+	}
+
+	lock.timer.Stop()
+	delete(r.locks, requestID)
+}
+
+func (r *requestLockRegistry) expire(expiredLock *requestLock) {
+	r.lock.Lock()
+	lock, ok := r.locks[expiredLock.requestID]
+	if !ok || lock != expiredLock || time.Now().Before(lock.expiresAt) {
+		r.lock.Unlock()
+		return
+	}
+	delete(r.locks, lock.requestID)
+	r.lock.Unlock()
+
+	publishRequestLockChange(lock.requestID)
+}
+
+// trackSession keeps the locks of the user in the team while the context is
+// active, when the last tracked context of the user ends all their locks in
+// the team are released.
+func (r *requestLockRegistry) trackSession(ctx context.Context, userID uint, teamID uint) {
+	key := lockSessionKey{userID: userID, teamID: teamID}
+
+	r.lock.Lock()
+	r.sessions[key]++
+	r.lock.Unlock()
+
+	go func() {
+		<-ctx.Done()
+
+		r.lock.Lock()
+		r.sessions[key]--
+		if r.sessions[key] > 0 {
+			r.lock.Unlock()
+			return
+		}
+		delete(r.sessions, key)
+
+		released := []uint{}
+		for requestID, lock := range r.locks {
+			if lock.userID == userID && lock.teamID == teamID {
+				lock.timer.Stop()
+				delete(r.locks, requestID)
+				released = append(released, requestID)
+			}
+		}
+		r.lock.Unlock()
+
+		for _, requestID := range released {
+			publishRequestLockChange(requestID)
+		}
+	}()
+}
+
+// publishRequestLockChange publishes the request as updated, so the
+// subscribers get the new lock. Locks also change after the request that
+// changed them has ended, so no request context is used, the subscribers
+// resolve the request with their own.
+func publishRequestLockChange(requestID uint) {
+	if db.DB == nil {
+		return
+	}
+
+	request := &models.TeamRequest{}
+	err := db.DB.Model(&models.TeamRequest{}).Where("id = ?", requestID).First(request).Error
+	if err != nil {
+		return
+	}
+
+	resolver, err := NewTeamRequestResolver(nil, request)
+	if err != nil {
+		return
+	}
+
+	go bus.Publish("team:"+strconv.Itoa(int(request.TeamID))+":requests:updated", resolver)
+}
+
+// checkRequestLock returns a LockedError when the request is locked by
+// someone else than the user, unless the write is forced.
+func checkRequestLock(c *graphql_context.Context, requestID uint, userID uint, force *bool) error {
+	if force != nil && *force {
+		return nil
+	}
+
+	lock := requestLocks.Get(requestID)
+	if lock == nil || lock.userID == userID {
+		return nil
+	}
+
+	holder, found, err := c.GetLoaders().User.Load(lock.userID)
+	if err != nil {
+		return err
+	}
+	if !found {
+		return nil
+	}
+
+	return &LockedError{holder: holder, expiresAt: lock.expiresAt}
+}
+
+// checkCollectionLocks returns a LockedError when someone else than the user
+// holds the lock on a request in one of the collections.
+func checkCollectionLocks(c *graphql_context.Context, collectionIDs []uint, userID uint) error {
+	if len(collectionIDs) == 0 {
+		return nil
+	}
+
+	requestIDs := []uint{}
+	err := c.GetDB().Model(&models.TeamRequest{}).Where("team_collection_id IN ?", collectionIDs).Pluck("id", &requestIDs).Error
+	if err != nil {
+		return err
+	}
+
+	for _, requestID := range requestIDs {
+		err := checkRequestLock(c, requestID, userID, nil)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type RequestLockResolver struct {
+	c    *graphql_context.Context
+	lock *requestLock
+}
+
+func (r *RequestLockResolver) Holder() (*UserResolver, error) {
+	holder, found, err := r.c.GetLoaders().User.Load(r.lock.userID)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, errors.New("holder not found")
+	}
+	return NewUserResolver(r.c, holder)
+}
+
+func (r *RequestLockResolver) AcquiredOn() scalars.DateTime {
+	return *scalars.NewDateTime(r.lock.acquiredAt)
+}
+
+func (r *RequestLockResolver) ExpiresOn() scalars.DateTime {
+	return *scalars.NewDateTime(r.lock.expiresAt)
+}
+
+func (r *TeamRequestResolver) Lock() *RequestLockResolver {
+	lock := requestLocks.Get(r.team_request.ID)
+	if lock == nil {
+		return nil
+	}
+	return &RequestLockResolver{c: r.c, lock: lock}
+}
+
+// getLockableRequest loads the request for a lock mutation, only editors can
+// lock a request.
+func getLockableRequest(ctx context.Context, c *graphql_context.Context, requestID graphql.ID) (*models.TeamRequest, *models.User, error) {
+	db := c.GetDB()
+	request := &models.TeamRequest{}
+	err := db.Model(&models.TeamRequest{}).Where("id = ?", requestID).First(request).Error
+	if err != nil && err == gorm.ErrRecordNotFound {
+		return nil, nil, errors.New("you do not have access to this request")
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	userRole, err := getUserRoleInTeam(ctx, c, request.TeamID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if userRole == nil {
+		return nil, nil, errors.New("you do not have access to this request")
+	}
+
+	if *userRole != models.Owner && *userRole != models.Editor {
+		return nil, nil, errors.New("you are not allowed to edit a request in this team")
+	}
+
+	currentUser, err := c.GetUser(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return request, currentUser, nil
+}
+
+type RequestLockArgs struct {
+	RequestID graphql.ID
+	TTL       *int32
+}
+
+func (b *BaseQuery) AcquireRequestLock(ctx context.Context, args *RequestLockArgs) (*RequestLockResolver, error) {
+	c := b.GetReqC(ctx)
+	request, currentUser, err := getLockableRequest(ctx, c, args.RequestID)
+	if err != nil {
+		return nil, err
+	}
+
+	lock, acquired := requestLocks.Acquire(request, currentUser.ID, lockTTL(args.TTL))
+	if !acquired {
+		return nil, checkRequestLock(c, request.ID, currentUser.ID, nil)
+	}
+
+	publishRequestLockChange(request.ID)
+
+	return &RequestLockResolver{c: c, lock: lock}, nil
+}
+
+func (b *BaseQuery) RenewRequestLock(ctx context.Context, args *RequestLockArgs) (*RequestLockResolver, error) {
+	c := b.GetReqC(ctx)
+	request, currentUser, err := getLockableRequest(ctx, c, args.RequestID)
+	if err != nil {
+		return nil, err
+	}
+
+	existingLock := requestLocks.Get(request.ID)
+	if existingLock == nil || existingLock.userID != currentUser.ID {
+		return nil, errors.New("you do not hold the lock on this request")
+	}
+
+	lock, acquired := requestLocks.Acquire(request, currentUser.ID, lockTTL(args.TTL))
+	if !acquired {
+		return nil, checkRequestLock(c, request.ID, currentUser.ID, nil)
+	}
+
+	publishRequestLockChange(request.ID)
+
+	return &RequestLockResolver{c: c, lock: lock}, nil
+}
+
+type ReleaseRequestLockArgs struct {
+	RequestID graphql.ID
+}
+
+func (b *BaseQuery) ReleaseRequestLock(ctx context.Context, args *ReleaseRequestLockArgs) (bool, error) {
+	c := b.GetReqC(ctx)
+	request, currentUser, err := getLockableRequest(ctx, c, args.RequestID)
+	if err != nil {
+		return false, err
+	}
+
+	if !requestLocks.Release(request.ID, currentUser.ID) {
+		return false, nil
+	}
+
+	publishRequestLockChange(request.ID)
+
+	return true, nil
+}
@@ -0,0 +1,199 @@
+package resolvers
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+
+	graphql_context "github.com/jerbob92/hoppscotch-backend/api/controllers/graphql/context"
+	"github.com/jerbob92/hoppscotch-backend/db"
+	"github.com/jerbob92/hoppscotch-backend/helpers/hoppscotch"
+	"github.com/jerbob92/hoppscotch-backend/helpers/search"
+	"github.com/jerbob92/hoppscotch-backend/models"
+
+	"github.com/graph-gophers/graphql-go"
+	"github.com/spf13/viper"
+	"gorm.io/gorm"
+)
+
+// maxSearchCandidates is the maximum amount of requests loaded to build a
+// page of search results.
+const maxSearchCandidates = 500
+
+type RequestSearchResultResolver struct {
+	c          *graphql_context.Context
+	request    *models.TeamRequest
+	score      float64
+	highlights []search.Highlight
+}
+
+func (r *RequestSearchResultResolver) Request() (*TeamRequestResolver, error) {
+	return NewTeamRequestResolver(r.c, r.request)
+}
+
+func (r *RequestSearchResultResolver) Score() float64 {
+	return r.score
+}
+
+func (r *RequestSearchResultResolver) Highlights() []*SearchHighlightResolver {
+	highlightResolvers := []*SearchHighlightResolver{}
+	for i := range r.highlights {
+		highlightResolvers = append(highlightResolvers, &SearchHighlightResolver{highlight: r.highlights[i]})
+	}
+	return highlightResolvers
+}
+
+type SearchHighlightResolver struct {
+	highlight search.Highlight
+}
+
+func (r *SearchHighlightResolver) Field() string {
+	return r.highlight.Field
+}
+
+func (r *SearchHighlightResolver) Snippet() string {
+	return r.highlight.Snippet
+}
+
+func (r *SearchHighlightResolver) Ranges() []*SearchHighlightRangeResolver {
+	rangeResolvers := []*SearchHighlightRangeResolver{}
+	for _, highlightRange := range r.highlight.Ranges {
+		rangeResolvers = append(rangeResolvers, &SearchHighlightRangeResolver{highlightRange: highlightRange})
+	}
+	return rangeResolvers
+}
+
+type SearchHighlightRangeResolver struct {
+	highlightRange search.Range
+}
+
+func (r *SearchHighlightRangeResolver) Start() int32 {
+	return int32(r.highlightRange.Start)
+}
+
+func (r *SearchHighlightRangeResolver) Length() int32 {
+	return int32(r.highlightRange.Length)
+}
+
+type searchRow struct {
+	models.TeamRequest
+	Score float64
+}
+
+// fullTextSearchQuery adds the full-text condition and ranking of the
+// configured driver to the query, models.CreateSearchIndexes made sure the
+// driver has a full-text index.
+func fullTextSearchQuery(query *gorm.DB, terms []string) *gorm.DB {
+	switch db.Driver() {
+	case "mysql":
+		booleanQuery := strings.Join(terms, "* ") + "*"
+		return query.
+			Select("team_requests.*, MATCH(team_requests.title, team_requests.request) AGAINST (? IN BOOLEAN MODE) AS score", booleanQuery).
+			Where("MATCH(team_requests.title, team_requests.request) AGAINST (? IN BOOLEAN MODE)", booleanQuery)
+	case "postgres":
+		tsQuery := strings.Join(terms, ":* | ") + ":*"
+		return query.
+			Select("team_requests.*, ts_rank("+models.PostgresSearchVector+", to_tsquery('simple', ?)) AS score", tsQuery).
+			Where(models.PostgresSearchVector+" @@ to_tsquery('simple', ?)", tsQuery)
+	case "mssql":
+		containsQuery := "\"" + strings.Join(terms, "*\" OR \"") + "*\""
+		return query.
+			Select("team_requests.*, search.[RANK] AS score").
+			Joins("INNER JOIN CONTAINSTABLE(team_requests, (title, request), ?) AS search ON team_requests.id = search.[KEY]", containsQuery)
+	}
+	return query
+}
+
+type SearchRequestsArgs struct {
+	SearchTerm string
+	TeamID     *graphql.ID
+	Take       *int32
+}
+
+// SearchRequests searches the title, URL, headers and body of the requests in
+// all teams of the current user, or in the given team.
+func (b *BaseQuery) SearchRequests(ctx context.Context, args *SearchRequestsArgs) ([]*RequestSearchResultResolver, error) {
+	c := b.GetReqC(ctx)
+	db := c.GetDB()
+
+	currentUser, err := c.GetUser(ctx)
+	if err != nil {
+		c.LogErr(err)
+		return nil, err
+	}
+
+	terms := search.ParseTerms(args.SearchTerm)
+	if len(terms) == 0 {
+		return []*RequestSearchResultResolver{}, nil
+	}
+
+	query := db.Model(&models.TeamRequest{}).Where("team_requests.team_id IN (?)", db.Model(&models.TeamMember{}).Select("team_id").Where("user_id = ?", currentUser.ID))
+	if args.TeamID != nil {
+		userRole, err := getUserRoleInTeam(ctx, c, *args.TeamID)
+		if err != nil {
+			return nil, err
+		}
+		if userRole == nil {
+			return nil, errors.New("user not in team")
+		}
+		query = query.Where("team_requests.team_id = ?", *args.TeamID)
+	}
+
+	size := pageSize(args.Take, viper.GetInt("api.pagination.defaultPageSize"))
+
+	// Matches on the JSON keys of the request data are dropped below, so load
+	// more candidates than needed.
+	candidates := size * 3
+	if candidates > maxSearchCandidates {
+		candidates = maxSearchCandidates
+	}
+
+	rows := []searchRow{}
+	if models.FullTextSearch {
+		err = fullTextSearchQuery(query, terms).Order("score DESC").Limit(candidates).Scan(&rows).Error
+	} else {
+		conditions := db.Where("1 = 0")
+		for _, term := range terms {
+			term = "%" + strings.NewReplacer("%", "\\%", "_", "\\_").Replace(term) + "%"
+			conditions = conditions.Or("(LOWER(team_requests.title) LIKE ? OR LOWER(team_requests.request) LIKE ?)", term, term)
+		}
+		err = query.Where(conditions).Order("team_requests.updated_at DESC").Limit(maxSearchCandidates).Scan(&rows).Error
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	results := []*RequestSearchResultResolver{}
+	for i := range rows {
+		request, _ := hoppscotch.ParseRESTRequest(currentRequestJSON(&rows[i].TeamRequest))
+		document := search.NewDocument(rows[i].Title, request)
+
+		highlights := document.Highlights(terms)
+		if len(highlights) == 0 {
+			continue
+		}
+
+		score := rows[i].Score
+		if !models.FullTextSearch {
+			score = document.Score(terms)
+		}
+
+		results = append(results, &RequestSearchResultResolver{
+			c:          c,
+			request:    &rows[i].TeamRequest,
+			score:      score,
+			highlights: highlights,
+		})
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].score > results[j].score
+	})
+
+	if len(results) > size {
+		results = results[:size]
+	}
+
+	return results, nil
+}
@@ -131,6 +131,7 @@ func (b *BaseQuery) RevokeShortcode(ctx context.Context, args *RevokeShortcodeAr
 
 type MyShortcodeArgs struct {
 	Cursor *graphql.ID
+	Take   *int32
 }
 
 func (b BaseQuery) MyShortcodes(ctx context.Context, args *MyShortcodeArgs) ([]*ShortcodeResolver, error) {
@@ -144,8 +145,9 @@ func (b BaseQuery) MyShortcodes(ctx context.Context, args *MyShortcodeArgs) ([]*
 	db := c.GetDB()
 	query := db.Model(&models.Shortcode{}).Where("user_id = ?", currentUser.ID)
 	if args.Cursor != nil && *args.Cursor != "" {
-		query.Where("id > ?", args.Cursor)
+		query = query.Where("id > ?", args.Cursor)
 	}
+	query = orderAndLimit(query, "id", args.Take)
 	err = query.Find(&shortcodes).Error
 	if err != nil {
 		return nil, err
@@ -163,6 +165,20 @@ func (b BaseQuery) MyShortcodes(ctx context.Context, args *MyShortcodeArgs) ([]*
 	return shortcodesResolvers, nil
 }
 
+func (b BaseQuery) MyShortcodesConnection(ctx context.Context, args *ConnectionArgs) (*ConnectionResolver[*ShortcodeResolver], error) {
+	c := b.GetReqC(ctx)
+	currentUser, err := c.GetUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	db := c.GetDB()
+	query := db.Model(&models.Shortcode{}).Where("user_id = ?", currentUser.ID)
+	return paginate(query, "id", *args, func(m *models.Shortcode) uint { return m.ID }, func(m *models.Shortcode) (*ShortcodeResolver, error) {
+		return NewShortcodeResolver(c, m)
+	})
+}
+
 func (b *BaseQuery) MyShortcodesCreated(ctx context.Context) (<-chan *ShortcodeResolver, error) {
 	c := b.GetReqC(ctx)
 	currentUser, err := c.GetUser(ctx)
@@ -46,6 +46,7 @@ func (r *TeamResolver) EditorsCount() (int32, error) {
 
 type TeamMembersArgs struct {
 	Cursor *graphql.ID
+	Take   *int32
 }
 
 func (r *TeamResolver) Members(args *TeamMembersArgs) ([]*TeamMemberResolver, error) {
@@ -54,8 +55,9 @@ func (r *TeamResolver) Members(args *TeamMembersArgs) ([]*TeamMemberResolver, er
 
 	query := db.Model(&models.TeamMember{}).Where("team_id = ?", r.team.ID)
 	if args.Cursor != nil && *args.Cursor != "" {
-		query.Where("id > ?", args.Cursor)
+		query = query.Where("id > ?", args.Cursor)
 	}
+	query = orderAndLimit(query, "id", args.Take)
 
 	err := query.Find(&members).Error
 	if err != nil {
@@ -74,6 +76,14 @@ func (r *TeamResolver) Members(args *TeamMembersArgs) ([]*TeamMemberResolver, er
 	return teamMemberResolves, nil
 }
 
+func (r *TeamResolver) MembersConnection(args *ConnectionArgs) (*ConnectionResolver[*TeamMemberResolver], error) {
+	db := r.c.GetDB()
+	query := db.Model(&models.TeamMember{}).Where("team_id = ?", r.team.ID)
+	return paginate(query, "id", *args, func(m *models.TeamMember) uint { return m.ID }, func(m *models.TeamMember) (*TeamMemberResolver, error) {
+		return NewTeamMemberResolver(r.c, m)
+	})
+}
+
 func (r *TeamResolver) MyRole(ctx context.Context) (models.TeamMemberRole, error) {
 	currentUser, err := r.c.GetUser(ctx)
 	if err != nil {
@@ -183,6 +193,7 @@ func (r *TeamResolver) TeamEnvironments() ([]*TeamEnvironmentResolver, error) {
 
 type MyTeamsArgs struct {
 	Cursor *graphql.ID
+	Take   *int32
 }
 
 func (b *BaseQuery) MyTeams(ctx context.Context, args *MyTeamsArgs) ([]*TeamResolver, error) {
@@ -197,8 +208,9 @@ func (b *BaseQuery) MyTeams(ctx context.Context, args *MyTeamsArgs) ([]*TeamReso
 	teams := []*models.Team{}
 	query := db.Model(&models.Team{}).Joins("JOIN team_members ON team_members.team_id = teams.id").Where("team_members.user_id = ? AND team_members.deleted_at IS NULL", currentUser.ID)
 	if args.Cursor != nil && *args.Cursor != "" {
-		query.Where("id > ?", args.Cursor)
+		query = query.Where("teams.id > ?", args.Cursor)
 	}
+	query = orderAndLimit(query, "teams.id", args.Take)
 
 	err = query.Find(&teams).Error
 	if err != nil {
@@ -217,6 +229,21 @@ func (b *BaseQuery) MyTeams(ctx context.Context, args *MyTeamsArgs) ([]*TeamReso
 	return teamResolvers, nil
 }
 
+func (b *BaseQuery) MyTeamsConnection(ctx context.Context, args *ConnectionArgs) (*ConnectionResolver[*TeamResolver], error) {
+	c := b.GetReqC(ctx)
+	currentUser, err := c.GetUser(ctx)
+	if err != nil {
+		c.LogErr(err)
+		return nil, err
+	}
+
+	db := c.GetDB()
+	query := db.Model(&models.Team{}).Joins("JOIN team_members ON team_members.team_id = teams.id").Where("team_members.user_id = ? AND team_members.deleted_at IS NULL", currentUser.ID)
+	return paginate(query, "teams.id", *args, func(m *models.Team) uint { return m.ID }, func(m *models.Team) (*TeamResolver, error) {
+		return NewTeamResolver(c, m)
+	})
+}
+
 type RequestArg struct {
 	RequestID graphql.ID
 }
@@ -247,6 +274,7 @@ func (b *BaseQuery) Request(ctx context.Context, args *RequestArg) (*TeamRequest
 
 type RootCollectionsOfTeamArgs struct {
 	Cursor *graphql.ID
+	Take   *int32
 	TeamID graphql.ID
 }
 
@@ -264,8 +292,9 @@ func (b *BaseQuery) RootCollectionsOfTeam(ctx context.Context, args *RootCollect
 	teamCollections := []*models.TeamCollection{}
 	query := db.Model(&models.TeamCollection{}).Where("team_id = ? AND parent_id = ?", args.TeamID, 0)
 	if args.Cursor != nil && *args.Cursor != "" {
-		query.Where("id > ?", args.Cursor)
+		query = query.Where("id > ?", args.Cursor)
 	}
+	query = orderAndLimit(query, "id", args.Take)
 	err = query.Find(&teamCollections).Error
 	if err != nil {
 		return nil, err
@@ -283,8 +312,31 @@ func (b *BaseQuery) RootCollectionsOfTeam(ctx context.Context, args *RootCollect
 	return teamCollectionResolvers, nil
 }
 
+type RootCollectionsOfTeamConnectionArgs struct {
+	ConnectionArgs
+	TeamID graphql.ID
+}
+
+func (b *BaseQuery) RootCollectionsOfTeamConnection(ctx context.Context, args *RootCollectionsOfTeamConnectionArgs) (*ConnectionResolver[*TeamCollectionResolver], error) {
+	c := b.GetReqC(ctx)
+	userRole, err := getUserRoleInTeam(ctx, c, args.TeamID)
+	if err != nil {
+		return nil, err
+	}
+	if userRole == nil {
+		return nil, errors.New("user not in team")
+	}
+
+	db := c.GetDB()
+	query := db.Model(&models.TeamCollection{}).Where("team_id = ? AND parent_id = ?", args.TeamID, 0)
+	return paginate(query, "id", args.ConnectionArgs, func(m *models.TeamCollection) uint { return m.ID }, func(m *models.TeamCollection) (*TeamCollectionResolver, error) {
+		return NewTeamCollectionResolver(c, m)
+	})
+}
+
 type SearchForRequestArgs struct {
 	Cursor     *graphql.ID
+	Take       *int32
 	SearchTerm string
 	TeamID     graphql.ID
 }
@@ -306,8 +358,9 @@ func (b *BaseQuery) SearchForRequest(ctx context.Context, args *SearchForRequest
 	args.SearchTerm = "%" + args.SearchTerm + "%"
 	query := db.Model(&models.TeamRequest{}).Where("team_id = ? AND title LIKE ?", args.TeamID, args.SearchTerm)
 	if args.Cursor != nil && *args.Cursor != "" {
-		query.Where("id > ?", args.Cursor)
+		query = query.Where("id > ?", args.Cursor)
 	}
+	query = orderAndLimit(query, "id", args.Take)
 	err = query.Find(&teamRequests).Error
 	if err != nil {
 		return nil, err
@@ -325,6 +378,32 @@ func (b *BaseQuery) SearchForRequest(ctx context.Context, args *SearchForRequest
 	return teamRequestResolvers, nil
 }
 
+type SearchForRequestConnectionArgs struct {
+	ConnectionArgs
+	SearchTerm string
+	TeamID     graphql.ID
+}
+
+func (b *BaseQuery) SearchForRequestConnection(ctx context.Context, args *SearchForRequestConnectionArgs) (*ConnectionResolver[*TeamRequestResolver], error) {
+	c := b.GetReqC(ctx)
+	userRole, err := getUserRoleInTeam(ctx, c, args.TeamID)
+	if err != nil {
+		return nil, err
+	}
+	if userRole == nil {
+		return nil, errors.New("user not in team")
+	}
+
+	db := c.GetDB()
+	searchTerm := strings.Replace(args.SearchTerm, "%", "\\%", -1)
+	searchTerm = strings.Replace(searchTerm, "_", "\\_", -1)
+	searchTerm = "%" + searchTerm + "%"
+	query := db.Model(&models.TeamRequest{}).Where("team_id = ? AND title LIKE ?", args.TeamID, searchTerm)
+	return paginate(query, "id", args.ConnectionArgs, func(m *models.TeamRequest) uint { return m.ID }, func(m *models.TeamRequest) (*TeamRequestResolver, error) {
+		return NewTeamRequestResolver(c, m)
+	})
+}
+
 type TeamArgs struct {
 	TeamID graphql.ID
 }
@@ -4,10 +4,13 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"log"
 	"strconv"
 
 	graphql_context "github.com/jerbob92/hoppscotch-backend/api/controllers/graphql/context"
+	"github.com/jerbob92/hoppscotch-backend/api/controllers/graphql/loaders"
+	"github.com/jerbob92/hoppscotch-backend/helpers/hoppscotch"
 	"github.com/jerbob92/hoppscotch-backend/models"
 
 	"github.com/graph-gophers/graphql-go"
@@ -37,26 +40,26 @@ func (r *TeamCollectionResolver) Parent() (*TeamCollectionResolver, error) {
 		return nil, nil
 	}
 
-	db := r.c.GetDB()
-	teamCollection := &models.TeamCollection{}
-	err := db.Where("id = ?", r.team_collection.ParentID).First(teamCollection).Error
-	if err != nil && err == gorm.ErrRecordNotFound {
-		return nil, errors.New("team collection not found")
-	}
-
+	teamCollection, found, err := r.c.GetLoaders().TeamCollection.Load(r.team_collection.ParentID)
 	if err != nil {
 		log.Println(err)
 		return nil, err
 	}
 
+	if !found {
+		return nil, errors.New("team collection not found")
+	}
+
 	return NewTeamCollectionResolver(r.c, teamCollection)
 }
 
 func (r *TeamCollectionResolver) Team() (*TeamResolver, error) {
-	db := r.c.GetDB()
-	team := &models.Team{}
-	err := db.Where("id = ?", r.team_collection.TeamID).First(team).Error
-	if err != nil && err == gorm.ErrRecordNotFound {
+	team, found, err := r.c.GetLoaders().Team.Load(r.team_collection.TeamID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !found {
 		return nil, errors.New("team collection not found")
 	}
 
@@ -65,18 +68,28 @@ func (r *TeamCollectionResolver) Team() (*TeamResolver, error) {
 
 type TeamCollectionChildrenArgs struct {
 	Cursor *string
+	Take   *int32
 }
 
 func (r *TeamCollectionResolver) Children(args *TeamCollectionChildrenArgs) ([]*TeamCollectionResolver, error) {
-	db := r.c.GetDB()
 	teamCollections := []*models.TeamCollection{}
-	query := db.Model(&models.TeamCollection{}).Where("parent_id = ?", r.team_collection.ID)
-	if args.Cursor != nil && *args.Cursor != "" {
-		query.Where("id > ?", args.Cursor)
-	}
-	err := query.Preload("Team").Find(&teamCollections).Error
-	if err != nil {
-		return nil, err
+	if (args.Cursor != nil && *args.Cursor != "") || args.Take != nil {
+		db := r.c.GetDB()
+		query := db.Model(&models.TeamCollection{}).Where("parent_id = ?", r.team_collection.ID)
+		if args.Cursor != nil && *args.Cursor != "" {
+			query = query.Where("id > ?", args.Cursor)
+		}
+		query = orderAndLimit(query, "id", args.Take)
+		err := query.Find(&teamCollections).Error
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		children, _, err := r.c.GetLoaders().ChildCollections.Load(r.team_collection.ID)
+		if err != nil {
+			return nil, err
+		}
+		teamCollections = children
 	}
 
 	teamCollectionResolvers := []*TeamCollectionResolver{}
@@ -95,6 +108,105 @@ func (r *TeamCollectionResolver) Title() (string, error) {
 	return r.team_collection.Title, nil
 }
 
+func (r *TeamCollectionResolver) Version() (int32, error) {
+	return int32(r.team_collection.Version), nil
+}
+
+// newTeamCollectionConflictError loads the current state of the collection
+// into a ConflictError.
+func newTeamCollectionConflictError(db *gorm.DB, collectionID uint) error {
+	current := &models.TeamCollection{}
+	err := db.Model(&models.TeamCollection{}).Where("id = ?", collectionID).First(current).Error
+	if err != nil {
+		return err
+	}
+
+	return &ConflictError{
+		resource:       "collection",
+		currentVersion: current.Version,
+		current: map[string]interface{}{
+			"id":         strconv.Itoa(int(current.ID)),
+			"title":      current.Title,
+			"properties": current.Properties,
+			"version":    current.Version,
+		},
+	}
+}
+
+func (r *TeamCollectionResolver) ChildrenConnection(args *ConnectionArgs) (*ConnectionResolver[*TeamCollectionResolver], error) {
+	db := r.c.GetDB()
+	query := db.Model(&models.TeamCollection{}).Where("parent_id = ?", r.team_collection.ID)
+	return paginate(query, "id", *args, func(m *models.TeamCollection) uint { return m.ID }, func(m *models.TeamCollection) (*TeamCollectionResolver, error) {
+		return NewTeamCollectionResolver(r.c, m)
+	})
+}
+
+func (r *TeamCollectionResolver) Requests() ([]*TeamRequestResolver, error) {
+	teamRequests, _, err := r.c.GetLoaders().RequestsInCollection.Load(r.team_collection.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	teamRequestResolvers := []*TeamRequestResolver{}
+	for i := range teamRequests {
+		newResolver, err := NewTeamRequestResolver(r.c, teamRequests[i])
+		if err != nil {
+			return nil, err
+		}
+		teamRequestResolvers = append(teamRequestResolvers, newResolver)
+	}
+
+	return teamRequestResolvers, nil
+}
+
+func (r *TeamCollectionResolver) Properties() (string, error) {
+	properties, err := hoppscotch.ParseCollectionProperties(r.team_collection.Properties)
+	if err != nil {
+		return "", err
+	}
+	return properties.JSON()
+}
+
+func (r *TeamCollectionResolver) EffectiveProperties() (string, error) {
+	properties, err := getEffectiveCollectionProperties(r.c.GetDB(), r.team_collection)
+	if err != nil {
+		return "", err
+	}
+	return properties.JSON()
+}
+
+// getEffectiveCollectionProperties returns the properties of the collection
+// with the properties of its parents applied.
+func getEffectiveCollectionProperties(db *gorm.DB, collection *models.TeamCollection) (hoppscotch.CollectionProperties, error) {
+	chain := []*models.TeamCollection{collection}
+	seen := map[uint]bool{collection.ID: true}
+	for parentID := collection.ParentID; parentID != 0 && !seen[parentID]; {
+		parent := &models.TeamCollection{}
+		err := db.Model(&models.TeamCollection{}).Where("id = ? AND team_id = ?", parentID, collection.TeamID).First(parent).Error
+		if err != nil && err == gorm.ErrRecordNotFound {
+			break
+		}
+		if err != nil {
+			return hoppscotch.CollectionProperties{}, err
+		}
+
+		seen[parentID] = true
+		chain = append([]*models.TeamCollection{parent}, chain...)
+		parentID = parent.ParentID
+	}
+
+	effectiveProperties := hoppscotch.NewCollectionProperties()
+	for i := range chain {
+		properties, err := hoppscotch.ParseCollectionProperties(chain[i].Properties)
+		if err != nil {
+			return hoppscotch.CollectionProperties{}, err
+		}
+		effectiveProperties = effectiveProperties.Inherit(properties)
+	}
+
+	return effectiveProperties, nil
+}
+
 type CollectionArgs struct {
 	CollectionID graphql.ID
 }
@@ -123,6 +235,108 @@ func (b *BaseQuery) Collection(ctx context.Context, args *CollectionArgs) (*Team
 	return NewTeamCollectionResolver(c, collection)
 }
 
+type CollectionTreeArgs struct {
+	TeamID graphql.ID
+	RootID *graphql.ID
+}
+
+// CollectionTree loads the whole tree below the root (or the root collections
+// of the team) at once, children and requests are served from the loaders.
+func (b *BaseQuery) CollectionTree(ctx context.Context, args *CollectionTreeArgs) ([]*TeamCollectionResolver, error) {
+	c := b.GetReqC(ctx)
+	userRole, err := getUserRoleInTeam(ctx, c, args.TeamID)
+	if err != nil {
+		return nil, err
+	}
+	if userRole == nil {
+		return nil, errors.New("user not in team")
+	}
+
+	db := c.GetDB()
+	team := &models.Team{}
+	err = db.Model(&models.Team{}).Where("id = ?", args.TeamID).First(team).Error
+	if err != nil {
+		return nil, err
+	}
+
+	teamCollections := []*models.TeamCollection{}
+	err = db.Model(&models.TeamCollection{}).Where("team_id = ?", team.ID).Order("id").Find(&teamCollections).Error
+	if err != nil {
+		return nil, err
+	}
+
+	byID := map[uint]*models.TeamCollection{}
+	for i := range teamCollections {
+		byID[teamCollections[i].ID] = teamCollections[i]
+	}
+
+	allIDs := []uint{0}
+	for i := range teamCollections {
+		allIDs = append(allIDs, teamCollections[i].ID)
+	}
+	childrenByParent := loaders.GroupCollectionsByParent(allIDs, teamCollections)
+
+	roots := childrenByParent[0]
+	if args.RootID != nil {
+		rootID, _ := strconv.Atoi(string(*args.RootID))
+		root, ok := byID[uint(rootID)]
+		if !ok {
+			return nil, errors.New("you do not have access to this collection")
+		}
+		roots = []*models.TeamCollection{root}
+	}
+
+	// Walk the tree to find the collections below the roots.
+	collectionIDs := []uint{}
+	queue := append([]*models.TeamCollection{}, roots...)
+	for len(queue) > 0 {
+		collection := queue[0]
+		queue = queue[1:]
+		collectionIDs = append(collectionIDs, collection.ID)
+		queue = append(queue, childrenByParent[collection.ID]...)
+	}
+
+	teamRequests := []*models.TeamRequest{}
+	for start := 0; start < len(collectionIDs); start += collectionTreeBatchSize {
+		end := start + collectionTreeBatchSize
+		if end > len(collectionIDs) {
+			end = len(collectionIDs)
+		}
+
+		batch := []*models.TeamRequest{}
+		err = db.Model(&models.TeamRequest{}).Where("team_id = ? AND team_collection_id IN ?", team.ID, collectionIDs[start:end]).Order("id").Find(&batch).Error
+		if err != nil {
+			return nil, err
+		}
+		teamRequests = append(teamRequests, batch...)
+	}
+
+	requestsByCollection := loaders.GroupRequestsByCollection(collectionIDs, teamRequests)
+
+	l := c.GetLoaders()
+	l.Team.Prime(team.ID, team)
+	for _, collectionID := range collectionIDs {
+		l.TeamCollection.Prime(collectionID, byID[collectionID])
+		l.ChildCollections.Prime(collectionID, childrenByParent[collectionID])
+		l.RequestsInCollection.Prime(collectionID, requestsByCollection[collectionID])
+	}
+
+	teamCollectionResolvers := []*TeamCollectionResolver{}
+	for i := range roots {
+		newResolver, err := NewTeamCollectionResolver(c, roots[i])
+		if err != nil {
+			return nil, err
+		}
+		teamCollectionResolvers = append(teamCollectionResolvers, newResolver)
+	}
+
+	return teamCollectionResolvers, nil
+}
+
+// collectionTreeBatchSize keeps the IN lists below the parameter limits of
+// the databases.
+const collectionTreeBatchSize = 500
+
 type CollectionsOfTeamArgs struct {
 	Cursor *graphql.ID
 	TeamID graphql.ID
@@ -142,9 +356,9 @@ func (b *BaseQuery) CollectionsOfTeam(ctx context.Context, args *CollectionsOfTe
 	teamCollections := []*models.TeamCollection{}
 	query := db.Model(&models.TeamCollection{}).Where("team_id = ?", args.TeamID)
 	if args.Cursor != nil && *args.Cursor != "" {
-		query.Where("id > ?", args.Cursor)
+		query = query.Where("id > ?", args.Cursor)
 	}
-	err = query.Find(&teamCollections).Error
+	err = query.Order("id").Find(&teamCollections).Error
 	if err != nil {
 		return nil, err
 	}
@@ -167,11 +381,40 @@ type ExportCollectionsToJSONArgs struct {
 
 type ExportJSONCollectionRequest map[string]interface{}
 
+// exportJSONCollectionVersion is the version of the collection format, since
+// version 2 collections have headers and auth.
+const exportJSONCollectionVersion = 2
+
 type ExportJSONCollection struct {
-	Version  int                           `json:"v"`
-	Name     string                        `json:"name"`
-	Folders  []ExportJSONCollection        `json:"folders"`
-	Requests []ExportJSONCollectionRequest `json:"requests"`
+	Version   int                              `json:"v"`
+	Name      string                           `json:"name"`
+	Folders   []ExportJSONCollection           `json:"folders"`
+	Requests  []ExportJSONCollectionRequest    `json:"requests"`
+	Headers   []hoppscotch.KeyValue            `json:"headers,omitempty"`
+	Auth      *hoppscotch.Auth                 `json:"auth,omitempty"`
+	Variables []hoppscotch.EnvironmentVariable `json:"variables,omitempty"`
+}
+
+// properties returns the collection properties of the exported collection,
+// older exports don't have any.
+func (e ExportJSONCollection) properties() hoppscotch.CollectionProperties {
+	properties := hoppscotch.NewCollectionProperties()
+	if e.Headers != nil {
+		properties.Headers = e.Headers
+	}
+	if e.Auth != nil && e.Auth.AuthType != "" {
+		properties.Auth = *e.Auth
+	}
+	if e.Variables != nil {
+		properties.Variables = e.Variables
+	}
+	return properties
+}
+
+func (e *ExportJSONCollection) setProperties(properties hoppscotch.CollectionProperties) {
+	e.Headers = properties.Headers
+	e.Auth = &properties.Auth
+	e.Variables = properties.Variables
 }
 
 func GetTeamExportJSON(c *graphql_context.Context, teamID graphql.ID, parentID uint) ([]ExportJSONCollection, error) {
@@ -184,32 +427,93 @@ func GetTeamExportJSON(c *graphql_context.Context, teamID graphql.ID, parentID u
 
 	output := []ExportJSONCollection{}
 	for i := range collections {
-		collection := ExportJSONCollection{
-			Version:  1,
-			Name:     collections[i].Title,
-			Folders:  []ExportJSONCollection{},
-			Requests: []ExportJSONCollectionRequest{},
+		collection, err := GetCollectionExportJSON(c, collections[i])
+		if err != nil {
+			return nil, err
+		}
+
+		output = append(output, *collection)
+	}
+	return output, nil
+}
+
+// GetCollectionExportJSON exports a single collection including all of its
+// requests and subfolders.
+func GetCollectionExportJSON(c *graphql_context.Context, teamCollection *models.TeamCollection) (*ExportJSONCollection, error) {
+	db := c.GetDB()
+	collection := &ExportJSONCollection{
+		Version:  exportJSONCollectionVersion,
+		Name:     teamCollection.Title,
+		Folders:  []ExportJSONCollection{},
+		Requests: []ExportJSONCollectionRequest{},
+	}
+
+	properties, err := hoppscotch.ParseCollectionProperties(teamCollection.Properties)
+	if err != nil {
+		return nil, err
+	}
+	collection.setProperties(properties)
+
+	requests := []*models.TeamRequest{}
+	err = db.Model(&models.TeamRequest{}).Where("team_id = ? AND team_collection_id = ?", teamCollection.TeamID, teamCollection.ID).Find(&requests).Error
+	if err != nil {
+		return nil, err
+	}
+
+	for ri := range requests {
+		requestDecode := ExportJSONCollectionRequest{}
+		err := json.Unmarshal([]byte(currentRequestJSON(requests[ri])), &requestDecode)
+		if err != nil {
+			return nil, fmt.Errorf("request %d in collection %s is not valid JSON: %w", requests[ri].ID, teamCollection.Title, err)
 		}
 
-		requests := []*models.TeamRequest{}
-		err := db.Model(&models.TeamRequest{}).Where("team_id = ? AND team_collection_id = ?", teamID, collections[i].ID).Find(&requests).Error
+		examples, err := exportRequestExamples(db, requests[ri].ID)
 		if err != nil {
 			return nil, err
 		}
+		if len(examples) > 0 {
+			requestDecode[hoppscotch.ExamplesField] = examples
+		}
+
+		collection.Requests = append(collection.Requests, requestDecode)
+	}
+
+	subfolders, err := GetTeamExportJSON(c, graphql.ID(strconv.Itoa(int(teamCollection.TeamID))), teamCollection.ID)
+	if err != nil {
+		return nil, err
+	}
 
-		for ri := range requests {
-			requestDecode := ExportJSONCollectionRequest{}
-			json.Unmarshal([]byte(requests[ri].Request), &requestDecode)
+	collection.Folders = subfolders
 
-			collection.Requests = append(collection.Requests, requestDecode)
+	return collection, nil
+}
+
+// exportJSONFromCollections converts collections created by an importer to
+// the JSON export format, so they can be imported with importJSON.
+func exportJSONFromCollections(collections []hoppscotch.Collection) ([]ExportJSONCollection, error) {
+	output := []ExportJSONCollection{}
+	for i := range collections {
+		collection := ExportJSONCollection{
+			Version:  exportJSONCollectionVersion,
+			Name:     collections[i].Name,
+			Folders:  []ExportJSONCollection{},
+			Requests: []ExportJSONCollectionRequest{},
+		}
+		collection.setProperties(hoppscotch.NewCollectionProperties())
+
+		for ri := range collections[i].Requests {
+			request, err := collections[i].Requests[ri].Map()
+			if err != nil {
+				return nil, err
+			}
+			collection.Requests = append(collection.Requests, request)
 		}
 
-		subfolders, err := GetTeamExportJSON(c, teamID, collections[i].ID)
+		folders, err := exportJSONFromCollections(collections[i].Folders)
 		if err != nil {
 			return nil, err
 		}
-
-		collection.Folders = subfolders
+		collection.Folders = folders
 
 		output = append(output, collection)
 	}
@@ -244,6 +548,7 @@ func (b *BaseQuery) ExportCollectionsToJSON(ctx context.Context, args *ExportCol
 type RequestsInCollectionArgs struct {
 	CollectionID graphql.ID
 	Cursor       *graphql.ID
+	Take         *int32
 }
 
 func (b *BaseQuery) RequestsInCollection(ctx context.Context, args *RequestsInCollectionArgs) ([]*TeamRequestResolver, error) {
@@ -268,10 +573,11 @@ func (b *BaseQuery) RequestsInCollection(ctx context.Context, args *RequestsInCo
 	}
 
 	teamRequests := []*models.TeamRequest{}
-	query := db.Model(&models.TeamRequest{}).Where("team_collection_id", args.CollectionID)
+	query := db.Model(&models.TeamRequest{}).Where("team_collection_id = ?", collection.ID)
 	if args.Cursor != nil && *args.Cursor != "" {
-		query.Where("id > ?", args.Cursor)
+		query = query.Where("id > ?", args.Cursor)
 	}
+	query = orderAndLimit(query, "id", args.Take)
 	err = query.Find(&teamRequests).Error
 	if err != nil {
 		return nil, err
@@ -289,6 +595,38 @@ func (b *BaseQuery) RequestsInCollection(ctx context.Context, args *RequestsInCo
 	return teamRequestResolvers, nil
 }
 
+type RequestsInCollectionConnectionArgs struct {
+	ConnectionArgs
+	CollectionID graphql.ID
+}
+
+func (b *BaseQuery) RequestsInCollectionConnection(ctx context.Context, args *RequestsInCollectionConnectionArgs) (*ConnectionResolver[*TeamRequestResolver], error) {
+	c := b.GetReqC(ctx)
+	db := c.GetDB()
+	collection := &models.TeamCollection{}
+	err := db.Model(&models.TeamCollection{}).Where("id = ?", args.CollectionID).First(collection).Error
+	if err != nil && err == gorm.ErrRecordNotFound {
+		return nil, errors.New("you do not have access to this collection")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	userRole, err := getUserRoleInTeam(ctx, c, collection.TeamID)
+	if err != nil {
+		return nil, err
+	}
+
+	if userRole == nil {
+		return nil, errors.New("you do not have access to this collection")
+	}
+
+	query := db.Model(&models.TeamRequest{}).Where("team_collection_id = ?", collection.ID)
+	return paginate(query, "id", args.ConnectionArgs, func(m *models.TeamRequest) uint { return m.ID }, func(m *models.TeamRequest) (*TeamRequestResolver, error) {
+		return NewTeamRequestResolver(c, m)
+	})
+}
+
 type CreateChildCollectionArgs struct {
 	ChildTitle   string
 	CollectionID graphql.ID
@@ -372,13 +710,23 @@ func (b *BaseQuery) CreateRequestInCollection(ctx context.Context, args *CreateR
 	}
 
 	if *userRole == models.Owner || *userRole == models.Editor {
+		currentUser, err := c.GetUser(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		normalized, err := normalizeTeamRequest(args.Data.Request, args.Data.Title)
+		if err != nil {
+			return nil, err
+		}
+
 		newRequest := &models.TeamRequest{
 			TeamCollectionID: collection.ID,
 			TeamID:           collection.TeamID,
-			Title:            args.Data.Title,
-			Request:          args.Data.Request,
+			Title:            normalized.Name,
+			Request:          normalized.JSON,
 		}
-		err := db.Save(newRequest).Error
+		err = createRequestWithRevision(db, newRequest, currentUser.ID)
 		if err != nil {
 			return nil, err
 		}
@@ -464,12 +812,39 @@ func (b *BaseQuery) DeleteCollection(ctx context.Context, args *DeleteCollection
 	}
 
 	if *userRole == models.Owner || *userRole == models.Editor {
-		err := db.Delete(collection).Error
+		currentUser, err := c.GetUser(ctx)
+		if err != nil {
+			return false, err
+		}
+
+		descendantIDs, err := getCollectionTreeIDs(db, collection.TeamID, collection.ID)
+		if err != nil {
+			return false, err
+		}
+		collectionIDs := append([]uint{collection.ID}, descendantIDs...)
+
+		err = checkCollectionLocks(c, collectionIDs, currentUser.ID)
+		if err != nil {
+			return false, err
+		}
+
+		// The collection is deleted with everything in it.
+		events := &eventQueue{}
+		deletedRequestIDs := []uint{}
+		err = db.Transaction(func(tx *gorm.DB) error {
+			var err error
+			deletedRequestIDs, err = deleteTeamCollectionsInTransaction(tx, events, collection.TeamID, collectionIDs)
+			return err
+		})
 		if err != nil {
 			return false, err
 		}
 
-		go bus.Publish("team:"+strconv.Itoa(int(collection.TeamID))+":collections:removed", graphql.ID(strconv.Itoa(int(collection.ID))))
+		events.Flush()
+
+		for _, requestID := range deletedRequestIDs {
+			requestLocks.Remove(requestID)
+		}
 
 		return true, nil
 	}
@@ -494,138 +869,263 @@ type ImportCollectionsFromJSONArgs struct {
 	TeamID             graphql.ID
 }
 
-func importJSON(c *graphql_context.Context, teamID uint, parentID uint, folders []ExportJSONCollection) error {
-	db := c.GetDB()
+// importJSON stores the collections in a single transaction, the events are
+// published after it has been committed.
+func importJSON(c *graphql_context.Context, teamID uint, parentID uint, userID uint, folders []ExportJSONCollection) error {
+	events := &eventQueue{}
+	err := c.GetDB().Transaction(func(tx *gorm.DB) error {
+		_, err := importJSONInTransaction(c, tx, events, teamID, parentID, userID, folders)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	events.Flush()
+
+	return nil
+}
+
+// importJSONInTransaction stores the collections below the parent and returns
+// the created collections, without their subfolders. The requests get a first
+// revision by the given user.
+func importJSONInTransaction(c *graphql_context.Context, db *gorm.DB, events *eventQueue, teamID uint, parentID uint, userID uint, folders []ExportJSONCollection) ([]*models.TeamCollection, error) {
+	newCollections := []*models.TeamCollection{}
 	for i := range folders {
+		properties, err := folders[i].properties().JSON()
+		if err != nil {
+			return nil, err
+		}
+
 		newCollection := &models.TeamCollection{
-			TeamID:   teamID,
-			Title:    folders[i].Name,
-			ParentID: parentID,
+			TeamID:     teamID,
+			Title:      folders[i].Name,
+			ParentID:   parentID,
+			Properties: properties,
 		}
 
-		err := db.Save(newCollection).Error
+		err = db.Save(newCollection).Error
 		if err != nil {
-			return err
+			return nil, err
 		}
+		newCollections = append(newCollections, newCollection)
 
 		resolver, err := NewTeamCollectionResolver(c, newCollection)
 		if err != nil {
-			return err
+			return nil, err
 		}
 
-		go bus.Publish("team:"+strconv.Itoa(int(teamID))+":collections:added", resolver)
+		events.Publish("team:"+strconv.Itoa(int(teamID))+":collections:added", resolver)
 
 		if folders[i].Requests != nil && len(folders[i].Requests) > 0 {
 			for ri := range folders[i].Requests {
-				newTeamRequest := &models.TeamRequest{
-					TeamID:           teamID,
-					TeamCollectionID: newCollection.ID,
+				// Examples are stored next to the request, not in it.
+				requestDocument := ExportJSONCollectionRequest{}
+				for key, value := range folders[i].Requests[ri] {
+					requestDocument[key] = value
 				}
+				delete(requestDocument, hoppscotch.ExamplesField)
 
-				if nameVal, ok := folders[i].Requests[ri]["name"]; ok {
-					name, ok := nameVal.(string)
-					if ok {
-						newTeamRequest.Title = name
+				examples, err := hoppscotch.ParseExamples(folders[i].Requests[ri][hoppscotch.ExamplesField])
+				if err != nil {
+					var validationErr *hoppscotch.ValidationError
+					if errors.As(err, &validationErr) {
+						return nil, &RequestValidationError{validationErr.Prefix(folders[i].Name + ".requests[" + strconv.Itoa(ri) + "]")}
 					}
+					return nil, err
 				}
 
-				requestData, err := json.Marshal(folders[i].Requests[ri])
+				requestData, err := json.Marshal(requestDocument)
 				if err != nil {
-					return err
+					return nil, err
 				}
 
-				newTeamRequest.Request = string(requestData)
+				normalized, err := normalizeTeamRequest(string(requestData), "")
+				if err != nil {
+					var validationErr *RequestValidationError
+					if errors.As(err, &validationErr) {
+						return nil, &RequestValidationError{validationErr.Prefix(folders[i].Name + ".requests[" + strconv.Itoa(ri) + "]")}
+					}
+					return nil, err
+				}
 
-				err = db.Save(newTeamRequest).Error
+				newTeamRequest := &models.TeamRequest{
+					TeamID:           teamID,
+					TeamCollectionID: newCollection.ID,
+					Title:            normalized.Name,
+					Request:          normalized.JSON,
+				}
+
+				err = createRequestWithRevision(db, newTeamRequest, userID)
+				if err != nil {
+					return nil, err
+				}
+
+				err = saveRequestExamples(db, newTeamRequest.ID, examples)
 				if err != nil {
-					return err
+					return nil, err
 				}
 
 				requestResolver, err := NewTeamRequestResolver(c, newTeamRequest)
 				if err != nil {
-					return err
+					return nil, err
 				}
 
-				go bus.Publish("team:"+strconv.Itoa(int(teamID))+":requests:added", requestResolver)
+				events.Publish("team:"+strconv.Itoa(int(teamID))+":requests:added", requestResolver)
 			}
 		}
 
 		if folders[i].Folders != nil && len(folders[i].Folders) > 0 {
-			err = importJSON(c, teamID, newCollection.ID, folders[i].Folders)
+			_, err = importJSONInTransaction(c, db, events, teamID, newCollection.ID, userID, folders[i].Folders)
 			if err != nil {
-				return err
+				return nil, err
 			}
 		}
 	}
 
-	return nil
+	return newCollections, nil
 }
 
-func (b *BaseQuery) ImportCollectionsFromJSON(ctx context.Context, args *ImportCollectionsFromJSONArgs) (bool, error) {
-	c := b.GetReqC(ctx)
+// getImportTarget checks whether the current user is allowed to import
+// collections into the team and optional parent collection, and returns the
+// IDs to import into.
+func getImportTarget(ctx context.Context, c *graphql_context.Context, teamID graphql.ID, parentCollectionID *graphql.ID) (uint, uint, error) {
 	db := c.GetDB()
 
-	parentCollectionID := uint(0)
-	if args.ParentCollectionID != nil {
+	parsedParentCollectionID := uint(0)
+	if parentCollectionID != nil {
 		collection := &models.TeamCollection{}
-		err := db.Model(&models.TeamCollection{}).Where("id = ?", args.ParentCollectionID).First(collection).Error
+		err := db.Model(&models.TeamCollection{}).Where("id = ? AND team_id = ?", parentCollectionID, teamID).First(collection).Error
 		if err != nil && err == gorm.ErrRecordNotFound {
-			return false, errors.New("you do not have access to this collection")
+			return 0, 0, errors.New("you do not have access to this collection")
 		}
 		if err != nil {
-			return false, err
+			return 0, 0, err
 		}
 
 		userRole, err := getUserRoleInTeam(ctx, c, collection.TeamID)
 		if err != nil {
-			return false, err
+			return 0, 0, err
 		}
 
 		if userRole == nil {
-			return false, errors.New("you do not have access to this collection")
+			return 0, 0, errors.New("you do not have access to this collection")
 		}
 
 		if *userRole == models.Owner || *userRole == models.Editor {
-			parentCollectionID = collection.ID
+			parsedParentCollectionID = collection.ID
 		} else {
-			return false, errors.New("you do not have write access to this collection")
+			return 0, 0, errors.New("you do not have write access to this collection")
 		}
 	}
 
-	userRole, err := getUserRoleInTeam(ctx, c, args.TeamID)
+	userRole, err := getUserRoleInTeam(ctx, c, teamID)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	if userRole == nil {
+		return 0, 0, errors.New("you do not have access to this collection")
+	}
+
+	if *userRole == models.Owner || *userRole == models.Editor {
+		parsedTeamID, _ := strconv.Atoi(string(teamID))
+		return uint(parsedTeamID), parsedParentCollectionID, nil
+	}
+
+	return 0, 0, errors.New("you do not have write access to this team")
+}
+
+func (b *BaseQuery) ImportCollectionsFromJSON(ctx context.Context, args *ImportCollectionsFromJSONArgs) (bool, error) {
+	c := b.GetReqC(ctx)
+
+	teamID, parentCollectionID, err := getImportTarget(ctx, c, args.TeamID, args.ParentCollectionID)
+	if err != nil {
+		return false, err
+	}
+
+	importData := []ExportJSONCollection{}
+	err = json.Unmarshal([]byte(args.JSONString), &importData)
+	if err != nil {
+		return false, err
+	}
+
+	currentUser, err := c.GetUser(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	err = importJSON(c, teamID, parentCollectionID, currentUser.ID, importData)
 	if err != nil {
 		return false, err
 	}
 
+	return true, nil
+}
+
+type RenameCollectionArgs struct {
+	CollectionID    graphql.ID
+	NewTitle        string
+	ExpectedVersion *int32
+}
+
+func (b *BaseQuery) RenameCollection(ctx context.Context, args *RenameCollectionArgs) (*TeamCollectionResolver, error) {
+	c := b.GetReqC(ctx)
+	db := c.GetDB()
+	collection := &models.TeamCollection{}
+	err := db.Model(&models.TeamCollection{}).Where("id = ?", args.CollectionID).First(collection).Error
+	if err != nil && err == gorm.ErrRecordNotFound {
+		return nil, errors.New("you do not have access to this collection")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	userRole, err := getUserRoleInTeam(ctx, c, collection.TeamID)
+	if err != nil {
+		return nil, err
+	}
+
 	if userRole == nil {
-		return false, errors.New("you do not have access to this collection")
+		return nil, errors.New("you do not have access to this collection")
 	}
 
 	if *userRole == models.Owner || *userRole == models.Editor {
-		importData := []ExportJSONCollection{}
-		err := json.Unmarshal([]byte(args.JSONString), &importData)
+		updated, err := updateVersioned(db, &models.TeamCollection{}, collection.ID, args.ExpectedVersion, map[string]interface{}{
+			"title": args.NewTitle,
+		})
 		if err != nil {
-			return false, err
+			return nil, err
+		}
+		if !updated {
+			return nil, newTeamCollectionConflictError(db, collection.ID)
 		}
 
-		teamID, _ := strconv.Atoi(string(args.TeamID))
-		err = importJSON(c, uint(teamID), parentCollectionID, importData)
+		err = db.Model(&models.TeamCollection{}).Where("id = ?", collection.ID).First(collection).Error
 		if err != nil {
-			return false, err
+			return nil, err
 		}
 
-		return true, nil
+		resolver, err := NewTeamCollectionResolver(c, collection)
+		if err != nil {
+			return nil, err
+		}
+
+		go bus.Publish("team:"+strconv.Itoa(int(collection.TeamID))+":collections:updated", resolver)
+
+		return NewTeamCollectionResolver(c, collection)
 	}
 
-	return false, errors.New("you do not have write access to this team")
+	return nil, errors.New("you are not allowed to rename a collection in this team")
 }
 
-type RenameCollectionArgs struct {
-	CollectionID graphql.ID
-	NewTitle     string
+type UpdateCollectionPropertiesArgs struct {
+	CollectionID    graphql.ID
+	Properties      string
+	ExpectedVersion *int32
 }
 
-func (b *BaseQuery) RenameCollection(ctx context.Context, args *RenameCollectionArgs) (*TeamCollectionResolver, error) {
+func (b *BaseQuery) UpdateCollectionProperties(ctx context.Context, args *UpdateCollectionPropertiesArgs) (*TeamCollectionResolver, error) {
 	c := b.GetReqC(ctx)
 	db := c.GetDB()
 	collection := &models.TeamCollection{}
@@ -647,8 +1147,27 @@ func (b *BaseQuery) RenameCollection(ctx context.Context, args *RenameCollection
 	}
 
 	if *userRole == models.Owner || *userRole == models.Editor {
-		collection.Title = args.NewTitle
-		err := db.Save(collection).Error
+		properties, err := hoppscotch.ParseCollectionProperties(args.Properties)
+		if err != nil {
+			return nil, errors.New("properties is not valid JSON: " + err.Error())
+		}
+
+		propertiesJSON, err := properties.JSON()
+		if err != nil {
+			return nil, err
+		}
+
+		updated, err := updateVersioned(db, &models.TeamCollection{}, collection.ID, args.ExpectedVersion, map[string]interface{}{
+			"properties": propertiesJSON,
+		})
+		if err != nil {
+			return nil, err
+		}
+		if !updated {
+			return nil, newTeamCollectionConflictError(db, collection.ID)
+		}
+
+		err = db.Model(&models.TeamCollection{}).Where("id = ?", collection.ID).First(collection).Error
 		if err != nil {
 			return nil, err
 		}
@@ -660,10 +1179,10 @@ func (b *BaseQuery) RenameCollection(ctx context.Context, args *RenameCollection
 
 		go bus.Publish("team:"+strconv.Itoa(int(collection.TeamID))+":collections:updated", resolver)
 
-		return NewTeamCollectionResolver(c, collection)
+		return resolver, nil
 	}
 
-	return nil, errors.New("you are not allowed to rename a collection in this team")
+	return nil, errors.New("you are not allowed to update a collection in this team")
 }
 
 type ReplaceCollectionsWithJSONArgs struct {
@@ -673,8 +1192,117 @@ type ReplaceCollectionsWithJSONArgs struct {
 }
 
 func (b *BaseQuery) ReplaceCollectionsWithJSON(ctx context.Context, args *ReplaceCollectionsWithJSONArgs) (bool, error) {
-	// This doesn't seem to be used (anymore).
-	return false, nil
+	c := b.GetReqC(ctx)
+
+	teamID, parentCollectionID, err := getImportTarget(ctx, c, args.TeamID, args.ParentCollectionID)
+	if err != nil {
+		return false, err
+	}
+
+	importData := []ExportJSONCollection{}
+	err = json.Unmarshal([]byte(args.JSONString), &importData)
+	if err != nil {
+		return false, err
+	}
+
+	currentUser, err := c.GetUser(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	replacedIDs, err := getCollectionTreeIDs(c.GetDB(), teamID, parentCollectionID)
+	if err != nil {
+		return false, err
+	}
+
+	err = checkCollectionLocks(c, replacedIDs, currentUser.ID)
+	if err != nil {
+		return false, err
+	}
+
+	events := &eventQueue{}
+	deletedRequestIDs := []uint{}
+	err = c.GetDB().Transaction(func(tx *gorm.DB) error {
+		collectionIDs, err := getCollectionTreeIDs(tx, teamID, parentCollectionID)
+		if err != nil {
+			return err
+		}
+
+		if len(collectionIDs) > 0 {
+			deletedRequestIDs, err = deleteTeamCollectionsInTransaction(tx, events, teamID, collectionIDs)
+			if err != nil {
+				return err
+			}
+		}
+
+		_, err = importJSONInTransaction(c, tx, events, teamID, parentCollectionID, currentUser.ID, importData)
+		return err
+	})
+	if err != nil {
+		return false, err
+	}
+
+	events.Flush()
+
+	for _, requestID := range deletedRequestIDs {
+		requestLocks.Remove(requestID)
+	}
+
+	return true, nil
+}
+
+// deleteTeamCollectionsInTransaction deletes the collections with their
+// requests and comments, the collection IDs include all subcollections. It
+// returns the IDs of the deleted requests.
+func deleteTeamCollectionsInTransaction(tx *gorm.DB, events *eventQueue, teamID uint, collectionIDs []uint) ([]uint, error) {
+	requestIDs := []uint{}
+	err := tx.Model(&models.TeamRequest{}).Where("team_id = ? AND team_collection_id IN ?", teamID, collectionIDs).Pluck("id", &requestIDs).Error
+	if err != nil {
+		return nil, err
+	}
+
+	if len(requestIDs) > 0 {
+		err = deleteTeamRequestsInTransaction(tx, events, requestIDs)
+		if err != nil {
+			return nil, err
+		}
+		for _, requestID := range requestIDs {
+			events.Publish("team:"+strconv.Itoa(int(teamID))+":requests:deleted", graphql.ID(strconv.Itoa(int(requestID))))
+		}
+	}
+
+	err = deleteCommentsInTransaction(tx, events, "team_collection_id", collectionIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	err = tx.Where("team_id = ? AND id IN ?", teamID, collectionIDs).Delete(&models.TeamCollection{}).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, collectionID := range collectionIDs {
+		events.Publish("team:"+strconv.Itoa(int(teamID))+":collections:removed", graphql.ID(strconv.Itoa(int(collectionID))))
+	}
+
+	return requestIDs, nil
+}
+
+// getCollectionTreeIDs returns the IDs of all collections below the parent,
+// parents come before their children.
+func getCollectionTreeIDs(db *gorm.DB, teamID uint, parentID uint) ([]uint, error) {
+	collectionIDs := []uint{}
+	parentIDs := []uint{parentID}
+	for len(parentIDs) > 0 {
+		childIDs := []uint{}
+		err := db.Model(&models.TeamCollection{}).Where("team_id = ? AND parent_id IN ?", teamID, parentIDs).Pluck("id", &childIDs).Error
+		if err != nil {
+			return nil, err
+		}
+
+		collectionIDs = append(collectionIDs, childIDs...)
+		parentIDs = childIDs
+	}
+	return collectionIDs, nil
 }
 
 type SubscriptionArgs struct {
@@ -695,7 +1323,7 @@ func (b *BaseQuery) TeamCollectionAdded(ctx context.Context, args *SubscriptionA
 	teamID, _ := strconv.Atoi(string(args.TeamID))
 	notificationChannel := make(chan *TeamCollectionResolver)
 	eventHandler := func(resolver *TeamCollectionResolver) {
-		notificationChannel <- resolver
+		notificationChannel <- &TeamCollectionResolver{c: eventContext(c), team_collection: resolver.team_collection}
 	}
 
 	err = subscribeUntilDone(ctx, "team:"+strconv.Itoa(teamID)+":collections:added", eventHandler)
@@ -745,7 +1373,7 @@ func (b *BaseQuery) TeamCollectionUpdated(ctx context.Context, args *Subscriptio
 	teamID, _ := strconv.Atoi(string(args.TeamID))
 	notificationChannel := make(chan *TeamCollectionResolver)
 	eventHandler := func(resolver *TeamCollectionResolver) {
-		notificationChannel <- resolver
+		notificationChannel <- &TeamCollectionResolver{c: eventContext(c), team_collection: resolver.team_collection}
 	}
 
 	err = subscribeUntilDone(ctx, "team:"+strconv.Itoa(teamID)+":collections:updated", eventHandler)
@@ -770,7 +1398,7 @@ func (b *BaseQuery) TeamInvitationAdded(ctx context.Context, args *SubscriptionA
 	teamID, _ := strconv.Atoi(string(args.TeamID))
 	notificationChannel := make(chan *TeamInvitationResolver)
 	eventHandler := func(resolver *TeamInvitationResolver) {
-		notificationChannel <- resolver
+		notificationChannel <- &TeamInvitationResolver{c: eventContext(c), team_invitation: resolver.team_invitation}
 	}
 
 	err = subscribeUntilDone(ctx, "team:"+strconv.Itoa(teamID)+":invitations:added", eventHandler)
@@ -820,7 +1448,7 @@ func (b *BaseQuery) TeamMemberAdded(ctx context.Context, args *SubscriptionArgs)
 	teamID, _ := strconv.Atoi(string(args.TeamID))
 	notificationChannel := make(chan *TeamMemberResolver)
 	eventHandler := func(resolver *TeamMemberResolver) {
-		notificationChannel <- resolver
+		notificationChannel <- &TeamMemberResolver{c: eventContext(c), team_member: resolver.team_member}
 	}
 
 	err = subscribeUntilDone(ctx, "team:"+strconv.Itoa(teamID)+":members:added", eventHandler)
@@ -870,7 +1498,7 @@ func (b *BaseQuery) TeamMemberUpdated(ctx context.Context, args *SubscriptionArg
 	teamID, _ := strconv.Atoi(string(args.TeamID))
 	notificationChannel := make(chan *TeamMemberResolver)
 	eventHandler := func(resolver *TeamMemberResolver) {
-		notificationChannel <- resolver
+		notificationChannel <- &TeamMemberResolver{c: eventContext(c), team_member: resolver.team_member}
 	}
 
 	err = subscribeUntilDone(ctx, "team:"+strconv.Itoa(teamID)+":members:updated", eventHandler)
@@ -895,7 +1523,7 @@ func (b *BaseQuery) TeamRequestAdded(ctx context.Context, args *SubscriptionArgs
 	teamID, _ := strconv.Atoi(string(args.TeamID))
 	notificationChannel := make(chan *TeamRequestResolver)
 	eventHandler := func(resolver *TeamRequestResolver) {
-		notificationChannel <- resolver
+		notificationChannel <- &TeamRequestResolver{c: eventContext(c), team_request: resolver.team_request}
 	}
 
 	err = subscribeUntilDone(ctx, "team:"+strconv.Itoa(teamID)+":requests:added", eventHandler)
@@ -945,7 +1573,8 @@ func (b *BaseQuery) TeamRequestUpdated(ctx context.Context, args *SubscriptionAr
 	teamID, _ := strconv.Atoi(string(args.TeamID))
 	notificationChannel := make(chan *TeamRequestResolver)
 	eventHandler := func(resolver *TeamRequestResolver) {
-		notificationChannel <- resolver
+		// Lock changes are published without a request context.
+		notificationChannel <- &TeamRequestResolver{c: eventContext(c), team_request: resolver.team_request}
 	}
 
 	err = subscribeUntilDone(ctx, "team:"+strconv.Itoa(teamID)+":requests:updated", eventHandler)
@@ -953,6 +1582,14 @@ func (b *BaseQuery) TeamRequestUpdated(ctx context.Context, args *SubscriptionAr
 		return nil, err
 	}
 
+	// Lock changes are published as updates, the locks of the user are kept
+	// while they listen for them.
+	currentUser, err := c.GetUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	requestLocks.trackSession(ctx, currentUser.ID, uint(teamID))
+
 	return notificationChannel, nil
 }
 
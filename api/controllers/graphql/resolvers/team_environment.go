@@ -7,6 +7,7 @@ import (
 	"strconv"
 
 	graphql_context "github.com/jerbob92/hoppscotch-backend/api/controllers/graphql/context"
+	"github.com/jerbob92/hoppscotch-backend/helpers/hoppscotch"
 	"github.com/jerbob92/hoppscotch-backend/models"
 
 	"github.com/graph-gophers/graphql-go"
@@ -42,6 +43,31 @@ func (r *TeamEnvironmentResolver) Name() (string, error) {
 	return r.team_environment.Name, nil
 }
 
+func (r *TeamEnvironmentResolver) Version() (int32, error) {
+	return int32(r.team_environment.Version), nil
+}
+
+// newTeamEnvironmentConflictError loads the current state of the environment
+// into a ConflictError.
+func newTeamEnvironmentConflictError(db *gorm.DB, environmentID uint) error {
+	current := &models.TeamEnvironment{}
+	err := db.Model(&models.TeamEnvironment{}).Where("id = ?", environmentID).First(current).Error
+	if err != nil {
+		return err
+	}
+
+	return &ConflictError{
+		resource:       "environment",
+		currentVersion: current.Version,
+		current: map[string]interface{}{
+			"id":        strconv.Itoa(int(current.ID)),
+			"name":      current.Name,
+			"variables": current.Variables,
+			"version":   current.Version,
+		},
+	}
+}
+
 type CreateTeamEnvironmentRequestArgs struct {
 	Name      string
 	TeamID    graphql.ID
@@ -134,9 +160,10 @@ func (b *BaseQuery) DeleteTeamEnvironment(ctx context.Context, args *DeleteTeamE
 }
 
 type UpdateTeamEnvironmentRequestArgs struct {
-	ID        graphql.ID
-	Name      string
-	Variables string
+	ID              graphql.ID
+	Name            string
+	Variables       string
+	ExpectedVersion *int32
 }
 
 func (b *BaseQuery) UpdateTeamEnvironment(ctx context.Context, args *UpdateTeamEnvironmentRequestArgs) (*TeamEnvironmentResolver, error) {
@@ -162,10 +189,18 @@ func (b *BaseQuery) UpdateTeamEnvironment(ctx context.Context, args *UpdateTeamE
 	}
 
 	if *userRole == models.Owner || *userRole == models.Editor {
-		teamEnvironment.Name = args.Name
-		teamEnvironment.Variables = args.Variables
+		updated, err := updateVersioned(db, &models.TeamEnvironment{}, teamEnvironment.ID, args.ExpectedVersion, map[string]interface{}{
+			"name":      args.Name,
+			"variables": args.Variables,
+		})
+		if err != nil {
+			return nil, err
+		}
+		if !updated {
+			return nil, newTeamEnvironmentConflictError(db, teamEnvironment.ID)
+		}
 
-		err := db.Save(teamEnvironment).Error
+		err = db.Model(&models.TeamEnvironment{}).Where("id = ?", teamEnvironment.ID).First(teamEnvironment).Error
 		if err != nil {
 			return nil, err
 		}
@@ -184,7 +219,8 @@ func (b *BaseQuery) UpdateTeamEnvironment(ctx context.Context, args *UpdateTeamE
 }
 
 type DeleteAllVariablesFromTeamEnvironmentRequestArgs struct {
-	ID graphql.ID
+	ID              graphql.ID
+	ExpectedVersion *int32
 }
 
 func (b *BaseQuery) DeleteAllVariablesFromTeamEnvironment(ctx context.Context, args *DeleteAllVariablesFromTeamEnvironmentRequestArgs) (*TeamEnvironmentResolver, error) {
@@ -210,9 +246,17 @@ func (b *BaseQuery) DeleteAllVariablesFromTeamEnvironment(ctx context.Context, a
 	}
 
 	if *userRole == models.Owner || *userRole == models.Editor {
-		teamEnvironment.Variables = ""
+		updated, err := updateVersioned(db, &models.TeamEnvironment{}, teamEnvironment.ID, args.ExpectedVersion, map[string]interface{}{
+			"variables": "",
+		})
+		if err != nil {
+			return nil, err
+		}
+		if !updated {
+			return nil, newTeamEnvironmentConflictError(db, teamEnvironment.ID)
+		}
 
-		err := db.Save(teamEnvironment).Error
+		err = db.Model(&models.TeamEnvironment{}).Where("id = ?", teamEnvironment.ID).First(teamEnvironment).Error
 		if err != nil {
 			return nil, err
 		}
@@ -280,3 +324,28 @@ func (b *BaseQuery) CreateDuplicateEnvironment(ctx context.Context, args *Create
 
 	return nil, errors.New("you are not allowed to duplicate an environment in this team")
 }
+
+// getEnvironmentVariables returns the variables of the given environment of
+// the team, without an environment there are no variables.
+func getEnvironmentVariables(c *graphql_context.Context, teamID uint, environmentID *graphql.ID) (map[string]string, error) {
+	if environmentID == nil {
+		return nil, nil
+	}
+
+	db := c.GetDB()
+	environment := &models.TeamEnvironment{}
+	err := db.Model(&models.TeamEnvironment{}).Where("id = ? AND team_id = ?", environmentID, teamID).First(environment).Error
+	if err != nil && err == gorm.ErrRecordNotFound {
+		return nil, errors.New("you do not have access to this environment")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	variables, err := hoppscotch.ParseEnvironmentVariables(environment.Variables)
+	if err != nil {
+		return nil, err
+	}
+
+	return hoppscotch.VariableMap(variables), nil
+}
@@ -1,11 +1,8 @@
 package resolvers
 
 import (
-	"bytes"
 	"context"
 	"errors"
-	"fmt"
-	"html/template"
 	"strconv"
 
 	graphql_context "github.com/jerbob92/hoppscotch-backend/api/controllers/graphql/context"
@@ -13,7 +10,6 @@ import (
 
 	"github.com/graph-gophers/graphql-go"
 	"github.com/spf13/viper"
-	"gopkg.in/gomail.v2"
 	"gorm.io/gorm"
 )
 
@@ -36,10 +32,11 @@ func (r *TeamInvitationResolver) ID() (graphql.ID, error) {
 }
 
 func (r *TeamInvitationResolver) Creator() (*UserResolver, error) {
-	db := r.c.GetDB()
-	existingUser := &models.User{}
-	err := db.Where("id = ?", r.team_invitation.UserID).First(existingUser).Error
-	if err != nil && err == gorm.ErrRecordNotFound {
+	existingUser, found, err := r.c.GetLoaders().User.Load(r.team_invitation.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
 		return nil, errors.New("user not found")
 	}
 
@@ -47,10 +44,11 @@ func (r *TeamInvitationResolver) Creator() (*UserResolver, error) {
 }
 
 func (r *TeamInvitationResolver) CreatorUid() (graphql.ID, error) {
-	db := r.c.GetDB()
-	existingUser := &models.User{}
-	err := db.Where("id = ?", r.team_invitation.UserID).First(existingUser).Error
-	if err != nil && err == gorm.ErrRecordNotFound {
+	existingUser, found, err := r.c.GetLoaders().User.Load(r.team_invitation.UserID)
+	if err != nil {
+		return graphql.ID(""), err
+	}
+	if !found {
 		return graphql.ID(""), errors.New("user not found")
 	}
 
@@ -66,10 +64,11 @@ func (r *TeamInvitationResolver) InviteeRole() (models.TeamMemberRole, error) {
 }
 
 func (r *TeamInvitationResolver) Team() (*TeamResolver, error) {
-	db := r.c.GetDB()
-	existingTeam := &models.Team{}
-	err := db.Where("id = ?", r.team_invitation.TeamID).First(existingTeam).Error
-	if err != nil && err == gorm.ErrRecordNotFound {
+	existingTeam, found, err := r.c.GetLoaders().Team.Load(r.team_invitation.TeamID)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
 		return nil, errors.New("team not found")
 	}
 
@@ -231,12 +230,6 @@ func (b *BaseQuery) CreateTeamInvitation(ctx context.Context, args *CreateTeamIn
 		return nil, err
 	}
 
-	from := fmt.Sprintf("%s <%s>", viper.GetString("smtp.from.name"), viper.GetString("smtp.from.email"))
-
-	m := gomail.NewMessage()
-	m.SetHeader("From", from)
-	m.SetHeader("To", invite.InviteeEmail)
-
 	joinLink := viper.GetString("frontend_domain") + "/join-team?id=" + invite.Code
 
 	templateVariables := struct {
@@ -249,39 +242,11 @@ func (b *BaseQuery) CreateTeamInvitation(ctx context.Context, args *CreateTeamIn
 		JoinLink:         joinLink,
 	}
 
-	subjectTemplate := template.New("Subject")
-	subjectTemplate, err = subjectTemplate.Parse(viper.GetString("mailTemplates.teamInvite.subject"))
-	if err != nil {
-		return nil, err
-	}
-
-	var subject bytes.Buffer
-	err = subjectTemplate.Execute(&subject, templateVariables)
-	if err != nil {
-		return nil, err
-	}
-
-	m.SetHeader("Subject", subject.String())
-
-	bodyTemplate := template.New("Body")
-	bodyTemplate, err = bodyTemplate.Parse(viper.GetString("mailTemplates.teamInvite.body"))
+	err = sendTemplateMail([]string{invite.InviteeEmail}, "teamInvite", templateVariables)
 	if err != nil {
 		return nil, err
 	}
 
-	var body bytes.Buffer
-	err = bodyTemplate.Execute(&body, templateVariables)
-	if err != nil {
-		return nil, err
-	}
-
-	m.SetBody("text/html", body.String())
-
-	d := gomail.NewDialer(viper.GetString("smtp.host"), viper.GetInt("smtp.port"), viper.GetString("smtp.username"), viper.GetString("smtp.password"))
-	if err := d.DialAndSend(m); err != nil {
-		return nil, err
-	}
-
 	resolver, err := NewTeamInvitationResolver(c, invite)
 	if err != nil {
 		return nil, err
@@ -9,7 +9,6 @@ import (
 	"github.com/jerbob92/hoppscotch-backend/models"
 
 	"github.com/graph-gophers/graphql-go"
-	"gorm.io/gorm"
 )
 
 type TeamMemberResolver struct {
@@ -35,10 +34,11 @@ func (r *TeamMemberResolver) Role() (models.TeamMemberRole, error) {
 }
 
 func (r *TeamMemberResolver) User() (*UserResolver, error) {
-	db := r.c.GetDB()
-	existingUser := &models.User{}
-	err := db.Where("id = ?", r.team_member.UserID).First(existingUser).Error
-	if err != nil && err == gorm.ErrRecordNotFound {
+	existingUser, found, err := r.c.GetLoaders().User.Load(r.team_member.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
 		return nil, errors.New("user not found")
 	}
 
@@ -6,6 +6,7 @@ import (
 	"strconv"
 
 	graphql_context "github.com/jerbob92/hoppscotch-backend/api/controllers/graphql/context"
+	"github.com/jerbob92/hoppscotch-backend/helpers/hoppscotch"
 	"github.com/jerbob92/hoppscotch-backend/models"
 
 	"github.com/graph-gophers/graphql-go"
@@ -31,15 +32,13 @@ func (r *TeamRequestResolver) ID() (graphql.ID, error) {
 }
 
 func (r *TeamRequestResolver) Collection() (*TeamCollectionResolver, error) {
-	db := r.c.GetDB()
-	collection := &models.TeamCollection{}
-	err := db.Model(&models.TeamCollection{}).Where("id = ?", r.team_request.TeamCollectionID).First(collection).Error
-	if err != nil && err == gorm.ErrRecordNotFound {
-		return nil, errors.New("you do not have access to this collection")
-	}
+	collection, found, err := r.c.GetLoaders().TeamCollection.Load(r.team_request.TeamCollectionID)
 	if err != nil {
 		return nil, err
 	}
+	if !found {
+		return nil, errors.New("you do not have access to this collection")
+	}
 	return NewTeamCollectionResolver(r.c, collection)
 }
 
@@ -48,19 +47,27 @@ func (r *TeamRequestResolver) CollectionID() (graphql.ID, error) {
 }
 
 func (r *TeamRequestResolver) Request() (string, error) {
-	return r.team_request.Request, nil
+	return currentRequestJSON(r.team_request), nil
 }
 
-func (r *TeamRequestResolver) Team() (*TeamResolver, error) {
-	db := r.c.GetDB()
-	team := &models.Team{}
-	err := db.Model(&models.Team{}).Where("id = ?", r.team_request.TeamID).First(team).Error
-	if err != nil && err == gorm.ErrRecordNotFound {
-		return nil, errors.New("you do not have access to this team")
+// currentRequestJSON returns the request data in the current version. Rows
+// that were not backfilled yet are migrated on read, without storing them.
+func currentRequestJSON(teamRequest *models.TeamRequest) string {
+	data, _, err := hoppscotch.MigrateRequestJSON(teamRequest.Request)
+	if err != nil {
+		return teamRequest.Request
 	}
+	return data
+}
+
+func (r *TeamRequestResolver) Team() (*TeamResolver, error) {
+	team, found, err := r.c.GetLoaders().Team.Load(r.team_request.TeamID)
 	if err != nil {
 		return nil, err
 	}
+	if !found {
+		return nil, errors.New("you do not have access to this team")
+	}
 	return NewTeamResolver(r.c, team)
 }
 
@@ -72,6 +79,156 @@ func (r *TeamRequestResolver) Title() (string, error) {
 	return r.team_request.Title, nil
 }
 
+func (r *TeamRequestResolver) Version() (int32, error) {
+	return int32(r.team_request.Version), nil
+}
+
+// newRequestConflictError loads the current state of the request into a
+// ConflictError.
+func newRequestConflictError(db *gorm.DB, requestID uint) error {
+	current := &models.TeamRequest{}
+	err := db.Model(&models.TeamRequest{}).Where("id = ?", requestID).First(current).Error
+	if err != nil {
+		return err
+	}
+
+	return &ConflictError{
+		resource:       "request",
+		currentVersion: current.Version,
+		current: map[string]interface{}{
+			"id":           strconv.Itoa(int(current.ID)),
+			"collectionID": strconv.Itoa(int(current.TeamCollectionID)),
+			"title":        current.Title,
+			"request":      currentRequestJSON(current),
+			"version":      current.Version,
+		},
+	}
+}
+
+// prepareTeamRequest resolves the request as it would be sent: the
+// properties of its collection are applied, and the variables of the
+// collection and the given environment are replaced.
+func prepareTeamRequest(c *graphql_context.Context, teamRequest *models.TeamRequest, environmentID *graphql.ID) (*hoppscotch.PreparedRequest, error) {
+	db := c.GetDB()
+
+	request, err := hoppscotch.ParseRESTRequest(currentRequestJSON(teamRequest))
+	if err != nil {
+		return nil, err
+	}
+
+	collection := &models.TeamCollection{}
+	err = db.Model(&models.TeamCollection{}).Where("id = ?", teamRequest.TeamCollectionID).First(collection).Error
+	if err != nil {
+		return nil, err
+	}
+
+	properties, err := getEffectiveCollectionProperties(db, collection)
+	if err != nil {
+		return nil, err
+	}
+	properties.ApplyTo(request)
+
+	// Environment variables override collection variables.
+	variables := hoppscotch.VariableMap(properties.Variables)
+	environmentVariables, err := getEnvironmentVariables(c, teamRequest.TeamID, environmentID)
+	if err != nil {
+		return nil, err
+	}
+	for key, value := range environmentVariables {
+		variables[key] = value
+	}
+
+	return hoppscotch.Prepare(request, variables), nil
+}
+
+// RequestValidationError is returned for request documents that are not
+// valid, the problem of every field is sent in the error extensions.
+type RequestValidationError struct {
+	*hoppscotch.ValidationError
+}
+
+func (e *RequestValidationError) Extensions() map[string]interface{} {
+	fields := []map[string]interface{}{}
+	for _, fieldError := range e.Errors {
+		fields = append(fields, map[string]interface{}{
+			"field":   fieldError.Field,
+			"message": fieldError.Message,
+		})
+	}
+
+	return map[string]interface{}{
+		"code":   "INVALID_REQUEST",
+		"fields": fields,
+	}
+}
+
+// normalizeTeamRequest validates the request document before it's stored,
+// and returns it normalized.
+func normalizeTeamRequest(data string, defaultName string) (*hoppscotch.NormalizedRequest, error) {
+	normalized, err := hoppscotch.NormalizeRequest(data, defaultName)
+	if err != nil {
+		var validationErr *hoppscotch.ValidationError
+		if errors.As(err, &validationErr) {
+			return nil, &RequestValidationError{validationErr}
+		}
+		return nil, err
+	}
+	return normalized, nil
+}
+
+// deleteTeamRequestsInTransaction deletes the requests with their examples,
+// revisions and comments.
+func deleteTeamRequestsInTransaction(tx *gorm.DB, events *eventQueue, requestIDs []uint) error {
+	err := deleteCommentsInTransaction(tx, events, "team_request_id", requestIDs)
+	if err != nil {
+		return err
+	}
+
+	err = tx.Where("team_request_id IN ?", requestIDs).Delete(&models.TeamRequestExample{}).Error
+	if err != nil {
+		return err
+	}
+
+	err = tx.Where("team_request_id IN ?", requestIDs).Delete(&models.TeamRequestRevision{}).Error
+	if err != nil {
+		return err
+	}
+
+	return tx.Where("id IN ?", requestIDs).Delete(&models.TeamRequest{}).Error
+}
+
+// deleteCommentsInTransaction deletes the comments on the requests or the
+// collections, the column is team_request_id or team_collection_id.
+func deleteCommentsInTransaction(tx *gorm.DB, events *eventQueue, column string, ids []uint) error {
+	comments := []*models.Comment{}
+	err := tx.Model(&models.Comment{}).Where(column+" IN ?", ids).Find(&comments).Error
+	if err != nil {
+		return err
+	}
+
+	if len(comments) == 0 {
+		return nil
+	}
+
+	err = tx.Where(column+" IN ?", ids).Delete(&models.Comment{}).Error
+	if err != nil {
+		return err
+	}
+	for i := range comments {
+		events.Publish("team:"+strconv.Itoa(int(comments[i].TeamID))+":comments:deleted", graphql.ID(strconv.Itoa(int(comments[i].ID))))
+	}
+	return nil
+}
+
+// moveCommentsInTransaction moves the comments on the requests or the
+// collections to the team, the column is team_request_id or team_collection_id.
+func moveCommentsInTransaction(tx *gorm.DB, column string, ids []uint, teamID uint) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	return tx.Model(&models.Comment{}).Where(column+" IN ?", ids).Update("team_id", teamID).Error
+}
+
 type DeleteRequestArgs struct {
 	RequestID graphql.ID
 }
@@ -98,12 +255,27 @@ func (b *BaseQuery) DeleteRequest(ctx context.Context, args *DeleteRequestArgs)
 	}
 
 	if *userRole == models.Owner || *userRole == models.Editor {
-		err := db.Delete(request).Error
+		currentUser, err := c.GetUser(ctx)
 		if err != nil {
 			return false, err
 		}
 
-		go bus.Publish("team:"+strconv.Itoa(int(request.TeamID))+":requests:deleted", graphql.ID(strconv.Itoa(int(request.ID))))
+		err = checkRequestLock(c, request.ID, currentUser.ID, nil)
+		if err != nil {
+			return false, err
+		}
+
+		events := &eventQueue{}
+		err = db.Transaction(func(tx *gorm.DB) error {
+			return deleteTeamRequestsInTransaction(tx, events, []uint{request.ID})
+		})
+		if err != nil {
+			return false, err
+		}
+
+		events.Publish("team:"+strconv.Itoa(int(request.TeamID))+":requests:deleted", graphql.ID(strconv.Itoa(int(request.ID))))
+		events.Flush()
+		requestLocks.Remove(request.ID)
 
 		return true, nil
 	}
@@ -156,6 +328,16 @@ func (b *BaseQuery) MoveRequest(ctx context.Context, args *MoveRequestArgs) (*Te
 	}
 
 	if (*userRole == models.Owner || *userRole == models.Editor) && (*targetUserRole == models.Owner || *targetUserRole == models.Editor) {
+		currentUser, err := c.GetUser(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		err = checkRequestLock(c, request.ID, currentUser.ID, nil)
+		if err != nil {
+			return nil, err
+		}
+
 		teamChanged := false
 		oldTeamID := request.TeamID
 		newTeamID := collection.TeamID
@@ -163,9 +345,25 @@ func (b *BaseQuery) MoveRequest(ctx context.Context, args *MoveRequestArgs) (*Te
 			teamChanged = true
 		}
 
-		request.TeamCollectionID = collection.ID
-		request.TeamID = collection.TeamID
-		err := db.Save(request).Error
+		err = db.Transaction(func(tx *gorm.DB) error {
+			_, err := updateVersioned(tx, &models.TeamRequest{}, request.ID, nil, map[string]interface{}{
+				"team_id":            collection.TeamID,
+				"team_collection_id": collection.ID,
+			})
+			if err != nil {
+				return err
+			}
+			if teamChanged {
+				return moveCommentsInTransaction(tx, "team_request_id", []uint{request.ID}, collection.TeamID)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		request = &models.TeamRequest{}
+		err = db.Model(&models.TeamRequest{}).Where("id = ?", args.RequestID).First(request).Error
 		if err != nil {
 			return nil, err
 		}
@@ -194,8 +392,10 @@ type UpdateTeamRequestInput struct {
 }
 
 type UpdateRequestArgs struct {
-	Data      UpdateTeamRequestInput
-	RequestID graphql.ID
+	Data            UpdateTeamRequestInput
+	RequestID       graphql.ID
+	ExpectedVersion *int32
+	Force           *bool
 }
 
 func (b *BaseQuery) UpdateRequest(ctx context.Context, args *UpdateRequestArgs) (*TeamRequestResolver, error) {
@@ -220,13 +420,40 @@ func (b *BaseQuery) UpdateRequest(ctx context.Context, args *UpdateRequestArgs)
 	}
 
 	if *userRole == models.Owner || *userRole == models.Editor {
-		if args.Data.Title != nil {
-			request.Title = *args.Data.Title
+		currentUser, err := c.GetUser(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		err = checkRequestLock(c, request.ID, currentUser.ID, args.Force)
+		if err != nil {
+			return nil, err
 		}
-		if args.Data.Request != nil {
-			request.Request = *args.Data.Request
+
+		previous := *request
+		if args.Data.Title != nil || args.Data.Request != nil {
+			requestData := request.Request
+			if args.Data.Request != nil {
+				requestData = *args.Data.Request
+			}
+
+			normalized, err := normalizeTeamRequest(requestData, request.Title)
+			if err != nil {
+				return nil, err
+			}
+
+			// The title is the name in the request, renaming changes both.
+			if args.Data.Title != nil {
+				err := normalized.Rename(*args.Data.Title)
+				if err != nil {
+					return nil, err
+				}
+			}
+
+			request.Title = normalized.Name
+			request.Request = normalized.JSON
 		}
-		err := db.Save(request).Error
+		err = saveRequestWithRevision(db, previous, request, currentUser.ID, args.ExpectedVersion)
 		if err != nil {
 			return nil, err
 		}
@@ -0,0 +1,432 @@
+package resolvers
+
+import (
+	"context"
+	"errors"
+	"strconv"
+
+	graphql_context "github.com/jerbob92/hoppscotch-backend/api/controllers/graphql/context"
+	"github.com/jerbob92/hoppscotch-backend/helpers/hoppscotch"
+	"github.com/jerbob92/hoppscotch-backend/models"
+
+	"github.com/graph-gophers/graphql-go"
+	"github.com/sanae10001/graphql-go-extension-scalars"
+	"gorm.io/gorm"
+)
+
+type RequestExampleResolver struct {
+	c       *graphql_context.Context
+	example *models.TeamRequestExample
+}
+
+func NewRequestExampleResolver(c *graphql_context.Context, example *models.TeamRequestExample) (*RequestExampleResolver, error) {
+	if example == nil {
+		return nil, nil
+	}
+
+	return &RequestExampleResolver{c: c, example: example}, nil
+}
+
+func (r *RequestExampleResolver) ID() (graphql.ID, error) {
+	id := graphql.ID(strconv.Itoa(int(r.example.ID)))
+	return id, nil
+}
+
+func (r *RequestExampleResolver) RequestID() (graphql.ID, error) {
+	return graphql.ID(strconv.Itoa(int(r.example.TeamRequestID))), nil
+}
+
+func (r *RequestExampleResolver) Name() (string, error) {
+	return r.example.Name, nil
+}
+
+func (r *RequestExampleResolver) Status() (int32, error) {
+	return int32(r.example.Status), nil
+}
+
+func (r *RequestExampleResolver) Headers() ([]*RequestExampleHeaderResolver, error) {
+	headers, err := hoppscotch.ParseExampleHeaders(r.example.Headers)
+	if err != nil {
+		return nil, err
+	}
+
+	headerResolvers := []*RequestExampleHeaderResolver{}
+	for i := range headers {
+		headerResolvers = append(headerResolvers, &RequestExampleHeaderResolver{header: headers[i]})
+	}
+
+	return headerResolvers, nil
+}
+
+func (r *RequestExampleResolver) Body() (string, error) {
+	return r.example.Body, nil
+}
+
+func (r *RequestExampleResolver) CreatedOn() (scalars.DateTime, error) {
+	return *scalars.NewDateTime(r.example.CreatedAt), nil
+}
+
+func (r *RequestExampleResolver) UpdatedOn() (scalars.DateTime, error) {
+	return *scalars.NewDateTime(r.example.UpdatedAt), nil
+}
+
+type RequestExampleHeaderResolver struct {
+	header hoppscotch.ExampleHeader
+}
+
+func (r *RequestExampleHeaderResolver) Key() string {
+	return r.header.Key
+}
+
+func (r *RequestExampleHeaderResolver) Value() string {
+	return r.header.Value
+}
+
+func (r *TeamRequestResolver) Examples() ([]*RequestExampleResolver, error) {
+	examples, err := getRequestExamples(r.c.GetDB(), r.team_request.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	exampleResolvers := []*RequestExampleResolver{}
+	for i := range examples {
+		exampleResolver, err := NewRequestExampleResolver(r.c, examples[i])
+		if err != nil {
+			return nil, err
+		}
+		exampleResolvers = append(exampleResolvers, exampleResolver)
+	}
+
+	return exampleResolvers, nil
+}
+
+func getRequestExamples(db *gorm.DB, requestID uint) ([]*models.TeamRequestExample, error) {
+	examples := []*models.TeamRequestExample{}
+	err := db.Model(&models.TeamRequestExample{}).Where("team_request_id = ?", requestID).Order("id").Find(&examples).Error
+	if err != nil {
+		return nil, err
+	}
+	return examples, nil
+}
+
+// exportRequestExamples returns the examples of the request in the format of
+// the JSON export.
+func exportRequestExamples(db *gorm.DB, requestID uint) ([]hoppscotch.ExampleResponse, error) {
+	examples, err := getRequestExamples(db, requestID)
+	if err != nil {
+		return nil, err
+	}
+
+	output := []hoppscotch.ExampleResponse{}
+	for i := range examples {
+		headers, err := hoppscotch.ParseExampleHeaders(examples[i].Headers)
+		if err != nil {
+			return nil, err
+		}
+
+		output = append(output, hoppscotch.ExampleResponse{
+			Name:    examples[i].Name,
+			Status:  examples[i].Status,
+			Headers: headers,
+			Body:    examples[i].Body,
+		})
+	}
+
+	return output, nil
+}
+
+// saveRequestExamples stores imported examples for the request.
+func saveRequestExamples(db *gorm.DB, requestID uint, examples []hoppscotch.ExampleResponse) error {
+	for i := range examples {
+		headers, err := hoppscotch.ExampleHeadersJSON(examples[i].Headers)
+		if err != nil {
+			return err
+		}
+
+		err = db.Save(&models.TeamRequestExample{
+			TeamRequestID: requestID,
+			Name:          examples[i].Name,
+			Status:        examples[i].Status,
+			Headers:       headers,
+			Body:          examples[i].Body,
+		}).Error
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// copyRequestExamples copies the examples of a request to another request.
+func copyRequestExamples(db *gorm.DB, fromRequestID uint, toRequestID uint) error {
+	examples, err := getRequestExamples(db, fromRequestID)
+	if err != nil {
+		return err
+	}
+
+	for i := range examples {
+		err := db.Save(&models.TeamRequestExample{
+			TeamRequestID: toRequestID,
+			Name:          examples[i].Name,
+			Status:        examples[i].Status,
+			Headers:       examples[i].Headers,
+			Body:          examples[i].Body,
+		}).Error
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// validateRequestExample returns a RequestValidationError when the example
+// is not valid.
+func validateRequestExample(example hoppscotch.ExampleResponse) error {
+	err := example.Validate()
+	if err != nil {
+		var validationErr *hoppscotch.ValidationError
+		if errors.As(err, &validationErr) {
+			return &RequestValidationError{validationErr}
+		}
+		return err
+	}
+	return nil
+}
+
+// getEditableRequest loads the request for an example mutation, only editors
+// can change the examples of a request, and not while someone else holds the
+// lock on it.
+func getEditableRequest(ctx context.Context, c *graphql_context.Context, requestID interface{}) (*models.TeamRequest, error) {
+	db := c.GetDB()
+	request := &models.TeamRequest{}
+	err := db.Model(&models.TeamRequest{}).Where("id = ?", requestID).First(request).Error
+	if err != nil && err == gorm.ErrRecordNotFound {
+		return nil, errors.New("you do not have access to this request")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	userRole, err := getUserRoleInTeam(ctx, c, request.TeamID)
+	if err != nil {
+		return nil, err
+	}
+
+	if userRole == nil {
+		return nil, errors.New("you do not have access to this request")
+	}
+
+	if *userRole != models.Owner && *userRole != models.Editor {
+		return nil, errors.New("you are not allowed to edit a request in this team")
+	}
+
+	currentUser, err := c.GetUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	err = checkRequestLock(c, request.ID, currentUser.ID, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return request, nil
+}
+
+// getEditableRequestExample loads the example and its request for an example
+// mutation.
+func getEditableRequestExample(ctx context.Context, c *graphql_context.Context, exampleID graphql.ID) (*models.TeamRequestExample, *models.TeamRequest, error) {
+	db := c.GetDB()
+	example := &models.TeamRequestExample{}
+	err := db.Model(&models.TeamRequestExample{}).Where("id = ?", exampleID).First(example).Error
+	if err != nil && err == gorm.ErrRecordNotFound {
+		return nil, nil, errors.New("you do not have access to this example")
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	request, err := getEditableRequest(ctx, c, example.TeamRequestID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return example, request, nil
+}
+
+// publishRequestExamplesChange publishes the request as updated, so the
+// subscribers get the new examples.
+func publishRequestExamplesChange(c *graphql_context.Context, request *models.TeamRequest) {
+	resolver, err := NewTeamRequestResolver(c, request)
+	if err != nil {
+		return
+	}
+
+	go bus.Publish("team:"+strconv.Itoa(int(request.TeamID))+":requests:updated", resolver)
+}
+
+type RequestExampleHeaderInput struct {
+	Key   string
+	Value string
+}
+
+func exampleHeadersFromInput(input *[]RequestExampleHeaderInput) []hoppscotch.ExampleHeader {
+	headers := []hoppscotch.ExampleHeader{}
+	if input == nil {
+		return headers
+	}
+	for _, header := range *input {
+		headers = append(headers, hoppscotch.ExampleHeader{Key: header.Key, Value: header.Value})
+	}
+	return headers
+}
+
+type CreateRequestExampleInput struct {
+	Name    string
+	Status  int32
+	Headers *[]RequestExampleHeaderInput
+	Body    *string
+}
+
+type CreateRequestExampleArgs struct {
+	RequestID graphql.ID
+	Data      CreateRequestExampleInput
+}
+
+func (b *BaseQuery) CreateRequestExample(ctx context.Context, args *CreateRequestExampleArgs) (*RequestExampleResolver, error) {
+	c := b.GetReqC(ctx)
+	request, err := getEditableRequest(ctx, c, args.RequestID)
+	if err != nil {
+		return nil, err
+	}
+
+	example := hoppscotch.ExampleResponse{
+		Name:    args.Data.Name,
+		Status:  int(args.Data.Status),
+		Headers: exampleHeadersFromInput(args.Data.Headers),
+	}
+	if args.Data.Body != nil {
+		example.Body = *args.Data.Body
+	}
+
+	err = validateRequestExample(example)
+	if err != nil {
+		return nil, err
+	}
+
+	headers, err := hoppscotch.ExampleHeadersJSON(example.Headers)
+	if err != nil {
+		return nil, err
+	}
+
+	newExample := &models.TeamRequestExample{
+		TeamRequestID: request.ID,
+		Name:          example.Name,
+		Status:        example.Status,
+		Headers:       headers,
+		Body:          example.Body,
+	}
+
+	db := c.GetDB()
+	err = db.Save(newExample).Error
+	if err != nil {
+		return nil, err
+	}
+
+	publishRequestExamplesChange(c, request)
+
+	return NewRequestExampleResolver(c, newExample)
+}
+
+type UpdateRequestExampleInput struct {
+	Name    *string
+	Status  *int32
+	Headers *[]RequestExampleHeaderInput
+	Body    *string
+}
+
+type UpdateRequestExampleArgs struct {
+	ExampleID graphql.ID
+	Data      UpdateRequestExampleInput
+}
+
+func (b *BaseQuery) UpdateRequestExample(ctx context.Context, args *UpdateRequestExampleArgs) (*RequestExampleResolver, error) {
+	c := b.GetReqC(ctx)
+	existingExample, request, err := getEditableRequestExample(ctx, c, args.ExampleID)
+	if err != nil {
+		return nil, err
+	}
+
+	headers, err := hoppscotch.ParseExampleHeaders(existingExample.Headers)
+	if err != nil {
+		return nil, err
+	}
+
+	example := hoppscotch.ExampleResponse{
+		Name:    existingExample.Name,
+		Status:  existingExample.Status,
+		Headers: headers,
+		Body:    existingExample.Body,
+	}
+	if args.Data.Name != nil {
+		example.Name = *args.Data.Name
+	}
+	if args.Data.Status != nil {
+		example.Status = int(*args.Data.Status)
+	}
+	if args.Data.Headers != nil {
+		example.Headers = exampleHeadersFromInput(args.Data.Headers)
+	}
+	if args.Data.Body != nil {
+		example.Body = *args.Data.Body
+	}
+
+	err = validateRequestExample(example)
+	if err != nil {
+		return nil, err
+	}
+
+	headersJSON, err := hoppscotch.ExampleHeadersJSON(example.Headers)
+	if err != nil {
+		return nil, err
+	}
+
+	existingExample.Name = example.Name
+	existingExample.Status = example.Status
+	existingExample.Headers = headersJSON
+	existingExample.Body = example.Body
+
+	db := c.GetDB()
+	err = db.Save(existingExample).Error
+	if err != nil {
+		return nil, err
+	}
+
+	publishRequestExamplesChange(c, request)
+
+	return NewRequestExampleResolver(c, existingExample)
+}
+
+type DeleteRequestExampleArgs struct {
+	ExampleID graphql.ID
+}
+
+func (b *BaseQuery) DeleteRequestExample(ctx context.Context, args *DeleteRequestExampleArgs) (bool, error) {
+	c := b.GetReqC(ctx)
+	example, request, err := getEditableRequestExample(ctx, c, args.ExampleID)
+	if err != nil {
+		return false, err
+	}
+
+	db := c.GetDB()
+	err = db.Delete(example).Error
+	if err != nil {
+		return false, err
+	}
+
+	publishRequestExamplesChange(c, request)
+
+	return true, nil
+}
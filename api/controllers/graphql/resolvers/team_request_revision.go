@@ -0,0 +1,346 @@
+package resolvers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strconv"
+
+	graphql_context "github.com/jerbob92/hoppscotch-backend/api/controllers/graphql/context"
+	"github.com/jerbob92/hoppscotch-backend/helpers/jsondiff"
+	"github.com/jerbob92/hoppscotch-backend/models"
+
+	"github.com/graph-gophers/graphql-go"
+	"github.com/sanae10001/graphql-go-extension-scalars"
+	"github.com/spf13/viper"
+	"gorm.io/gorm"
+)
+
+type TeamRequestRevisionResolver struct {
+	c                     *graphql_context.Context
+	team_request_revision *models.TeamRequestRevision
+}
+
+func NewTeamRequestRevisionResolver(c *graphql_context.Context, team_request_revision *models.TeamRequestRevision) (*TeamRequestRevisionResolver, error) {
+	if team_request_revision == nil {
+		return nil, nil
+	}
+
+	return &TeamRequestRevisionResolver{c: c, team_request_revision: team_request_revision}, nil
+}
+
+func (r *TeamRequestRevisionResolver) ID() (graphql.ID, error) {
+	id := graphql.ID(strconv.Itoa(int(r.team_request_revision.ID)))
+	return id, nil
+}
+
+func (r *TeamRequestRevisionResolver) RequestID() (graphql.ID, error) {
+	return graphql.ID(strconv.Itoa(int(r.team_request_revision.TeamRequestID))), nil
+}
+
+func (r *TeamRequestRevisionResolver) Request() (string, error) {
+	return r.team_request_revision.Request, nil
+}
+
+func (r *TeamRequestRevisionResolver) Title() (string, error) {
+	return r.team_request_revision.Title, nil
+}
+
+func (r *TeamRequestRevisionResolver) Author() (*UserResolver, error) {
+	if r.team_request_revision.UserID == nil {
+		return nil, nil
+	}
+
+	author, found, err := r.c.GetLoaders().User.Load(*r.team_request_revision.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, nil
+	}
+	return NewUserResolver(r.c, author)
+}
+
+func (r *TeamRequestRevisionResolver) CreatedOn() (scalars.DateTime, error) {
+	return *scalars.NewDateTime(r.team_request_revision.CreatedAt), nil
+}
+
+type TeamRequestRevisionsArgs struct {
+	Cursor *graphql.ID
+	Take   *int32
+}
+
+// Revisions returns the revisions of the request, newest first.
+func (r *TeamRequestResolver) Revisions(args *TeamRequestRevisionsArgs) ([]*TeamRequestRevisionResolver, error) {
+	db := r.c.GetDB()
+	revisions := []*models.TeamRequestRevision{}
+	query := db.Model(&models.TeamRequestRevision{}).Where("team_request_id = ?", r.team_request.ID)
+
+	if args.Cursor != nil && *args.Cursor != "" {
+		query = query.Where("id < ?", args.Cursor)
+	}
+
+	query = query.Order("id DESC")
+	if size := pageSize(args.Take, 0); size > 0 {
+		query = query.Limit(size)
+	}
+
+	err := query.Find(&revisions).Error
+	if err != nil {
+		return nil, err
+	}
+
+	revisionResolvers := []*TeamRequestRevisionResolver{}
+	for i := range revisions {
+		newResolver, err := NewTeamRequestRevisionResolver(r.c, revisions[i])
+		if err != nil {
+			return nil, err
+		}
+		revisionResolvers = append(revisionResolvers, newResolver)
+	}
+
+	return revisionResolvers, nil
+}
+
+// saveRequestWithRevision saves the title and request data of the request and
+// records its new state as a revision by the given user. Requests from before
+// revisions were kept get their previous state recorded first, so the first
+// change can be restored. A ConflictError is returned when the request is not
+// at the expected version.
+func saveRequestWithRevision(db *gorm.DB, previous models.TeamRequest, request *models.TeamRequest, userID uint, expectedVersion *int32) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		var revisionCount int64
+		err := tx.Model(&models.TeamRequestRevision{}).Where("team_request_id = ?", request.ID).Count(&revisionCount).Error
+		if err != nil {
+			return err
+		}
+
+		if revisionCount == 0 {
+			initialRevision := &models.TeamRequestRevision{
+				TeamRequestID: previous.ID,
+				Request:       previous.Request,
+				Title:         previous.Title,
+			}
+			initialRevision.CreatedAt = previous.UpdatedAt
+			err := tx.Create(initialRevision).Error
+			if err != nil {
+				return err
+			}
+		}
+
+		updated, err := updateVersioned(tx, &models.TeamRequest{}, request.ID, expectedVersion, map[string]interface{}{
+			"title":   request.Title,
+			"request": request.Request,
+		})
+		if err != nil {
+			return err
+		}
+		if !updated {
+			return newRequestConflictError(tx, request.ID)
+		}
+
+		err = tx.Model(&models.TeamRequest{}).Where("id = ?", request.ID).First(request).Error
+		if err != nil {
+			return err
+		}
+
+		// Saving without changes is not a revision.
+		if previous.Request == request.Request && previous.Title == request.Title {
+			return nil
+		}
+
+		err = tx.Create(&models.TeamRequestRevision{
+			TeamRequestID: request.ID,
+			Request:       request.Request,
+			Title:         request.Title,
+			UserID:        &userID,
+		}).Error
+		if err != nil {
+			return err
+		}
+
+		return pruneRequestRevisions(tx, request.ID)
+	})
+}
+
+// createRequestWithRevision creates the request and records its first state
+// as a revision by the given user.
+func createRequestWithRevision(db *gorm.DB, request *models.TeamRequest, userID uint) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Save(request).Error
+		if err != nil {
+			return err
+		}
+
+		return tx.Create(&models.TeamRequestRevision{
+			TeamRequestID: request.ID,
+			Request:       request.Request,
+			Title:         request.Title,
+			UserID:        &userID,
+		}).Error
+	})
+}
+
+// pruneRequestRevisions removes the oldest revisions of the request when it
+// has more than the configured maximum.
+func pruneRequestRevisions(db *gorm.DB, requestID uint) error {
+	maxRevisions := viper.GetInt("api.revisions.maxPerRequest")
+	if maxRevisions <= 0 {
+		return nil
+	}
+
+	keepIDs := []uint{}
+	err := db.Model(&models.TeamRequestRevision{}).Where("team_request_id = ?", requestID).Order("id DESC").Limit(maxRevisions).Pluck("id", &keepIDs).Error
+	if err != nil {
+		return err
+	}
+
+	if len(keepIDs) < maxRevisions {
+		return nil
+	}
+
+	return db.Unscoped().Where("team_request_id = ? AND id NOT IN ?", requestID, keepIDs).Delete(&models.TeamRequestRevision{}).Error
+}
+
+// getRequestRevision loads the revision with its request, when the current
+// user is allowed to see the request.
+func getRequestRevision(ctx context.Context, c *graphql_context.Context, revisionID graphql.ID) (*models.TeamRequestRevision, *models.TeamMemberRole, error) {
+	db := c.GetDB()
+	revision := &models.TeamRequestRevision{}
+	err := db.Model(&models.TeamRequestRevision{}).Where("id = ?", revisionID).Preload("TeamRequest").First(revision).Error
+	if err != nil && err == gorm.ErrRecordNotFound {
+		return nil, nil, errors.New("you do not have access to this revision")
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	userRole, err := getUserRoleInTeam(ctx, c, revision.TeamRequest.TeamID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if userRole == nil {
+		return nil, nil, errors.New("you do not have access to this revision")
+	}
+
+	return revision, userRole, nil
+}
+
+type RequestRevisionChangeResolver struct {
+	change jsondiff.Change
+}
+
+func (r *RequestRevisionChangeResolver) Operation() string {
+	return r.change.Operation
+}
+
+func (r *RequestRevisionChangeResolver) Path() string {
+	return r.change.Path
+}
+
+func (r *RequestRevisionChangeResolver) OldValue() *string {
+	return r.change.OldValue
+}
+
+func (r *RequestRevisionChangeResolver) NewValue() *string {
+	return r.change.NewValue
+}
+
+// revisionDocument returns the revision as one JSON document, with the
+// request decoded so it's compared field by field.
+func revisionDocument(revision *models.TeamRequestRevision) map[string]interface{} {
+	var request interface{}
+	if err := json.Unmarshal([]byte(revision.Request), &request); err != nil {
+		// Not valid JSON, compare it as a string.
+		request = revision.Request
+	}
+
+	return map[string]interface{}{
+		"title":   revision.Title,
+		"request": request,
+	}
+}
+
+type DiffRequestRevisionsArgs struct {
+	FromRevisionID graphql.ID
+	ToRevisionID   graphql.ID
+}
+
+func (b *BaseQuery) DiffRequestRevisions(ctx context.Context, args *DiffRequestRevisionsArgs) ([]*RequestRevisionChangeResolver, error) {
+	c := b.GetReqC(ctx)
+
+	fromRevision, _, err := getRequestRevision(ctx, c, args.FromRevisionID)
+	if err != nil {
+		return nil, err
+	}
+
+	toRevision, _, err := getRequestRevision(ctx, c, args.ToRevisionID)
+	if err != nil {
+		return nil, err
+	}
+
+	if fromRevision.TeamRequestID != toRevision.TeamRequestID {
+		return nil, errors.New("the revisions do not belong to the same request")
+	}
+
+	changeResolvers := []*RequestRevisionChangeResolver{}
+	for _, change := range jsondiff.DiffValues(revisionDocument(fromRevision), revisionDocument(toRevision)) {
+		changeResolvers = append(changeResolvers, &RequestRevisionChangeResolver{change: change})
+	}
+
+	return changeResolvers, nil
+}
+
+type RestoreRequestRevisionArgs struct {
+	RevisionID      graphql.ID
+	ExpectedVersion *int32
+	Force           *bool
+}
+
+func (b *BaseQuery) RestoreRequestRevision(ctx context.Context, args *RestoreRequestRevisionArgs) (*TeamRequestResolver, error) {
+	c := b.GetReqC(ctx)
+	db := c.GetDB()
+
+	revision, userRole, err := getRequestRevision(ctx, c, args.RevisionID)
+	if err != nil {
+		return nil, err
+	}
+
+	if *userRole == models.Owner || *userRole == models.Editor {
+		currentUser, err := c.GetUser(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		err = checkRequestLock(c, revision.TeamRequestID, currentUser.ID, args.Force)
+		if err != nil {
+			return nil, err
+		}
+
+		normalized, err := normalizeTeamRequest(revision.Request, revision.Title)
+		if err != nil {
+			return nil, err
+		}
+
+		request := &revision.TeamRequest
+		previous := *request
+		request.Title = normalized.Name
+		request.Request = normalized.JSON
+		err = saveRequestWithRevision(db, previous, request, currentUser.ID, args.ExpectedVersion)
+		if err != nil {
+			return nil, err
+		}
+
+		requestResolver, err := NewTeamRequestResolver(c, request)
+		if err != nil {
+			return nil, err
+		}
+
+		go bus.Publish("team:"+strconv.Itoa(int(request.TeamID))+":requests:updated", requestResolver)
+
+		return requestResolver, nil
+	}
+
+	return nil, errors.New("you are not allowed to update a request in this team")
+}
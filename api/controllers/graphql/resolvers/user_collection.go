@@ -0,0 +1,398 @@
+package resolvers
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+
+	graphql_context "github.com/jerbob92/hoppscotch-backend/api/controllers/graphql/context"
+	"github.com/jerbob92/hoppscotch-backend/helpers/hoppscotch"
+	"github.com/jerbob92/hoppscotch-backend/models"
+
+	"github.com/graph-gophers/graphql-go"
+	"gorm.io/gorm"
+)
+
+type UserCollectionResolver struct {
+	c               *graphql_context.Context
+	user_collection *models.UserCollection
+}
+
+func NewUserCollectionResolver(c *graphql_context.Context, user_collection *models.UserCollection) (*UserCollectionResolver, error) {
+	if user_collection == nil {
+		return nil, nil
+	}
+
+	return &UserCollectionResolver{c: c, user_collection: user_collection}, nil
+}
+
+func (r *UserCollectionResolver) ID() (graphql.ID, error) {
+	id := graphql.ID(strconv.Itoa(int(r.user_collection.ID)))
+	return id, nil
+}
+
+func (r *UserCollectionResolver) Title() (string, error) {
+	return r.user_collection.Title, nil
+}
+
+func (r *UserCollectionResolver) Type() (models.ReqType, error) {
+	return r.user_collection.Type, nil
+}
+
+func (r *UserCollectionResolver) Properties() (string, error) {
+	properties, err := hoppscotch.ParseCollectionProperties(r.user_collection.Properties)
+	if err != nil {
+		return "", err
+	}
+	return properties.JSON()
+}
+
+func (r *UserCollectionResolver) ParentID() (*graphql.ID, error) {
+	if r.user_collection.ParentID == 0 {
+		return nil, nil
+	}
+	id := graphql.ID(strconv.Itoa(int(r.user_collection.ParentID)))
+	return &id, nil
+}
+
+func (r *UserCollectionResolver) Parent() (*UserCollectionResolver, error) {
+	if r.user_collection.ParentID == 0 {
+		return nil, nil
+	}
+
+	parent := &models.UserCollection{}
+	db := r.c.GetDB()
+	err := db.Model(&models.UserCollection{}).Where("id = ? AND user_id = ?", r.user_collection.ParentID, r.user_collection.UserID).First(parent).Error
+	if err != nil && err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return NewUserCollectionResolver(r.c, parent)
+}
+
+type UserCollectionChildrenArgs struct {
+	Cursor *graphql.ID
+	Take   *int32
+}
+
+func (r *UserCollectionResolver) Children(args *UserCollectionChildrenArgs) ([]*UserCollectionResolver, error) {
+	db := r.c.GetDB()
+	query := db.Model(&models.UserCollection{}).Where("user_id = ? AND parent_id = ?", r.user_collection.UserID, r.user_collection.ID)
+	return listUserCollections(r.c, query, args.Cursor, args.Take)
+}
+
+func (r *UserCollectionResolver) Requests(args *UserCollectionChildrenArgs) ([]*UserRequestResolver, error) {
+	requests := []*models.UserRequest{}
+	db := r.c.GetDB()
+
+	query := db.Model(&models.UserRequest{}).Where("user_id = ? AND user_collection_id = ?", r.user_collection.UserID, r.user_collection.ID)
+	if args.Cursor != nil && *args.Cursor != "" {
+		query = query.Where("id > ?", args.Cursor)
+	}
+	query = orderAndLimit(query, "id", args.Take)
+
+	err := query.Find(&requests).Error
+	if err != nil {
+		return nil, err
+	}
+
+	requestResolvers := []*UserRequestResolver{}
+	for i := range requests {
+		newResolver, err := NewUserRequestResolver(r.c, requests[i])
+		if err != nil {
+			return nil, err
+		}
+		requestResolvers = append(requestResolvers, newResolver)
+	}
+
+	return requestResolvers, nil
+}
+
+func listUserCollections(c *graphql_context.Context, query *gorm.DB, cursor *graphql.ID, take *int32) ([]*UserCollectionResolver, error) {
+	if cursor != nil && *cursor != "" {
+		query = query.Where("id > ?", cursor)
+	}
+	query = orderAndLimit(query, "id", take)
+
+	collections := []*models.UserCollection{}
+	err := query.Find(&collections).Error
+	if err != nil {
+		return nil, err
+	}
+
+	collectionResolvers := []*UserCollectionResolver{}
+	for i := range collections {
+		newResolver, err := NewUserCollectionResolver(c, collections[i])
+		if err != nil {
+			return nil, err
+		}
+		collectionResolvers = append(collectionResolvers, newResolver)
+	}
+
+	return collectionResolvers, nil
+}
+
+// getUserCollection loads a collection of the current user.
+func getUserCollection(ctx context.Context, c *graphql_context.Context, collectionID interface{}) (*models.UserCollection, error) {
+	currentUser, err := c.GetUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	collection := &models.UserCollection{}
+	db := c.GetDB()
+	err = db.Model(&models.UserCollection{}).Where("id = ? AND user_id = ?", collectionID, currentUser.ID).First(collection).Error
+	if err != nil && err == gorm.ErrRecordNotFound {
+		return nil, errors.New("you do not have access to this collection")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return collection, nil
+}
+
+// getUserCollectionTreeIDs returns the IDs of all collections below the
+// parent, parents come before their children.
+func getUserCollectionTreeIDs(db *gorm.DB, userID uint, parentID uint) ([]uint, error) {
+	collectionIDs := []uint{}
+	parentIDs := []uint{parentID}
+	for len(parentIDs) > 0 {
+		childIDs := []uint{}
+		err := db.Model(&models.UserCollection{}).Where("user_id = ? AND parent_id IN ?", userID, parentIDs).Pluck("id", &childIDs).Error
+		if err != nil {
+			return nil, err
+		}
+
+		collectionIDs = append(collectionIDs, childIDs...)
+		parentIDs = childIDs
+	}
+	return collectionIDs, nil
+}
+
+type RootUserCollectionsArgs struct {
+	Type   models.ReqType
+	Cursor *graphql.ID
+	Take   *int32
+}
+
+func (b *BaseQuery) RootUserCollections(ctx context.Context, args *RootUserCollectionsArgs) ([]*UserCollectionResolver, error) {
+	c := b.GetReqC(ctx)
+	currentUser, err := c.GetUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	db := c.GetDB()
+	query := db.Model(&models.UserCollection{}).Where("user_id = ? AND parent_id = ? AND type = ?", currentUser.ID, 0, args.Type)
+	return listUserCollections(c, query, args.Cursor, args.Take)
+}
+
+type UserCollectionArgs struct {
+	UserCollectionID graphql.ID
+}
+
+func (b *BaseQuery) UserCollection(ctx context.Context, args *UserCollectionArgs) (*UserCollectionResolver, error) {
+	c := b.GetReqC(ctx)
+
+	collection, err := getUserCollection(ctx, c, args.UserCollectionID)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewUserCollectionResolver(c, collection)
+}
+
+type CreateUserCollectionArgs struct {
+	Title    string
+	Type     models.ReqType
+	ParentID *graphql.ID
+}
+
+func (b *BaseQuery) CreateUserCollection(ctx context.Context, args *CreateUserCollectionArgs) (*UserCollectionResolver, error) {
+	c := b.GetReqC(ctx)
+	currentUser, err := c.GetUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if strings.TrimSpace(args.Title) == "" {
+		return nil, errors.New("the title of a collection can't be empty")
+	}
+
+	collection := &models.UserCollection{
+		UserID: currentUser.ID,
+		Type:   args.Type,
+		Title:  args.Title,
+	}
+
+	if args.ParentID != nil {
+		parent, err := getUserCollection(ctx, c, *args.ParentID)
+		if err != nil {
+			return nil, err
+		}
+		if parent.Type != args.Type {
+			return nil, errors.New("a " + string(args.Type) + " collection can't be created in a " + string(parent.Type) + " collection")
+		}
+		collection.ParentID = parent.ID
+	}
+
+	db := c.GetDB()
+	err = db.Save(collection).Error
+	if err != nil {
+		return nil, err
+	}
+
+	resolver, err := NewUserCollectionResolver(c, collection)
+	if err != nil {
+		return nil, err
+	}
+
+	go bus.Publish("user:"+strconv.Itoa(int(currentUser.ID))+":collections:created", resolver)
+
+	return resolver, nil
+}
+
+type UpdateUserCollectionArgs struct {
+	UserCollectionID graphql.ID
+	Title            *string
+	Properties       *string
+}
+
+func (b *BaseQuery) UpdateUserCollection(ctx context.Context, args *UpdateUserCollectionArgs) (*UserCollectionResolver, error) {
+	c := b.GetReqC(ctx)
+	db := c.GetDB()
+
+	collection, err := getUserCollection(ctx, c, args.UserCollectionID)
+	if err != nil {
+		return nil, err
+	}
+
+	updates := map[string]interface{}{}
+	if args.Title != nil {
+		if strings.TrimSpace(*args.Title) == "" {
+			return nil, errors.New("the title of a collection can't be empty")
+		}
+		updates["title"] = *args.Title
+	}
+	if args.Properties != nil {
+		properties, err := hoppscotch.ParseCollectionProperties(*args.Properties)
+		if err != nil {
+			return nil, errors.New("properties is not valid JSON: " + err.Error())
+		}
+
+		propertiesJSON, err := properties.JSON()
+		if err != nil {
+			return nil, err
+		}
+		updates["properties"] = propertiesJSON
+	}
+
+	if len(updates) > 0 {
+		err = db.Model(collection).Updates(updates).Error
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	resolver, err := NewUserCollectionResolver(c, collection)
+	if err != nil {
+		return nil, err
+	}
+
+	go bus.Publish("user:"+strconv.Itoa(int(collection.UserID))+":collections:updated", resolver)
+
+	return resolver, nil
+}
+
+func (b *BaseQuery) DeleteUserCollection(ctx context.Context, args *UserCollectionArgs) (bool, error) {
+	c := b.GetReqC(ctx)
+	db := c.GetDB()
+
+	collection, err := getUserCollection(ctx, c, args.UserCollectionID)
+	if err != nil {
+		return false, err
+	}
+
+	// The child collections and the requests are removed with it.
+	err = db.Transaction(func(tx *gorm.DB) error {
+		collectionIDs, err := getUserCollectionTreeIDs(tx, collection.UserID, collection.ID)
+		if err != nil {
+			return err
+		}
+		collectionIDs = append(collectionIDs, collection.ID)
+
+		if err := tx.Where("user_id = ? AND user_collection_id IN ?", collection.UserID, collectionIDs).Delete(&models.UserRequest{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("user_id = ? AND id IN ?", collection.UserID, collectionIDs).Delete(&models.UserCollection{}).Error
+	})
+	if err != nil {
+		return false, err
+	}
+
+	go bus.Publish("user:"+strconv.Itoa(int(collection.UserID))+":collections:removed", graphql.ID(strconv.Itoa(int(collection.ID))))
+
+	return true, nil
+}
+
+func (b *BaseQuery) UserCollectionCreated(ctx context.Context) (<-chan *UserCollectionResolver, error) {
+	c := b.GetReqC(ctx)
+	currentUser, err := c.GetUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	notificationChannel := make(chan *UserCollectionResolver)
+	eventHandler := func(resolver *UserCollectionResolver) {
+		notificationChannel <- resolver
+	}
+	err = subscribeUntilDone(ctx, "user:"+strconv.Itoa(int(currentUser.ID))+":collections:created", eventHandler)
+	if err != nil {
+		return nil, err
+	}
+
+	return notificationChannel, nil
+}
+
+func (b *BaseQuery) UserCollectionUpdated(ctx context.Context) (<-chan *UserCollectionResolver, error) {
+	c := b.GetReqC(ctx)
+	currentUser, err := c.GetUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	notificationChannel := make(chan *UserCollectionResolver)
+	eventHandler := func(resolver *UserCollectionResolver) {
+		notificationChannel <- resolver
+	}
+	err = subscribeUntilDone(ctx, "user:"+strconv.Itoa(int(currentUser.ID))+":collections:updated", eventHandler)
+	if err != nil {
+		return nil, err
+	}
+
+	return notificationChannel, nil
+}
+
+func (b *BaseQuery) UserCollectionRemoved(ctx context.Context) (<-chan graphql.ID, error) {
+	c := b.GetReqC(ctx)
+	currentUser, err := c.GetUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	notificationChannel := make(chan graphql.ID)
+	eventHandler := func(id graphql.ID) {
+		notificationChannel <- id
+	}
+	err = subscribeUntilDone(ctx, "user:"+strconv.Itoa(int(currentUser.ID))+":collections:removed", eventHandler)
+	if err != nil {
+		return nil, err
+	}
+
+	return notificationChannel, nil
+}
@@ -0,0 +1,327 @@
+package resolvers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+
+	graphql_context "github.com/jerbob92/hoppscotch-backend/api/controllers/graphql/context"
+	"github.com/jerbob92/hoppscotch-backend/helpers/hoppscotch"
+	"github.com/jerbob92/hoppscotch-backend/models"
+
+	"github.com/graph-gophers/graphql-go"
+	"gorm.io/gorm"
+)
+
+// getUserCollectionExportJSON exports a personal collection including all of
+// its requests and subfolders, in the same format as a team collection.
+func getUserCollectionExportJSON(db *gorm.DB, userCollection *models.UserCollection) (*ExportJSONCollection, error) {
+	collection := &ExportJSONCollection{
+		Version:  exportJSONCollectionVersion,
+		Name:     userCollection.Title,
+		Folders:  []ExportJSONCollection{},
+		Requests: []ExportJSONCollectionRequest{},
+	}
+
+	properties, err := hoppscotch.ParseCollectionProperties(userCollection.Properties)
+	if err != nil {
+		return nil, err
+	}
+	collection.setProperties(properties)
+
+	requests := []*models.UserRequest{}
+	err = db.Model(&models.UserRequest{}).Where("user_id = ? AND user_collection_id = ?", userCollection.UserID, userCollection.ID).Order("id").Find(&requests).Error
+	if err != nil {
+		return nil, err
+	}
+
+	for ri := range requests {
+		requestData, _, err := hoppscotch.MigrateRequestJSON(requests[ri].Request)
+		if err != nil {
+			return nil, fmt.Errorf("request %d in collection %s is not valid: %w", requests[ri].ID, userCollection.Title, err)
+		}
+
+		requestDecode := ExportJSONCollectionRequest{}
+		err = json.Unmarshal([]byte(requestData), &requestDecode)
+		if err != nil {
+			return nil, fmt.Errorf("request %d in collection %s is not valid JSON: %w", requests[ri].ID, userCollection.Title, err)
+		}
+
+		collection.Requests = append(collection.Requests, requestDecode)
+	}
+
+	children := []*models.UserCollection{}
+	err = db.Model(&models.UserCollection{}).Where("user_id = ? AND parent_id = ?", userCollection.UserID, userCollection.ID).Order("id").Find(&children).Error
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range children {
+		folder, err := getUserCollectionExportJSON(db, children[i])
+		if err != nil {
+			return nil, err
+		}
+		collection.Folders = append(collection.Folders, *folder)
+	}
+
+	return collection, nil
+}
+
+// exportJSONRequestData returns the request document without its examples,
+// personal requests don't have examples.
+func exportJSONRequestData(request ExportJSONCollectionRequest) (string, error) {
+	requestDocument := ExportJSONCollectionRequest{}
+	for key, value := range request {
+		requestDocument[key] = value
+	}
+	delete(requestDocument, hoppscotch.ExamplesField)
+
+	requestData, err := json.Marshal(requestDocument)
+	if err != nil {
+		return "", err
+	}
+	return string(requestData), nil
+}
+
+// collectExportJSONRequestKinds adds the kinds of all requests in the
+// collections and their subfolders to kinds.
+func collectExportJSONRequestKinds(folders []ExportJSONCollection, kinds map[string]bool) error {
+	for i := range folders {
+		for ri := range folders[i].Requests {
+			requestData, err := exportJSONRequestData(folders[i].Requests[ri])
+			if err != nil {
+				return err
+			}
+
+			normalized, err := normalizeTeamRequest(requestData, "")
+			if err != nil {
+				var validationErr *RequestValidationError
+				if errors.As(err, &validationErr) {
+					return &RequestValidationError{validationErr.Prefix(folders[i].Name + ".requests[" + strconv.Itoa(ri) + "]")}
+				}
+				return err
+			}
+			kinds[normalized.Kind] = true
+		}
+
+		err := collectExportJSONRequestKinds(folders[i].Folders, kinds)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// exportJSONReqType returns the type of personal collection the collections
+// can be copied into. Team collections can mix REST and GraphQL requests,
+// personal collections can't.
+func exportJSONReqType(folders []ExportJSONCollection) (models.ReqType, error) {
+	kinds := map[string]bool{}
+	err := collectExportJSONRequestKinds(folders, kinds)
+	if err != nil {
+		return "", err
+	}
+
+	if kinds[hoppscotch.RequestKindREST] && kinds[hoppscotch.RequestKindGraphQL] {
+		return "", errors.New("the collection contains both REST and GraphQL requests, a personal collection can only hold one type")
+	}
+	if kinds[hoppscotch.RequestKindGraphQL] {
+		return models.GQLReqType, nil
+	}
+	return models.RESTReqType, nil
+}
+
+// importUserJSONInTransaction stores the collections below the personal
+// parent collection and returns the created collections, without their
+// subfolders.
+func importUserJSONInTransaction(c *graphql_context.Context, db *gorm.DB, events *eventQueue, userID uint, parentID uint, reqType models.ReqType, folders []ExportJSONCollection) ([]*models.UserCollection, error) {
+	newCollections := []*models.UserCollection{}
+	for i := range folders {
+		properties, err := folders[i].properties().JSON()
+		if err != nil {
+			return nil, err
+		}
+
+		newCollection := &models.UserCollection{
+			UserID:     userID,
+			Type:       reqType,
+			Title:      folders[i].Name,
+			ParentID:   parentID,
+			Properties: properties,
+		}
+
+		err = db.Save(newCollection).Error
+		if err != nil {
+			return nil, err
+		}
+		newCollections = append(newCollections, newCollection)
+
+		resolver, err := NewUserCollectionResolver(c, newCollection)
+		if err != nil {
+			return nil, err
+		}
+
+		events.Publish("user:"+strconv.Itoa(int(userID))+":collections:created", resolver)
+
+		for ri := range folders[i].Requests {
+			requestData, err := exportJSONRequestData(folders[i].Requests[ri])
+			if err != nil {
+				return nil, err
+			}
+
+			normalized, err := normalizeUserRequest(requestData, "", reqType)
+			if err != nil {
+				var validationErr *RequestValidationError
+				if errors.As(err, &validationErr) {
+					return nil, &RequestValidationError{validationErr.Prefix(folders[i].Name + ".requests[" + strconv.Itoa(ri) + "]")}
+				}
+				return nil, err
+			}
+
+			newRequest := &models.UserRequest{
+				UserID:           userID,
+				UserCollectionID: newCollection.ID,
+				Type:             reqType,
+				Title:            normalized.Name,
+				Request:          normalized.JSON,
+			}
+
+			err = db.Save(newRequest).Error
+			if err != nil {
+				return nil, err
+			}
+
+			requestResolver, err := NewUserRequestResolver(c, newRequest)
+			if err != nil {
+				return nil, err
+			}
+
+			events.Publish("user:"+strconv.Itoa(int(userID))+":requests:created", requestResolver)
+		}
+
+		_, err = importUserJSONInTransaction(c, db, events, userID, newCollection.ID, reqType, folders[i].Folders)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return newCollections, nil
+}
+
+type CopyUserCollectionToTeamArgs struct {
+	UserCollectionID   graphql.ID
+	TeamID             graphql.ID
+	ParentCollectionID *graphql.ID
+}
+
+func (b *BaseQuery) CopyUserCollectionToTeam(ctx context.Context, args *CopyUserCollectionToTeamArgs) (*TeamCollectionResolver, error) {
+	c := b.GetReqC(ctx)
+	db := c.GetDB()
+
+	collection, err := getUserCollection(ctx, c, args.UserCollectionID)
+	if err != nil {
+		return nil, err
+	}
+
+	teamID, parentCollectionID, err := getImportTarget(ctx, c, args.TeamID, args.ParentCollectionID)
+	if err != nil {
+		return nil, err
+	}
+
+	collectionExport, err := getUserCollectionExportJSON(db, collection)
+	if err != nil {
+		return nil, err
+	}
+
+	currentUser, err := c.GetUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	newCollections := []*models.TeamCollection{}
+	events := &eventQueue{}
+	err = db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		newCollections, err = importJSONInTransaction(c, tx, events, teamID, parentCollectionID, currentUser.ID, []ExportJSONCollection{*collectionExport})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	events.Flush()
+
+	return NewTeamCollectionResolver(c, newCollections[0])
+}
+
+type CopyTeamCollectionToUserArgs struct {
+	CollectionID           graphql.ID
+	ParentUserCollectionID *graphql.ID
+}
+
+func (b *BaseQuery) CopyTeamCollectionToUser(ctx context.Context, args *CopyTeamCollectionToUserArgs) (*UserCollectionResolver, error) {
+	c := b.GetReqC(ctx)
+	currentUser, err := c.GetUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	db := c.GetDB()
+	collection := &models.TeamCollection{}
+	err = db.Model(&models.TeamCollection{}).Where("id = ?", args.CollectionID).First(collection).Error
+	if err != nil && err == gorm.ErrRecordNotFound {
+		return nil, errors.New("you do not have access to this collection")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Every member can read the collection, so every member can copy it.
+	userRole, err := getUserRoleInTeam(ctx, c, collection.TeamID)
+	if err != nil {
+		return nil, err
+	}
+
+	if userRole == nil {
+		return nil, errors.New("you do not have access to this collection")
+	}
+
+	collectionExport, err := GetCollectionExportJSON(c, collection)
+	if err != nil {
+		return nil, err
+	}
+	folders := []ExportJSONCollection{*collectionExport}
+
+	parentID := uint(0)
+	var reqType models.ReqType
+	if args.ParentUserCollectionID != nil {
+		parent, err := getUserCollection(ctx, c, *args.ParentUserCollectionID)
+		if err != nil {
+			return nil, err
+		}
+		parentID = parent.ID
+		reqType = parent.Type
+	} else {
+		reqType, err = exportJSONReqType(folders)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	newCollections := []*models.UserCollection{}
+	events := &eventQueue{}
+	err = db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		newCollections, err = importUserJSONInTransaction(c, tx, events, currentUser.ID, parentID, reqType, folders)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	events.Flush()
+
+	return NewUserCollectionResolver(c, newCollections[0])
+}
@@ -0,0 +1,347 @@
+package resolvers
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+
+	graphql_context "github.com/jerbob92/hoppscotch-backend/api/controllers/graphql/context"
+	"github.com/jerbob92/hoppscotch-backend/helpers/hoppscotch"
+	"github.com/jerbob92/hoppscotch-backend/models"
+
+	"github.com/graph-gophers/graphql-go"
+	"gorm.io/gorm"
+)
+
+type UserEnvironmentResolver struct {
+	c                *graphql_context.Context
+	user_environment *models.UserEnvironment
+}
+
+func NewUserEnvironmentResolver(c *graphql_context.Context, user_environment *models.UserEnvironment) (*UserEnvironmentResolver, error) {
+	if user_environment == nil {
+		return nil, nil
+	}
+
+	return &UserEnvironmentResolver{c: c, user_environment: user_environment}, nil
+}
+
+func (r *UserEnvironmentResolver) ID() (graphql.ID, error) {
+	id := graphql.ID(strconv.Itoa(int(r.user_environment.ID)))
+	return id, nil
+}
+
+func (r *UserEnvironmentResolver) Name() (*string, error) {
+	if r.user_environment.IsGlobal {
+		return nil, nil
+	}
+	return &r.user_environment.Name, nil
+}
+
+func (r *UserEnvironmentResolver) Variables() (string, error) {
+	return r.user_environment.Variables, nil
+}
+
+func (r *UserEnvironmentResolver) IsGlobal() (bool, error) {
+	return r.user_environment.IsGlobal, nil
+}
+
+// validateUserEnvironmentVariables checks that the variables are a JSON list
+// of environment variables. They are stored as they are, so fields of newer
+// frontends are kept.
+func validateUserEnvironmentVariables(variables string) error {
+	_, err := hoppscotch.ParseEnvironmentVariables(variables)
+	if err != nil {
+		return errors.New("variables is not a valid list of variables: " + err.Error())
+	}
+	return nil
+}
+
+// getUserEnvironment loads an environment of the current user.
+func getUserEnvironment(ctx context.Context, c *graphql_context.Context, environmentID graphql.ID) (*models.UserEnvironment, error) {
+	currentUser, err := c.GetUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	environment := &models.UserEnvironment{}
+	db := c.GetDB()
+	err = db.Model(&models.UserEnvironment{}).Where("id = ? AND user_id = ?", environmentID, currentUser.ID).First(environment).Error
+	if err != nil && err == gorm.ErrRecordNotFound {
+		return nil, errors.New("you do not have access to this environment")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return environment, nil
+}
+
+func (b *BaseQuery) MyEnvironments(ctx context.Context) ([]*UserEnvironmentResolver, error) {
+	c := b.GetReqC(ctx)
+	currentUser, err := c.GetUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	environments := []*models.UserEnvironment{}
+	db := c.GetDB()
+	err = db.Model(&models.UserEnvironment{}).Where("user_id = ? AND is_global = ?", currentUser.ID, false).Order("id").Find(&environments).Error
+	if err != nil {
+		return nil, err
+	}
+
+	environmentResolvers := []*UserEnvironmentResolver{}
+	for i := range environments {
+		newResolver, err := NewUserEnvironmentResolver(c, environments[i])
+		if err != nil {
+			return nil, err
+		}
+		environmentResolvers = append(environmentResolvers, newResolver)
+	}
+
+	return environmentResolvers, nil
+}
+
+func (b *BaseQuery) MyGlobalEnvironment(ctx context.Context) (*UserEnvironmentResolver, error) {
+	c := b.GetReqC(ctx)
+	currentUser, err := c.GetUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	environment := &models.UserEnvironment{}
+	db := c.GetDB()
+	err = db.Model(&models.UserEnvironment{}).Where("user_id = ? AND is_global = ?", currentUser.ID, true).First(environment).Error
+	if err != nil && err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return NewUserEnvironmentResolver(c, environment)
+}
+
+type CreateUserEnvironmentArgs struct {
+	Name      string
+	Variables string
+}
+
+func (b *BaseQuery) CreateUserEnvironment(ctx context.Context, args *CreateUserEnvironmentArgs) (*UserEnvironmentResolver, error) {
+	c := b.GetReqC(ctx)
+	currentUser, err := c.GetUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if strings.TrimSpace(args.Name) == "" {
+		return nil, errors.New("the name of an environment can't be empty")
+	}
+
+	err = validateUserEnvironmentVariables(args.Variables)
+	if err != nil {
+		return nil, err
+	}
+
+	environment := &models.UserEnvironment{
+		UserID:    currentUser.ID,
+		Name:      args.Name,
+		Variables: args.Variables,
+	}
+
+	db := c.GetDB()
+	err = db.Save(environment).Error
+	if err != nil {
+		return nil, err
+	}
+
+	resolver, err := NewUserEnvironmentResolver(c, environment)
+	if err != nil {
+		return nil, err
+	}
+
+	go bus.Publish("user:"+strconv.Itoa(int(currentUser.ID))+":environments:created", resolver)
+
+	return resolver, nil
+}
+
+type UpdateUserEnvironmentArgs struct {
+	ID        graphql.ID
+	Name      string
+	Variables string
+}
+
+func (b *BaseQuery) UpdateUserEnvironment(ctx context.Context, args *UpdateUserEnvironmentArgs) (*UserEnvironmentResolver, error) {
+	c := b.GetReqC(ctx)
+	db := c.GetDB()
+
+	environment, err := getUserEnvironment(ctx, c, args.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if environment.IsGlobal {
+		return nil, errors.New("use updateUserGlobalEnvironment to change the global environment")
+	}
+
+	if strings.TrimSpace(args.Name) == "" {
+		return nil, errors.New("the name of an environment can't be empty")
+	}
+
+	err = validateUserEnvironmentVariables(args.Variables)
+	if err != nil {
+		return nil, err
+	}
+
+	err = db.Model(environment).Updates(map[string]interface{}{
+		"name":      args.Name,
+		"variables": args.Variables,
+	}).Error
+	if err != nil {
+		return nil, err
+	}
+
+	resolver, err := NewUserEnvironmentResolver(c, environment)
+	if err != nil {
+		return nil, err
+	}
+
+	go bus.Publish("user:"+strconv.Itoa(int(environment.UserID))+":environments:updated", resolver)
+
+	return resolver, nil
+}
+
+type UpdateUserGlobalEnvironmentArgs struct {
+	Variables string
+}
+
+func (b *BaseQuery) UpdateUserGlobalEnvironment(ctx context.Context, args *UpdateUserGlobalEnvironmentArgs) (*UserEnvironmentResolver, error) {
+	c := b.GetReqC(ctx)
+	currentUser, err := c.GetUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	err = validateUserEnvironmentVariables(args.Variables)
+	if err != nil {
+		return nil, err
+	}
+
+	environment := &models.UserEnvironment{}
+	db := c.GetDB()
+	err = db.Model(&models.UserEnvironment{}).Where("user_id = ? AND is_global = ?", currentUser.ID, true).First(environment).Error
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return nil, err
+	}
+
+	// The global environment is created on its first update.
+	event := "updated"
+	if err == gorm.ErrRecordNotFound {
+		environment = &models.UserEnvironment{
+			UserID:   currentUser.ID,
+			IsGlobal: true,
+		}
+		event = "created"
+	}
+
+	environment.Variables = args.Variables
+	err = db.Save(environment).Error
+	if err != nil {
+		return nil, err
+	}
+
+	resolver, err := NewUserEnvironmentResolver(c, environment)
+	if err != nil {
+		return nil, err
+	}
+
+	go bus.Publish("user:"+strconv.Itoa(int(currentUser.ID))+":environments:"+event, resolver)
+
+	return resolver, nil
+}
+
+type DeleteUserEnvironmentArgs struct {
+	ID graphql.ID
+}
+
+func (b *BaseQuery) DeleteUserEnvironment(ctx context.Context, args *DeleteUserEnvironmentArgs) (bool, error) {
+	c := b.GetReqC(ctx)
+	db := c.GetDB()
+
+	environment, err := getUserEnvironment(ctx, c, args.ID)
+	if err != nil {
+		return false, err
+	}
+
+	if environment.IsGlobal {
+		return false, errors.New("the global environment can't be deleted")
+	}
+
+	err = db.Delete(environment).Error
+	if err != nil {
+		return false, err
+	}
+
+	go bus.Publish("user:"+strconv.Itoa(int(environment.UserID))+":environments:deleted", graphql.ID(strconv.Itoa(int(environment.ID))))
+
+	return true, nil
+}
+
+func (b *BaseQuery) UserEnvironmentCreated(ctx context.Context) (<-chan *UserEnvironmentResolver, error) {
+	c := b.GetReqC(ctx)
+	currentUser, err := c.GetUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	notificationChannel := make(chan *UserEnvironmentResolver)
+	eventHandler := func(resolver *UserEnvironmentResolver) {
+		notificationChannel <- resolver
+	}
+	err = subscribeUntilDone(ctx, "user:"+strconv.Itoa(int(currentUser.ID))+":environments:created", eventHandler)
+	if err != nil {
+		return nil, err
+	}
+
+	return notificationChannel, nil
+}
+
+func (b *BaseQuery) UserEnvironmentUpdated(ctx context.Context) (<-chan *UserEnvironmentResolver, error) {
+	c := b.GetReqC(ctx)
+	currentUser, err := c.GetUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	notificationChannel := make(chan *UserEnvironmentResolver)
+	eventHandler := func(resolver *UserEnvironmentResolver) {
+		notificationChannel <- resolver
+	}
+	err = subscribeUntilDone(ctx, "user:"+strconv.Itoa(int(currentUser.ID))+":environments:updated", eventHandler)
+	if err != nil {
+		return nil, err
+	}
+
+	return notificationChannel, nil
+}
+
+func (b *BaseQuery) UserEnvironmentDeleted(ctx context.Context) (<-chan graphql.ID, error) {
+	c := b.GetReqC(ctx)
+	currentUser, err := c.GetUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	notificationChannel := make(chan graphql.ID)
+	eventHandler := func(id graphql.ID) {
+		notificationChannel <- id
+	}
+	err = subscribeUntilDone(ctx, "user:"+strconv.Itoa(int(currentUser.ID))+":environments:deleted", eventHandler)
+	if err != nil {
+		return nil, err
+	}
+
+	return notificationChannel, nil
+}
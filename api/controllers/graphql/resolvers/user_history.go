@@ -0,0 +1,363 @@
+package resolvers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strconv"
+
+	graphql_context "github.com/jerbob92/hoppscotch-backend/api/controllers/graphql/context"
+	"github.com/jerbob92/hoppscotch-backend/models"
+
+	"github.com/graph-gophers/graphql-go"
+	"github.com/sanae10001/graphql-go-extension-scalars"
+	"github.com/spf13/viper"
+	"gorm.io/gorm"
+)
+
+type UserHistoryResolver struct {
+	c            *graphql_context.Context
+	user_history *models.UserHistory
+}
+
+func NewUserHistoryResolver(c *graphql_context.Context, user_history *models.UserHistory) (*UserHistoryResolver, error) {
+	if user_history == nil {
+		return nil, nil
+	}
+
+	return &UserHistoryResolver{c: c, user_history: user_history}, nil
+}
+
+func (r *UserHistoryResolver) ID() (graphql.ID, error) {
+	id := graphql.ID(strconv.Itoa(int(r.user_history.ID)))
+	return id, nil
+}
+
+func (r *UserHistoryResolver) ReqType() (models.ReqType, error) {
+	return r.user_history.ReqType, nil
+}
+
+func (r *UserHistoryResolver) Request() (string, error) {
+	return r.user_history.Request, nil
+}
+
+func (r *UserHistoryResolver) ResponseMetadata() (string, error) {
+	return r.user_history.ResponseMetadata, nil
+}
+
+func (r *UserHistoryResolver) IsStarred() (bool, error) {
+	return r.user_history.IsStarred, nil
+}
+
+func (r *UserHistoryResolver) ExecutedOn() (scalars.DateTime, error) {
+	return *scalars.NewDateTime(r.user_history.CreatedAt), nil
+}
+
+// trimUserHistory removes the oldest entries of the type that are not
+// starred when the user has more than the configured maximum, and returns
+// the IDs of the removed entries.
+func trimUserHistory(db *gorm.DB, userID uint, reqType models.ReqType) ([]uint, error) {
+	maxEntries := viper.GetInt("api.history.maxPerUser")
+	if maxEntries <= 0 {
+		return nil, nil
+	}
+
+	query := db.Model(&models.UserHistory{}).Where("user_id = ? AND req_type = ? AND is_starred = ?", userID, reqType, false)
+
+	keepIDs := []uint{}
+	err := query.Session(&gorm.Session{}).Order("id DESC").Limit(maxEntries).Pluck("id", &keepIDs).Error
+	if err != nil {
+		return nil, err
+	}
+
+	if len(keepIDs) < maxEntries {
+		return nil, nil
+	}
+
+	removeIDs := []uint{}
+	err = query.Session(&gorm.Session{}).Where("id NOT IN ?", keepIDs).Pluck("id", &removeIDs).Error
+	if err != nil {
+		return nil, err
+	}
+
+	if len(removeIDs) == 0 {
+		return nil, nil
+	}
+
+	err = db.Unscoped().Where("id IN ?", removeIDs).Delete(&models.UserHistory{}).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return removeIDs, nil
+}
+
+// getUserHistory loads a history entry of the current user.
+func getUserHistory(ctx context.Context, c *graphql_context.Context, historyID graphql.ID) (*models.UserHistory, error) {
+	currentUser, err := c.GetUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	history := &models.UserHistory{}
+	db := c.GetDB()
+	err = db.Model(&models.UserHistory{}).Where("id = ? AND user_id = ?", historyID, currentUser.ID).First(history).Error
+	if err != nil && err == gorm.ErrRecordNotFound {
+		return nil, errors.New("you do not have access to this history entry")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return history, nil
+}
+
+type MyHistoryArgs struct {
+	ReqType models.ReqType
+	Starred *bool
+	Cursor  *graphql.ID
+	Take    *int32
+}
+
+func (b *BaseQuery) MyHistory(ctx context.Context, args *MyHistoryArgs) ([]*UserHistoryResolver, error) {
+	c := b.GetReqC(ctx)
+	currentUser, err := c.GetUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	history := []*models.UserHistory{}
+	db := c.GetDB()
+	query := db.Model(&models.UserHistory{}).Where("user_id = ? AND req_type = ?", currentUser.ID, args.ReqType)
+	if args.Starred != nil {
+		query = query.Where("is_starred = ?", *args.Starred)
+	}
+
+	// The history is listed newest first, the cursor is the last ID of the
+	// previous page.
+	if args.Cursor != nil && *args.Cursor != "" {
+		query = query.Where("id < ?", args.Cursor)
+	}
+	query = query.Order("id DESC")
+	if size := pageSize(args.Take, 0); size > 0 {
+		query = query.Limit(size)
+	}
+
+	err = query.Find(&history).Error
+	if err != nil {
+		return nil, err
+	}
+
+	historyResolvers := []*UserHistoryResolver{}
+	for i := range history {
+		newResolver, err := NewUserHistoryResolver(c, history[i])
+		if err != nil {
+			return nil, err
+		}
+		historyResolvers = append(historyResolvers, newResolver)
+	}
+
+	return historyResolvers, nil
+}
+
+type CreateUserHistoryArgs struct {
+	ReqType          models.ReqType
+	Request          string
+	ResponseMetadata string
+}
+
+func (b *BaseQuery) CreateUserHistory(ctx context.Context, args *CreateUserHistoryArgs) (*UserHistoryResolver, error) {
+	c := b.GetReqC(ctx)
+	currentUser, err := c.GetUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if !json.Valid([]byte(args.Request)) {
+		return nil, errors.New("request is not valid JSON")
+	}
+	if !json.Valid([]byte(args.ResponseMetadata)) {
+		return nil, errors.New("responseMetadata is not valid JSON")
+	}
+
+	history := &models.UserHistory{
+		UserID:           currentUser.ID,
+		ReqType:          args.ReqType,
+		Request:          args.Request,
+		ResponseMetadata: args.ResponseMetadata,
+	}
+
+	removedIDs := []uint{}
+	db := c.GetDB()
+	err = db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(history).Error; err != nil {
+			return err
+		}
+
+		var err error
+		removedIDs, err = trimUserHistory(tx, currentUser.ID, args.ReqType)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	resolver, err := NewUserHistoryResolver(c, history)
+	if err != nil {
+		return nil, err
+	}
+
+	events := &eventQueue{}
+	events.Publish("user:"+strconv.Itoa(int(currentUser.ID))+":history:created", resolver)
+	for _, removedID := range removedIDs {
+		events.Publish("user:"+strconv.Itoa(int(currentUser.ID))+":history:deleted", graphql.ID(strconv.Itoa(int(removedID))))
+	}
+	events.Flush()
+
+	return resolver, nil
+}
+
+type UserHistoryArgs struct {
+	ID graphql.ID
+}
+
+func (b *BaseQuery) ToggleHistoryStarStatus(ctx context.Context, args *UserHistoryArgs) (*UserHistoryResolver, error) {
+	c := b.GetReqC(ctx)
+	db := c.GetDB()
+
+	history, err := getUserHistory(ctx, c, args.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = db.Model(history).Update("is_starred", !history.IsStarred).Error
+	if err != nil {
+		return nil, err
+	}
+
+	resolver, err := NewUserHistoryResolver(c, history)
+	if err != nil {
+		return nil, err
+	}
+
+	go bus.Publish("user:"+strconv.Itoa(int(history.UserID))+":history:updated", resolver)
+
+	return resolver, nil
+}
+
+func (b *BaseQuery) RemoveRequestFromHistory(ctx context.Context, args *UserHistoryArgs) (bool, error) {
+	c := b.GetReqC(ctx)
+	db := c.GetDB()
+
+	history, err := getUserHistory(ctx, c, args.ID)
+	if err != nil {
+		return false, err
+	}
+
+	err = db.Unscoped().Delete(history).Error
+	if err != nil {
+		return false, err
+	}
+
+	go bus.Publish("user:"+strconv.Itoa(int(history.UserID))+":history:deleted", graphql.ID(strconv.Itoa(int(history.ID))))
+
+	return true, nil
+}
+
+type DeleteAllUserHistoryArgs struct {
+	ReqType models.ReqType
+}
+
+func (b *BaseQuery) DeleteAllUserHistory(ctx context.Context, args *DeleteAllUserHistoryArgs) (int32, error) {
+	c := b.GetReqC(ctx)
+	currentUser, err := c.GetUser(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	db := c.GetDB()
+	result := db.Unscoped().Where("user_id = ? AND req_type = ?", currentUser.ID, args.ReqType).Delete(&models.UserHistory{})
+	if result.Error != nil {
+		return 0, result.Error
+	}
+
+	go bus.Publish("user:"+strconv.Itoa(int(currentUser.ID))+":history:cleared", args.ReqType)
+
+	return int32(result.RowsAffected), nil
+}
+
+func (b *BaseQuery) UserHistoryCreated(ctx context.Context) (<-chan *UserHistoryResolver, error) {
+	c := b.GetReqC(ctx)
+	currentUser, err := c.GetUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	notificationChannel := make(chan *UserHistoryResolver)
+	eventHandler := func(resolver *UserHistoryResolver) {
+		notificationChannel <- resolver
+	}
+	err = subscribeUntilDone(ctx, "user:"+strconv.Itoa(int(currentUser.ID))+":history:created", eventHandler)
+	if err != nil {
+		return nil, err
+	}
+
+	return notificationChannel, nil
+}
+
+func (b *BaseQuery) UserHistoryUpdated(ctx context.Context) (<-chan *UserHistoryResolver, error) {
+	c := b.GetReqC(ctx)
+	currentUser, err := c.GetUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	notificationChannel := make(chan *UserHistoryResolver)
+	eventHandler := func(resolver *UserHistoryResolver) {
+		notificationChannel <- resolver
+	}
+	err = subscribeUntilDone(ctx, "user:"+strconv.Itoa(int(currentUser.ID))+":history:updated", eventHandler)
+	if err != nil {
+		return nil, err
+	}
+
+	return notificationChannel, nil
+}
+
+func (b *BaseQuery) UserHistoryDeleted(ctx context.Context) (<-chan graphql.ID, error) {
+	c := b.GetReqC(ctx)
+	currentUser, err := c.GetUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	notificationChannel := make(chan graphql.ID)
+	eventHandler := func(id graphql.ID) {
+		notificationChannel <- id
+	}
+	err = subscribeUntilDone(ctx, "user:"+strconv.Itoa(int(currentUser.ID))+":history:deleted", eventHandler)
+	if err != nil {
+		return nil, err
+	}
+
+	return notificationChannel, nil
+}
+
+func (b *BaseQuery) UserHistoryCleared(ctx context.Context) (<-chan models.ReqType, error) {
+	c := b.GetReqC(ctx)
+	currentUser, err := c.GetUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	notificationChannel := make(chan models.ReqType)
+	eventHandler := func(reqType models.ReqType) {
+		notificationChannel <- reqType
+	}
+	err = subscribeUntilDone(ctx, "user:"+strconv.Itoa(int(currentUser.ID))+":history:cleared", eventHandler)
+	if err != nil {
+		return nil, err
+	}
+
+	return notificationChannel, nil
+}
@@ -0,0 +1,294 @@
+package resolvers
+
+import (
+	"context"
+	"errors"
+	"strconv"
+
+	graphql_context "github.com/jerbob92/hoppscotch-backend/api/controllers/graphql/context"
+	"github.com/jerbob92/hoppscotch-backend/helpers/hoppscotch"
+	"github.com/jerbob92/hoppscotch-backend/models"
+
+	"github.com/graph-gophers/graphql-go"
+	"github.com/sanae10001/graphql-go-extension-scalars"
+	"gorm.io/gorm"
+)
+
+type UserRequestResolver struct {
+	c            *graphql_context.Context
+	user_request *models.UserRequest
+}
+
+func NewUserRequestResolver(c *graphql_context.Context, user_request *models.UserRequest) (*UserRequestResolver, error) {
+	if user_request == nil {
+		return nil, nil
+	}
+
+	return &UserRequestResolver{c: c, user_request: user_request}, nil
+}
+
+func (r *UserRequestResolver) ID() (graphql.ID, error) {
+	id := graphql.ID(strconv.Itoa(int(r.user_request.ID)))
+	return id, nil
+}
+
+func (r *UserRequestResolver) CollectionID() (graphql.ID, error) {
+	return graphql.ID(strconv.Itoa(int(r.user_request.UserCollectionID))), nil
+}
+
+func (r *UserRequestResolver) Title() (string, error) {
+	return r.user_request.Title, nil
+}
+
+func (r *UserRequestResolver) Type() (models.ReqType, error) {
+	return r.user_request.Type, nil
+}
+
+func (r *UserRequestResolver) Request() (string, error) {
+	data, _, err := hoppscotch.MigrateRequestJSON(r.user_request.Request)
+	if err != nil {
+		return r.user_request.Request, nil
+	}
+	return data, nil
+}
+
+func (r *UserRequestResolver) CreatedOn() (scalars.DateTime, error) {
+	return *scalars.NewDateTime(r.user_request.CreatedAt), nil
+}
+
+func (r *UserRequestResolver) UpdatedOn() (scalars.DateTime, error) {
+	return *scalars.NewDateTime(r.user_request.UpdatedAt), nil
+}
+
+// normalizeUserRequest validates the request document like a team request,
+// and checks that it's of the type of its collection.
+func normalizeUserRequest(data string, defaultName string, reqType models.ReqType) (*hoppscotch.NormalizedRequest, error) {
+	normalized, err := normalizeTeamRequest(data, defaultName)
+	if err != nil {
+		return nil, err
+	}
+
+	kind := hoppscotch.RequestKindREST
+	if reqType == models.GQLReqType {
+		kind = hoppscotch.RequestKindGraphQL
+	}
+	if normalized.Kind != kind {
+		return nil, errors.New("a " + normalized.Kind + " request can't be stored in a " + string(reqType) + " collection")
+	}
+
+	return normalized, nil
+}
+
+// getUserRequest loads a request of the current user.
+func getUserRequest(ctx context.Context, c *graphql_context.Context, requestID graphql.ID) (*models.UserRequest, error) {
+	currentUser, err := c.GetUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	request := &models.UserRequest{}
+	db := c.GetDB()
+	err = db.Model(&models.UserRequest{}).Where("id = ? AND user_id = ?", requestID, currentUser.ID).First(request).Error
+	if err != nil && err == gorm.ErrRecordNotFound {
+		return nil, errors.New("you do not have access to this request")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return request, nil
+}
+
+type UserRequestArgs struct {
+	UserRequestID graphql.ID
+}
+
+func (b *BaseQuery) UserRequest(ctx context.Context, args *UserRequestArgs) (*UserRequestResolver, error) {
+	c := b.GetReqC(ctx)
+
+	request, err := getUserRequest(ctx, c, args.UserRequestID)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewUserRequestResolver(c, request)
+}
+
+type CreateUserRequestArgs struct {
+	CollectionID graphql.ID
+	Title        *string
+	Request      string
+}
+
+func (b *BaseQuery) CreateUserRequest(ctx context.Context, args *CreateUserRequestArgs) (*UserRequestResolver, error) {
+	c := b.GetReqC(ctx)
+	db := c.GetDB()
+
+	collection, err := getUserCollection(ctx, c, args.CollectionID)
+	if err != nil {
+		return nil, err
+	}
+
+	normalized, err := normalizeUserRequest(args.Request, "Untitled request", collection.Type)
+	if err != nil {
+		return nil, err
+	}
+
+	if args.Title != nil {
+		err := normalized.Rename(*args.Title)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	request := &models.UserRequest{
+		UserID:           collection.UserID,
+		UserCollectionID: collection.ID,
+		Type:             collection.Type,
+		Title:            normalized.Name,
+		Request:          normalized.JSON,
+	}
+
+	err = db.Save(request).Error
+	if err != nil {
+		return nil, err
+	}
+
+	resolver, err := NewUserRequestResolver(c, request)
+	if err != nil {
+		return nil, err
+	}
+
+	go bus.Publish("user:"+strconv.Itoa(int(request.UserID))+":requests:created", resolver)
+
+	return resolver, nil
+}
+
+type UpdateUserRequestArgs struct {
+	UserRequestID graphql.ID
+	Title         *string
+	Request       *string
+}
+
+func (b *BaseQuery) UpdateUserRequest(ctx context.Context, args *UpdateUserRequestArgs) (*UserRequestResolver, error) {
+	c := b.GetReqC(ctx)
+	db := c.GetDB()
+
+	request, err := getUserRequest(ctx, c, args.UserRequestID)
+	if err != nil {
+		return nil, err
+	}
+
+	if args.Title != nil || args.Request != nil {
+		requestData := request.Request
+		if args.Request != nil {
+			requestData = *args.Request
+		}
+
+		normalized, err := normalizeUserRequest(requestData, request.Title, request.Type)
+		if err != nil {
+			return nil, err
+		}
+
+		// The title is the name in the request, renaming changes both.
+		if args.Title != nil {
+			err := normalized.Rename(*args.Title)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		err = db.Model(request).Updates(map[string]interface{}{
+			"title":   normalized.Name,
+			"request": normalized.JSON,
+		}).Error
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	resolver, err := NewUserRequestResolver(c, request)
+	if err != nil {
+		return nil, err
+	}
+
+	go bus.Publish("user:"+strconv.Itoa(int(request.UserID))+":requests:updated", resolver)
+
+	return resolver, nil
+}
+
+func (b *BaseQuery) DeleteUserRequest(ctx context.Context, args *UserRequestArgs) (bool, error) {
+	c := b.GetReqC(ctx)
+	db := c.GetDB()
+
+	request, err := getUserRequest(ctx, c, args.UserRequestID)
+	if err != nil {
+		return false, err
+	}
+
+	err = db.Delete(request).Error
+	if err != nil {
+		return false, err
+	}
+
+	go bus.Publish("user:"+strconv.Itoa(int(request.UserID))+":requests:deleted", graphql.ID(strconv.Itoa(int(request.ID))))
+
+	return true, nil
+}
+
+func (b *BaseQuery) UserRequestCreated(ctx context.Context) (<-chan *UserRequestResolver, error) {
+	c := b.GetReqC(ctx)
+	currentUser, err := c.GetUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	notificationChannel := make(chan *UserRequestResolver)
+	eventHandler := func(resolver *UserRequestResolver) {
+		notificationChannel <- resolver
+	}
+	err = subscribeUntilDone(ctx, "user:"+strconv.Itoa(int(currentUser.ID))+":requests:created", eventHandler)
+	if err != nil {
+		return nil, err
+	}
+
+	return notificationChannel, nil
+}
+
+func (b *BaseQuery) UserRequestUpdated(ctx context.Context) (<-chan *UserRequestResolver, error) {
+	c := b.GetReqC(ctx)
+	currentUser, err := c.GetUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	notificationChannel := make(chan *UserRequestResolver)
+	eventHandler := func(resolver *UserRequestResolver) {
+		notificationChannel <- resolver
+	}
+	err = subscribeUntilDone(ctx, "user:"+strconv.Itoa(int(currentUser.ID))+":requests:updated", eventHandler)
+	if err != nil {
+		return nil, err
+	}
+
+	return notificationChannel, nil
+}
+
+func (b *BaseQuery) UserRequestDeleted(ctx context.Context) (<-chan graphql.ID, error) {
+	c := b.GetReqC(ctx)
+	currentUser, err := c.GetUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	notificationChannel := make(chan graphql.ID)
+	eventHandler := func(id graphql.ID) {
+		notificationChannel <- id
+	}
+	err = subscribeUntilDone(ctx, "user:"+strconv.Itoa(int(currentUser.ID))+":requests:deleted", eventHandler)
+	if err != nil {
+		return nil, err
+	}
+
+	return notificationChannel, nil
+}
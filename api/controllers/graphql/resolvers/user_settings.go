@@ -0,0 +1,124 @@
+package resolvers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strconv"
+
+	graphql_context "github.com/jerbob92/hoppscotch-backend/api/controllers/graphql/context"
+	"github.com/jerbob92/hoppscotch-backend/models"
+
+	"github.com/graph-gophers/graphql-go"
+	"github.com/sanae10001/graphql-go-extension-scalars"
+	"gorm.io/gorm"
+)
+
+type UserSettingsResolver struct {
+	c             *graphql_context.Context
+	user_settings *models.UserSettings
+}
+
+func NewUserSettingsResolver(c *graphql_context.Context, user_settings *models.UserSettings) (*UserSettingsResolver, error) {
+	if user_settings == nil {
+		return nil, nil
+	}
+
+	return &UserSettingsResolver{c: c, user_settings: user_settings}, nil
+}
+
+func (r *UserSettingsResolver) ID() (graphql.ID, error) {
+	id := graphql.ID(strconv.Itoa(int(r.user_settings.ID)))
+	return id, nil
+}
+
+func (r *UserSettingsResolver) Properties() (string, error) {
+	return r.user_settings.Properties, nil
+}
+
+func (r *UserSettingsResolver) UpdatedOn() (scalars.DateTime, error) {
+	return *scalars.NewDateTime(r.user_settings.UpdatedAt), nil
+}
+
+func (b *BaseQuery) MySettings(ctx context.Context) (*UserSettingsResolver, error) {
+	c := b.GetReqC(ctx)
+	currentUser, err := c.GetUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	settings := &models.UserSettings{}
+	db := c.GetDB()
+	err = db.Model(&models.UserSettings{}).Where("user_id = ?", currentUser.ID).First(settings).Error
+	if err != nil && err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return NewUserSettingsResolver(c, settings)
+}
+
+type UpdateUserSettingsArgs struct {
+	Properties string
+}
+
+func (b *BaseQuery) UpdateUserSettings(ctx context.Context, args *UpdateUserSettingsArgs) (*UserSettingsResolver, error) {
+	c := b.GetReqC(ctx)
+	currentUser, err := c.GetUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	properties := map[string]interface{}{}
+	if err := json.Unmarshal([]byte(args.Properties), &properties); err != nil || properties == nil {
+		return nil, errors.New("properties must be a JSON object")
+	}
+
+	settings := &models.UserSettings{}
+	db := c.GetDB()
+	err = db.Model(&models.UserSettings{}).Where("user_id = ?", currentUser.ID).First(settings).Error
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return nil, err
+	}
+
+	// The settings are created on their first update.
+	if err == gorm.ErrRecordNotFound {
+		settings = &models.UserSettings{UserID: currentUser.ID}
+	}
+
+	settings.Properties = args.Properties
+	err = db.Save(settings).Error
+	if err != nil {
+		return nil, err
+	}
+
+	resolver, err := NewUserSettingsResolver(c, settings)
+	if err != nil {
+		return nil, err
+	}
+
+	go bus.Publish("user:"+strconv.Itoa(int(currentUser.ID))+":settings:updated", resolver)
+
+	return resolver, nil
+}
+
+func (b *BaseQuery) UserSettingsUpdated(ctx context.Context) (<-chan *UserSettingsResolver, error) {
+	c := b.GetReqC(ctx)
+	currentUser, err := c.GetUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	notificationChannel := make(chan *UserSettingsResolver)
+	eventHandler := func(resolver *UserSettingsResolver) {
+		notificationChannel <- resolver
+	}
+	err = subscribeUntilDone(ctx, "user:"+strconv.Itoa(int(currentUser.ID))+":settings:updated", eventHandler)
+	if err != nil {
+		return nil, err
+	}
+
+	return notificationChannel, nil
+}
@@ -46,7 +46,8 @@ func (t graphqlTracer) TraceQuery(ctx context.Context, queryString string, opera
 	//log.Println(queryString)
 	//log.Println(operationName)
 	//log.Println(variables)
-	return ctx, func(errs []*gqlerrors.QueryError) {}
+	// Every query and mutation gets its own loaders, shared by all its fields.
+	return resolvers.OperationContext(ctx), func(errs []*gqlerrors.QueryError) {}
 }
 
 func (t graphqlTracer) TraceField(ctx context.Context, label, typeName, fieldName string, trivial bool, args map[string]interface{}) (context.Context, trace.TraceFieldFinishFunc) {
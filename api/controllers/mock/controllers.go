@@ -0,0 +1,93 @@
+package mock
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/jerbob92/hoppscotch-backend/api/controllers/graphql/context"
+	"github.com/jerbob92/hoppscotch-backend/api/controllers/graphql/resolvers"
+	"github.com/jerbob92/hoppscotch-backend/helpers/mock"
+	"github.com/jerbob92/hoppscotch-backend/helpers/responses"
+	"github.com/jerbob92/hoppscotch-backend/models"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+func AttachControllers(r *gin.RouterGroup) error {
+	r.Any("/:slug", serveMock)
+	r.Any("/:slug/*path", serveMock)
+	return nil
+}
+
+// serveMock answers the request with an example response of the stored
+// request that matches it. Mock servers don't need authentication, the slug
+// is the secret.
+func serveMock(c *gin.Context) {
+	db := context.GetContext(c).GetDB()
+
+	mockServer := &models.MockServer{}
+	err := db.Model(&models.MockServer{}).Where("slug = ? AND enabled = ?", c.Param("slug"), true).First(mockServer).Error
+	if err != nil && err == gorm.ErrRecordNotFound {
+		responses.JSONAbort(c, http.StatusNotFound, responses.RequestError{
+			Code:    responses.NotFound,
+			Message: "this mock server does not exist",
+		})
+		return
+	}
+	if err != nil {
+		responses.JSONAbort(c, http.StatusInternalServerError, responses.RequestInternalError{
+			Message: "could not load the mock server",
+		})
+		return
+	}
+
+	routes, err := resolvers.LoadMockRoutes(db, mockServer)
+	if err != nil {
+		responses.JSONAbort(c, http.StatusInternalServerError, responses.RequestInternalError{
+			Message: "could not load the requests of the mock server",
+		})
+		return
+	}
+
+	path := "/" + strings.TrimPrefix(c.Param("path"), "/")
+	route := mock.Find(routes, c.Request, path, mockServer.MatchHeaders)
+	if route == nil {
+		responses.JSONAbort(c, http.StatusNotFound, responses.RequestError{
+			Code:    responses.NotFound,
+			Message: "no request matches " + c.Request.Method + " " + path,
+		})
+		return
+	}
+
+	example := route.Example(c.Request)
+	if example == nil {
+		responses.JSONAbort(c, http.StatusNotFound, responses.RequestError{
+			Code:    responses.NotFound,
+			Message: "request " + route.Name + " has no matching example response",
+		})
+		return
+	}
+
+	if mockServer.Delay > 0 {
+		select {
+		case <-time.After(time.Duration(mockServer.Delay) * time.Millisecond):
+		case <-c.Request.Context().Done():
+			c.Abort()
+			return
+		}
+	}
+
+	for _, header := range example.Headers {
+		// The length is set for the body that is written.
+		if strings.EqualFold(header.Key, "Content-Length") {
+			continue
+		}
+		c.Writer.Header().Add(header.Key, header.Value)
+	}
+	c.Status(example.Status)
+	if c.Request.Method != http.MethodHead {
+		c.Writer.WriteString(example.Body)
+	}
+}
@@ -0,0 +1,82 @@
+package proxy
+
+import (
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/jerbob92/hoppscotch-backend/api/controllers/graphql/context"
+	"github.com/jerbob92/hoppscotch-backend/config"
+	"github.com/jerbob92/hoppscotch-backend/helpers/executor"
+	"github.com/jerbob92/hoppscotch-backend/helpers/hoppscotch"
+	"github.com/jerbob92/hoppscotch-backend/helpers/responses"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxRequestSize is the maximum size of the request document.
+const maxRequestSize = 10 << 20
+
+func AttachControllers(r *gin.RouterGroup) error {
+	options, err := config.ExecutorOptions()
+	if err != nil {
+		return err
+	}
+
+	r.POST("", executeRequest(options))
+	return nil
+}
+
+// executeRequest sends the Hoppscotch REST request in the body and returns
+// the response, for targets the browser can't reach because of CORS.
+func executeRequest(options executor.Options) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		reqC := context.GetContext(c)
+		_, err := reqC.GetUser(c.Request.Context())
+		if err != nil {
+			responses.JSONAbort(c, http.StatusUnauthorized, responses.RequestError{
+				Code:    responses.Unauthorized,
+				Message: "you need to be logged in to use the proxy",
+			})
+			return
+		}
+
+		document, err := io.ReadAll(io.LimitReader(c.Request.Body, maxRequestSize))
+		if err != nil {
+			responses.JSONAbort(c, http.StatusBadRequest, responses.RequestError{
+				Code:    responses.InputValidationError,
+				Message: "could not read the request",
+			})
+			return
+		}
+
+		request, err := hoppscotch.ParseRESTRequest(string(document))
+		if err != nil {
+			responses.JSONAbort(c, http.StatusBadRequest, responses.RequestError{
+				Code:    responses.InputValidationError,
+				Message: "the body must be a Hoppscotch REST request: " + err.Error(),
+			})
+			return
+		}
+
+		response, err := executor.Execute(c.Request.Context(), hoppscotch.Prepare(request, nil), options)
+		if err != nil {
+			var targetErr *executor.TargetNotAllowedError
+			if errors.As(err, &targetErr) {
+				responses.JSONAbort(c, http.StatusForbidden, responses.RequestError{
+					Code:    responses.Forbidden,
+					Message: targetErr.Error(),
+				})
+				return
+			}
+
+			responses.JSONAbort(c, http.StatusBadGateway, responses.RequestError{
+				Code:    responses.UpstreamError,
+				Message: err.Error(),
+			})
+			return
+		}
+
+		responses.JSON(c, http.StatusOK, response)
+	}
+}
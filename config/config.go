@@ -15,6 +15,27 @@ func LoadConfig() error {
 	if configPath != "" {
 		viper.AddConfigPath(configPath)
 	}
+	viper.SetDefault("api.pagination.defaultPageSize", 20)
+	viper.SetDefault("api.pagination.maxPageSize", 100)
+	viper.SetDefault("api.revisions.maxPerRequest", 50)
+	viper.SetDefault("api.locks.ttl", 60)
+	viper.SetDefault("api.locks.maxTTL", 600)
+	viper.SetDefault("api.proxy.enabled", false)
+	viper.SetDefault("api.proxy.timeout", 30)
+	viper.SetDefault("api.proxy.maxResponseSize", 10485760)
+	viper.SetDefault("api.proxy.allow", []string{})
+	viper.SetDefault("api.proxy.deny", []string{"localhost", "127.0.0.0/8", "::1/128", "::/128", "0.0.0.0/8", "10.0.0.0/8", "172.16.0.0/12", "192.168.0.0/16", "169.254.0.0/16", "100.64.0.0/10", "224.0.0.0/4", "fc00::/7", "fe80::/10", "ff00::/8", "64:ff9b::/96", "64:ff9b:1::/48"})
+	viper.SetDefault("api.history.maxPerUser", 100)
+	viper.SetDefault("api.monitors.enabled", true)
+	viper.SetDefault("api.monitors.pollInterval", 30)
+	viper.SetDefault("api.monitors.concurrency", 4)
+	viper.SetDefault("api.monitors.maxRunsPerMonitor", 1000)
+	viper.SetDefault("api.mock.enabled", true)
+	viper.SetDefault("api.mock.port", "")
+	viper.SetDefault("api.mock.maxDelay", 30000)
+	viper.SetDefault("mailTemplates.monitorFailing.subject", "Monitor {{.MonitorName}} of {{.TeamName}} is failing")
+	viper.SetDefault("mailTemplates.monitorFailing.body", "<html><body>The last run of monitor {{.MonitorName}} of {{.TeamName}} failed{{if .Error}}: {{.Error}}{{else}}, {{.Failed}} of {{.Total}} requests failed{{end}}.</body></html>")
+
 	if err := viper.ReadInConfig(); err != nil {
 		return err
 	}
@@ -0,0 +1,24 @@
+package config
+
+import (
+	"time"
+
+	"github.com/jerbob92/hoppscotch-backend/helpers/executor"
+
+	"github.com/spf13/viper"
+)
+
+// ExecutorOptions returns the options for requests that the backend sends
+// itself, for the proxy and collection runs.
+func ExecutorOptions() (executor.Options, error) {
+	policy, err := executor.NewPolicy(viper.GetStringSlice("api.proxy.allow"), viper.GetStringSlice("api.proxy.deny"))
+	if err != nil {
+		return executor.Options{}, err
+	}
+
+	return executor.Options{
+		Policy:          policy,
+		Timeout:         time.Duration(viper.GetInt("api.proxy.timeout")) * time.Second,
+		MaxResponseSize: viper.GetInt64("api.proxy.maxResponseSize"),
+	}, nil
+}
@@ -84,6 +84,15 @@ func (dsn *DatabaseDSN) GetDialector() (gorm.Dialector, error) {
 	}
 }
 
+// Driver returns the configured database driver. Old configs without a
+// driver but with an address use MySQL.
+func Driver() string {
+	if viper.GetString("database.address") != "" && viper.GetString("database.driver") == "" {
+		return "mysql"
+	}
+	return viper.GetString("database.driver")
+}
+
 func ConnectDB() error {
 	var connectionData = &DatabaseDSN{
 		driver:            viper.GetString("database.driver"),
@@ -0,0 +1,72 @@
+package codegen
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"path"
+	"strings"
+
+	"github.com/jerbob92/hoppscotch-backend/helpers/curl"
+	"github.com/jerbob92/hoppscotch-backend/helpers/hoppscotch"
+)
+
+// Languages that code can be generated for, the values are the names in the
+// GraphQL schema.
+const (
+	LanguageCurl       = "CURL"
+	LanguageGo         = "GO"
+	LanguagePython     = "PYTHON"
+	LanguageJavaScript = "JAVASCRIPT"
+	LanguageJava       = "JAVA"
+	LanguagePowerShell = "POWERSHELL"
+)
+
+var renderers = map[string]func(request *hoppscotch.PreparedRequest) string{
+	LanguageCurl:       curl.Render,
+	LanguageGo:         renderGo,
+	LanguagePython:     renderPython,
+	LanguageJavaScript: renderJavaScript,
+	LanguageJava:       renderJava,
+	LanguagePowerShell: renderPowerShell,
+}
+
+// Render renders a prepared request as code in the given language.
+func Render(language string, request *hoppscotch.PreparedRequest) (string, error) {
+	renderer, ok := renderers[language]
+	if !ok {
+		return "", errors.New("code generation is not supported for " + language)
+	}
+
+	return renderer(request), nil
+}
+
+// hasBody returns whether the request has a body that is not multipart.
+func hasBody(request *hoppscotch.PreparedRequest) bool {
+	return request.ContentType != hoppscotch.ContentTypeMultipart && request.Body != nil && *request.Body != ""
+}
+
+// filePath returns the path of a file field, the file itself is not stored,
+// so there is only a placeholder.
+func filePath(field hoppscotch.FormDataKeyValue) string {
+	if field.Value == "" {
+		return "/path/to/file"
+	}
+	return field.Value
+}
+
+func fileName(field hoppscotch.FormDataKeyValue) string {
+	return path.Base(filePath(field))
+}
+
+// quote returns the input as a double quoted string literal, which is valid
+// in JSON, Python, JavaScript and Java.
+func quote(input string) string {
+	buffer := &bytes.Buffer{}
+	encoder := json.NewEncoder(buffer)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(input); err != nil {
+		return `""`
+	}
+	return strings.TrimSuffix(buffer.String(), "\n")
+}
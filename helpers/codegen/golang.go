@@ -0,0 +1,99 @@
+package codegen
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/jerbob92/hoppscotch-backend/helpers/hoppscotch"
+)
+
+// renderGo renders the request as a Go program using net/http.
+func renderGo(request *hoppscotch.PreparedRequest) string {
+	multipart := request.ContentType == hoppscotch.ContentTypeMultipart
+	hasFiles := false
+	for _, field := range request.FormData {
+		if field.IsFile {
+			hasFiles = true
+		}
+	}
+
+	imports := []string{"fmt", "io", "net/http"}
+	if multipart {
+		imports = append(imports, "bytes", "mime/multipart")
+		if hasFiles {
+			imports = append(imports, "os")
+		}
+	} else if hasBody(request) {
+		imports = append(imports, "strings")
+	}
+
+	output := &strings.Builder{}
+	output.WriteString("package main\n\nimport (\n")
+	for _, name := range sortedImports(imports) {
+		output.WriteString("\t" + strconv.Quote(name) + "\n")
+	}
+	output.WriteString(")\n\nfunc main() {\n")
+
+	body := "nil"
+	if multipart {
+		body = "body"
+		output.WriteString("\tbody := &bytes.Buffer{}\n")
+		output.WriteString("\twriter := multipart.NewWriter(body)\n")
+		for _, field := range request.FormData {
+			if field.IsFile {
+				// Every file gets its own block, so the variables can be
+				// declared again.
+				output.WriteString("\t{\n")
+				output.WriteString("\t\tfile, err := os.Open(" + strconv.Quote(filePath(field)) + ")\n")
+				output.WriteString("\t\tif err != nil {\n\t\t\tpanic(err)\n\t\t}\n")
+				output.WriteString("\t\tpart, err := writer.CreateFormFile(" + strconv.Quote(field.Key) + ", " + strconv.Quote(fileName(field)) + ")\n")
+				output.WriteString("\t\tif err != nil {\n\t\t\tpanic(err)\n\t\t}\n")
+				output.WriteString("\t\tif _, err := io.Copy(part, file); err != nil {\n\t\t\tpanic(err)\n\t\t}\n")
+				output.WriteString("\t\tfile.Close()\n")
+				output.WriteString("\t}\n")
+			} else {
+				output.WriteString("\tif err := writer.WriteField(" + strconv.Quote(field.Key) + ", " + strconv.Quote(field.Value) + "); err != nil {\n\t\tpanic(err)\n\t}\n")
+			}
+		}
+		output.WriteString("\tif err := writer.Close(); err != nil {\n\t\tpanic(err)\n\t}\n\n")
+	} else if hasBody(request) {
+		body = "body"
+		output.WriteString("\tbody := strings.NewReader(" + strconv.Quote(*request.Body) + ")\n\n")
+	}
+
+	output.WriteString("\treq, err := http.NewRequest(" + strconv.Quote(request.Method) + ", " + strconv.Quote(request.URL) + ", " + body + ")\n")
+	output.WriteString("\tif err != nil {\n\t\tpanic(err)\n\t}\n")
+	for _, header := range request.Headers {
+		// The Host header is ignored by net/http, it has its own field.
+		if strings.EqualFold(header.Key, "Host") {
+			output.WriteString("\treq.Host = " + strconv.Quote(header.Value) + "\n")
+			continue
+		}
+		output.WriteString("\treq.Header.Add(" + strconv.Quote(header.Key) + ", " + strconv.Quote(header.Value) + ")\n")
+	}
+	if multipart {
+		output.WriteString("\treq.Header.Set(\"Content-Type\", writer.FormDataContentType())\n")
+	}
+
+	output.WriteString("\n\tres, err := http.DefaultClient.Do(req)\n")
+	output.WriteString("\tif err != nil {\n\t\tpanic(err)\n\t}\n")
+	output.WriteString("\tdefer res.Body.Close()\n\n")
+	output.WriteString("\tresBody, err := io.ReadAll(res.Body)\n")
+	output.WriteString("\tif err != nil {\n\t\tpanic(err)\n\t}\n\n")
+	output.WriteString("\tfmt.Println(res.Status)\n")
+	output.WriteString("\tfmt.Println(string(resBody))\n")
+	output.WriteString("}\n")
+
+	return output.String()
+}
+
+// sortedImports sorts the imports the way gofmt would.
+func sortedImports(imports []string) []string {
+	sorted := append([]string{}, imports...)
+	for i := 1; i < len(sorted); i++ {
+		for j := i; j > 0 && sorted[j] < sorted[j-1]; j-- {
+			sorted[j], sorted[j-1] = sorted[j-1], sorted[j]
+		}
+	}
+	return sorted
+}
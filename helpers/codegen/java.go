@@ -0,0 +1,83 @@
+package codegen
+
+import (
+	"strings"
+
+	"github.com/jerbob92/hoppscotch-backend/helpers/hoppscotch"
+)
+
+// javaRestrictedHeaders are set by the HttpClient itself, setting them
+// throws an IllegalArgumentException.
+var javaRestrictedHeaders = map[string]bool{
+	"connection":        true,
+	"content-length":    true,
+	"expect":            true,
+	"host":              true,
+	"upgrade":           true,
+	"transfer-encoding": true,
+}
+
+// javaMultipartBoundary is the boundary of multipart bodies, the HttpClient
+// has no multipart support so the body is built by hand.
+const javaMultipartBoundary = "HoppscotchFormBoundary"
+
+// renderJava renders the request as Java code using java.net.http.HttpClient.
+func renderJava(request *hoppscotch.PreparedRequest) string {
+	multipart := request.ContentType == hoppscotch.ContentTypeMultipart
+
+	imports := []string{
+		"java.net.URI",
+		"java.net.http.HttpClient",
+		"java.net.http.HttpRequest",
+		"java.net.http.HttpResponse",
+	}
+	if multipart {
+		imports = append(imports, "java.nio.charset.StandardCharsets", "java.nio.file.Files", "java.nio.file.Path", "java.util.ArrayList", "java.util.List")
+	}
+
+	output := &strings.Builder{}
+	for _, name := range imports {
+		output.WriteString("import " + name + ";\n")
+	}
+	output.WriteString("\n")
+
+	bodyPublisher := "HttpRequest.BodyPublishers.noBody()"
+	if multipart {
+		bodyPublisher = "HttpRequest.BodyPublishers.ofByteArrays(body)"
+		output.WriteString("List<byte[]> body = new ArrayList<>();\n")
+		for _, field := range request.FormData {
+			if field.IsFile {
+				partHeader := "--" + javaMultipartBoundary + "\r\nContent-Disposition: form-data; name=\"" + field.Key + "\"; filename=\"" + fileName(field) + "\"\r\n\r\n"
+				output.WriteString("body.add(" + quote(partHeader) + ".getBytes(StandardCharsets.UTF_8));\n")
+				output.WriteString("body.add(Files.readAllBytes(Path.of(" + quote(filePath(field)) + ")));\n")
+				output.WriteString("body.add(\"\\r\\n\".getBytes(StandardCharsets.UTF_8));\n")
+			} else {
+				part := "--" + javaMultipartBoundary + "\r\nContent-Disposition: form-data; name=\"" + field.Key + "\"\r\n\r\n" + field.Value + "\r\n"
+				output.WriteString("body.add(" + quote(part) + ".getBytes(StandardCharsets.UTF_8));\n")
+			}
+		}
+		output.WriteString("body.add(" + quote("--"+javaMultipartBoundary+"--\r\n") + ".getBytes(StandardCharsets.UTF_8));\n\n")
+	} else if hasBody(request) {
+		bodyPublisher = "HttpRequest.BodyPublishers.ofString(" + quote(*request.Body) + ")"
+	}
+
+	output.WriteString("HttpClient client = HttpClient.newHttpClient();\n")
+	output.WriteString("HttpRequest request = HttpRequest.newBuilder()\n")
+	output.WriteString("    .uri(URI.create(" + quote(request.URL) + "))\n")
+	for _, header := range request.Headers {
+		if javaRestrictedHeaders[strings.ToLower(header.Key)] {
+			continue
+		}
+		output.WriteString("    .header(" + quote(header.Key) + ", " + quote(header.Value) + ")\n")
+	}
+	if multipart {
+		output.WriteString("    .header(\"Content-Type\", " + quote("multipart/form-data; boundary="+javaMultipartBoundary) + ")\n")
+	}
+	output.WriteString("    .method(" + quote(request.Method) + ", " + bodyPublisher + ")\n")
+	output.WriteString("    .build();\n\n")
+	output.WriteString("HttpResponse<String> response = client.send(request, HttpResponse.BodyHandlers.ofString());\n\n")
+	output.WriteString("System.out.println(response.statusCode());\n")
+	output.WriteString("System.out.println(response.body());\n")
+
+	return output.String()
+}
@@ -0,0 +1,47 @@
+package codegen
+
+import (
+	"strings"
+
+	"github.com/jerbob92/hoppscotch-backend/helpers/hoppscotch"
+)
+
+// renderJavaScript renders the request as JavaScript using fetch.
+func renderJavaScript(request *hoppscotch.PreparedRequest) string {
+	output := &strings.Builder{}
+
+	body := ""
+	if request.ContentType == hoppscotch.ContentTypeMultipart {
+		body = "body"
+		output.WriteString("const body = new FormData();\n")
+		for _, field := range request.FormData {
+			if field.IsFile {
+				output.WriteString("// Replace the empty file with the contents of " + filePath(field) + ".\n")
+				output.WriteString("body.append(" + quote(field.Key) + ", new File([], " + quote(fileName(field)) + "));\n")
+			} else {
+				output.WriteString("body.append(" + quote(field.Key) + ", " + quote(field.Value) + ");\n")
+			}
+		}
+		output.WriteString("\n")
+	} else if hasBody(request) {
+		body = quote(*request.Body)
+	}
+
+	output.WriteString("const response = await fetch(" + quote(request.URL) + ", {\n")
+	output.WriteString("  method: " + quote(request.Method) + ",\n")
+	if len(request.Headers) > 0 {
+		output.WriteString("  headers: {\n")
+		for _, header := range request.Headers {
+			output.WriteString("    " + quote(header.Key) + ": " + quote(header.Value) + ",\n")
+		}
+		output.WriteString("  },\n")
+	}
+	if body != "" {
+		output.WriteString("  body: " + body + ",\n")
+	}
+	output.WriteString("});\n\n")
+	output.WriteString("console.log(response.status);\n")
+	output.WriteString("console.log(await response.text());\n")
+
+	return output.String()
+}
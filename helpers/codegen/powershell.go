@@ -0,0 +1,101 @@
+package codegen
+
+import (
+	"strings"
+
+	"github.com/jerbob92/hoppscotch-backend/helpers/hoppscotch"
+)
+
+// powerShellMethods are the methods Invoke-WebRequest knows, other methods
+// have to be passed as a custom method.
+var powerShellMethods = map[string]bool{
+	"GET":     true,
+	"HEAD":    true,
+	"POST":    true,
+	"PUT":     true,
+	"DELETE":  true,
+	"TRACE":   true,
+	"OPTIONS": true,
+	"MERGE":   true,
+	"PATCH":   true,
+}
+
+// powerShellQuote returns the input as a single quoted PowerShell string, in
+// which nothing is expanded. PowerShell also treats the typographic single
+// quotes as quotes, every quote is escaped by doubling it.
+func powerShellQuote(input string) string {
+	output := &strings.Builder{}
+	output.WriteRune('\'')
+	for _, r := range input {
+		switch r {
+		case '\'', '\u2018', '\u2019', '\u201a', '\u201b':
+			output.WriteRune(r)
+		}
+		output.WriteRune(r)
+	}
+	output.WriteRune('\'')
+	return output.String()
+}
+
+// renderPowerShell renders the request as a PowerShell script using
+// Invoke-WebRequest.
+func renderPowerShell(request *hoppscotch.PreparedRequest) string {
+	output := &strings.Builder{}
+	arguments := []string{"-Uri " + powerShellQuote(request.URL)}
+
+	if powerShellMethods[request.Method] {
+		arguments = append(arguments, "-Method "+powerShellQuote(request.Method))
+	} else {
+		arguments = append(arguments, "-CustomMethod "+powerShellQuote(request.Method))
+	}
+
+	// The content type can't be set as a header.
+	contentType := ""
+	headers := []hoppscotch.KeyValue{}
+	for _, header := range request.Headers {
+		if strings.EqualFold(header.Key, "Content-Type") {
+			contentType = header.Value
+			continue
+		}
+		headers = append(headers, header)
+	}
+
+	if len(headers) > 0 {
+		output.WriteString("$headers = @{\n")
+		for _, header := range headers {
+			output.WriteString("    " + powerShellQuote(header.Key) + " = " + powerShellQuote(header.Value) + "\n")
+		}
+		output.WriteString("}\n")
+		arguments = append(arguments, "-Headers $headers")
+	}
+
+	if request.ContentType == hoppscotch.ContentTypeMultipart {
+		output.WriteString("$form = @{\n")
+		for _, field := range request.FormData {
+			if field.IsFile {
+				output.WriteString("    " + powerShellQuote(field.Key) + " = Get-Item -Path " + powerShellQuote(filePath(field)) + "\n")
+			} else {
+				output.WriteString("    " + powerShellQuote(field.Key) + " = " + powerShellQuote(field.Value) + "\n")
+			}
+		}
+		output.WriteString("}\n")
+		arguments = append(arguments, "-Form $form")
+	} else {
+		if contentType != "" {
+			arguments = append(arguments, "-ContentType "+powerShellQuote(contentType))
+		}
+		if hasBody(request) {
+			output.WriteString("$body = " + powerShellQuote(*request.Body) + "\n")
+			arguments = append(arguments, "-Body $body")
+		}
+	}
+
+	if output.Len() > 0 {
+		output.WriteString("\n")
+	}
+	output.WriteString("$response = Invoke-WebRequest " + strings.Join(arguments, " ") + "\n\n")
+	output.WriteString("$response.StatusCode\n")
+	output.WriteString("$response.Content\n")
+
+	return output.String()
+}
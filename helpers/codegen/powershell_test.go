@@ -0,0 +1,50 @@
+package codegen
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/jerbob92/hoppscotch-backend/helpers/hoppscotch"
+)
+
+func TestPowerShellQuote(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{input: "", want: "''"},
+		{input: "plain $HOME `n", want: "'plain $HOME `n'"},
+		{input: "O'Brien", want: "'O''Brien'"},
+		{input: "’; calc; ’", want: "'’’; calc; ’’'"},
+		{input: "‘a’ ‚b‛", want: "'‘‘a’’ ‚‚b‛‛'"},
+	}
+
+	for _, test := range tests {
+		if got := powerShellQuote(test.input); got != test.want {
+			t.Errorf("%q: got %q, want %q", test.input, got, test.want)
+		}
+	}
+}
+
+func TestRenderPowerShellQuotesEverything(t *testing.T) {
+	body := "’; calc; ’"
+	code, err := Render(LanguagePowerShell, &hoppscotch.PreparedRequest{
+		Method:  "PURGE",
+		URL:     "https://example.com/",
+		Headers: []hoppscotch.KeyValue{{Key: "X-Name", Value: "‘; calc; ‘"}},
+		Body:    &body,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, want := range []string{
+		"'X-Name' = '‘‘; calc; ‘‘'",
+		"$body = '’’; calc; ’’'",
+		"-CustomMethod 'PURGE'",
+	} {
+		if !strings.Contains(code, want) {
+			t.Errorf("expected %q in\n%s", want, code)
+		}
+	}
+}
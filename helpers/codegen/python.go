@@ -0,0 +1,54 @@
+package codegen
+
+import (
+	"strings"
+
+	"github.com/jerbob92/hoppscotch-backend/helpers/hoppscotch"
+)
+
+// renderPython renders the request as a Python script using requests.
+func renderPython(request *hoppscotch.PreparedRequest) string {
+	output := &strings.Builder{}
+	output.WriteString("import requests\n\n")
+	output.WriteString("url = " + quote(request.URL) + "\n")
+
+	arguments := []string{quote(request.Method), "url"}
+
+	if len(request.Headers) > 0 {
+		output.WriteString("headers = {\n")
+		for _, header := range request.Headers {
+			output.WriteString("    " + quote(header.Key) + ": " + quote(header.Value) + ",\n")
+		}
+		output.WriteString("}\n")
+		arguments = append(arguments, "headers=headers")
+	}
+
+	if request.ContentType == hoppscotch.ContentTypeMultipart {
+		data := []string{}
+		files := []string{}
+		for _, field := range request.FormData {
+			if field.IsFile {
+				files = append(files, "    ("+quote(field.Key)+", ("+quote(fileName(field))+", open("+quote(filePath(field))+", \"rb\"))),\n")
+			} else {
+				data = append(data, "    ("+quote(field.Key)+", "+quote(field.Value)+"),\n")
+			}
+		}
+		if len(data) > 0 {
+			output.WriteString("data = [\n" + strings.Join(data, "") + "]\n")
+			arguments = append(arguments, "data=data")
+		}
+		if len(files) > 0 {
+			output.WriteString("files = [\n" + strings.Join(files, "") + "]\n")
+			arguments = append(arguments, "files=files")
+		}
+	} else if hasBody(request) {
+		output.WriteString("data = " + quote(*request.Body) + "\n")
+		arguments = append(arguments, "data=data.encode(\"utf-8\")")
+	}
+
+	output.WriteString("\nresponse = requests.request(" + strings.Join(arguments, ", ") + ")\n\n")
+	output.WriteString("print(response.status_code)\n")
+	output.WriteString("print(response.text)\n")
+
+	return output.String()
+}
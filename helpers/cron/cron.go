@@ -0,0 +1,190 @@
+// Package cron parses the standard five field cron expressions: minute, hour,
+// day of month, month and day of week.
+package cron
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Schedule is a parsed cron expression.
+type Schedule struct {
+	minutes  uint64
+	hours    uint64
+	days     uint64
+	months   uint64
+	weekdays uint64
+
+	// When both the day of month and the day of week are restricted, a day
+	// matches when either matches, like in the classic cron.
+	daysRestricted     bool
+	weekdaysRestricted bool
+}
+
+type fieldRange struct {
+	name  string
+	min   int
+	max   int
+	names map[string]int
+}
+
+var fieldRanges = []fieldRange{
+	{name: "minute", min: 0, max: 59},
+	{name: "hour", min: 0, max: 23},
+	{name: "day of month", min: 1, max: 31},
+	{name: "month", min: 1, max: 12, names: map[string]int{
+		"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+		"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+	}},
+	{name: "day of week", min: 0, max: 7, names: map[string]int{
+		"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+	}},
+}
+
+var macros = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// Parse parses a five field cron expression or one of the macros @yearly,
+// @annually, @monthly, @weekly, @daily, @midnight and @hourly. Fields support
+// *, values, ranges (1-5), steps (*/15, 1-30/5), lists (1,15) and the English
+// abbreviations of months and days.
+func Parse(expression string) (*Schedule, error) {
+	expression = strings.TrimSpace(expression)
+	if macro, ok := macros[strings.ToLower(expression)]; ok {
+		expression = macro
+	}
+
+	fields := strings.Fields(expression)
+	if len(fields) != 5 {
+		return nil, errors.New("a cron expression needs 5 fields: minute, hour, day of month, month and day of week")
+	}
+
+	values := make([]uint64, 5)
+	for i, field := range fields {
+		bits, err := parseField(strings.ToLower(field), fieldRanges[i])
+		if err != nil {
+			return nil, err
+		}
+		values[i] = bits
+	}
+
+	// 7 is an alias of sunday.
+	if values[4]&(1<<7) != 0 {
+		values[4] = values[4]&^(1<<7) | 1
+	}
+
+	return &Schedule{
+		minutes:            values[0],
+		hours:              values[1],
+		days:               values[2],
+		months:             values[3],
+		weekdays:           values[4],
+		daysRestricted:     fields[2] != "*",
+		weekdaysRestricted: fields[4] != "*",
+	}, nil
+}
+
+func parseField(field string, r fieldRange) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(field, ",") {
+		step := 1
+		if index := strings.Index(part, "/"); index >= 0 {
+			var err error
+			step, err = strconv.Atoi(part[index+1:])
+			if err != nil || step < 1 {
+				return 0, errors.New("invalid step in " + r.name + " field: " + part)
+			}
+			part = part[:index]
+		}
+
+		start, end := r.min, r.max
+		if part != "*" {
+			var err error
+			bounds := strings.SplitN(part, "-", 2)
+			start, err = parseValue(bounds[0], r)
+			if err != nil {
+				return 0, err
+			}
+			end = start
+			if len(bounds) == 2 {
+				end, err = parseValue(bounds[1], r)
+				if err != nil {
+					return 0, err
+				}
+			} else if step > 1 {
+				// A value with a step runs from the value to the maximum.
+				end = r.max
+			}
+			if end < start {
+				return 0, errors.New("invalid range in " + r.name + " field: " + part)
+			}
+		}
+
+		for value := start; value <= end; value += step {
+			bits |= 1 << uint(value)
+		}
+	}
+
+	return bits, nil
+}
+
+func parseValue(value string, r fieldRange) (int, error) {
+	if number, ok := r.names[value]; ok {
+		return number, nil
+	}
+
+	number, err := strconv.Atoi(value)
+	if err != nil || number < r.min || number > r.max {
+		return 0, errors.New("invalid value in " + r.name + " field: " + value + ", it must be between " + strconv.Itoa(r.min) + " and " + strconv.Itoa(r.max))
+	}
+
+	return number, nil
+}
+
+// Next returns the first time after t that matches the schedule, in the
+// location of t. It returns the zero time when nothing matches within five
+// years, for example for the 30th of February.
+func (s *Schedule) Next(t time.Time) time.Time {
+	t = t.Truncate(time.Minute).Add(time.Minute)
+	limit := t.AddDate(5, 0, 0)
+
+	for t.Before(limit) {
+		if s.months&(1<<uint(t.Month())) == 0 {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+		if !s.dayMatches(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+		if s.hours&(1<<uint(t.Hour())) == 0 {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, t.Location())
+			continue
+		}
+		if s.minutes&(1<<uint(t.Minute())) == 0 {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t
+	}
+
+	return time.Time{}
+}
+
+func (s *Schedule) dayMatches(t time.Time) bool {
+	day := s.days&(1<<uint(t.Day())) != 0
+	weekday := s.weekdays&(1<<uint(t.Weekday())) != 0
+	if s.daysRestricted && s.weekdaysRestricted {
+		return day || weekday
+	}
+	return day && weekday
+}
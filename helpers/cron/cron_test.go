@@ -0,0 +1,114 @@
+package cron
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseErrors(t *testing.T) {
+	for _, expression := range []string{
+		"",
+		"* * * *",
+		"* * * * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * 32 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"5-1 * * * *",
+		"*/0 * * * *",
+		"*/x * * * *",
+		"1,,2 * * * *",
+		"-5 * * * *",
+		"* * * foo *",
+		"@every 5m",
+	} {
+		if _, err := Parse(expression); err == nil {
+			t.Errorf("%q: expected an error", expression)
+		}
+	}
+}
+
+func TestNext(t *testing.T) {
+	start := time.Date(2026, 10, 19, 11, 22, 33, 0, time.UTC) // A monday.
+
+	tests := []struct {
+		expression string
+		want       time.Time
+	}{
+		{expression: "* * * * *", want: time.Date(2026, 10, 19, 11, 23, 0, 0, time.UTC)},
+		{expression: "*/15 * * * *", want: time.Date(2026, 10, 19, 11, 30, 0, 0, time.UTC)},
+		{expression: "22 11 * * *", want: time.Date(2026, 10, 20, 11, 22, 0, 0, time.UTC)},
+		{expression: "0 9-17/4 * * *", want: time.Date(2026, 10, 19, 13, 0, 0, 0, time.UTC)},
+		{expression: "5,50 * * * *", want: time.Date(2026, 10, 19, 11, 50, 0, 0, time.UTC)},
+		{expression: "10/20 * * * *", want: time.Date(2026, 10, 19, 11, 30, 0, 0, time.UTC)},
+		{expression: "0 0 * * sun", want: time.Date(2026, 10, 25, 0, 0, 0, 0, time.UTC)},
+		{expression: "0 0 * * 7", want: time.Date(2026, 10, 25, 0, 0, 0, 0, time.UTC)},
+		{expression: "0 0 * * MON-FRI", want: time.Date(2026, 10, 20, 0, 0, 0, 0, time.UTC)},
+		{expression: "0 0 1 jan *", want: time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{expression: "0 0 29 2 *", want: time.Date(2028, 2, 29, 0, 0, 0, 0, time.UTC)},
+		{expression: "0 0 31 * *", want: time.Date(2026, 10, 31, 0, 0, 0, 0, time.UTC)},
+		// Either the day of month or the day of week matches.
+		{expression: "0 0 1 * fri", want: time.Date(2026, 10, 23, 0, 0, 0, 0, time.UTC)},
+		{expression: "@hourly", want: time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)},
+		{expression: "@daily", want: time.Date(2026, 10, 20, 0, 0, 0, 0, time.UTC)},
+		{expression: "@weekly", want: time.Date(2026, 10, 25, 0, 0, 0, 0, time.UTC)},
+		{expression: "@monthly", want: time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC)},
+		{expression: " @YEARLY ", want: time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{expression: "0 0 30 2 *", want: time.Time{}},
+	}
+
+	for _, test := range tests {
+		schedule, err := Parse(test.expression)
+		if err != nil {
+			t.Errorf("%q: %v", test.expression, err)
+			continue
+		}
+		if got := schedule.Next(start); !got.Equal(test.want) {
+			t.Errorf("%q: got %v, want %v", test.expression, got, test.want)
+		}
+	}
+}
+
+func TestNextIsAfter(t *testing.T) {
+	schedule, err := Parse("30 * * * *")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	start := time.Date(2026, 10, 19, 11, 30, 0, 0, time.UTC)
+	if got := schedule.Next(start); !got.Equal(start.Add(time.Hour)) {
+		t.Errorf("got %v, want %v", got, start.Add(time.Hour))
+	}
+}
+
+func TestNextInLocation(t *testing.T) {
+	location, err := time.LoadLocation("Europe/Amsterdam")
+	if err != nil {
+		t.Skip("no time zone data: " + err.Error())
+	}
+
+	schedule, err := Parse("0 9 * * *")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Across the switch to summer time, 9:00 stays 9:00 local time.
+	got := schedule.Next(time.Date(2026, 3, 28, 10, 0, 0, 0, location))
+	want := time.Date(2026, 3, 29, 9, 0, 0, 0, location)
+	if !got.Equal(want) || got.Location() != location {
+		t.Errorf("got %v, want %v", got, want)
+	}
+
+	// A time that doesn't exist on the day of the switch is skipped.
+	schedule, err = Parse("30 2 * * *")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got = schedule.Next(time.Date(2026, 3, 29, 0, 0, 0, 0, location))
+	want = time.Date(2026, 3, 30, 2, 30, 0, 0, location)
+	if !got.Equal(want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
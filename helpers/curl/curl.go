@@ -0,0 +1,458 @@
+package curl
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/jerbob92/hoppscotch-backend/helpers/hoppscotch"
+)
+
+// ignoredValueFlags are flags we don't import, but that take a value that
+// should not be mistaken for the URL.
+var ignoredValueFlags = map[string]bool{
+	"-o": true, "--output": true,
+	"-m": true, "--max-time": true,
+	"--connect-timeout": true,
+	"-x":                true, "--proxy": true,
+	"--retry": true,
+	"-w":      true, "--write-out": true,
+	"--cacert": true, "-E": true, "--cert": true, "--key": true,
+	"-c": true, "--cookie-jar": true,
+	"-T": true, "--upload-file": true,
+	"--resolve":     true,
+	"--limit-rate":  true,
+	"--max-redirs":  true,
+	"--retry-delay": true, "--retry-max-time": true,
+	"-r": true, "--range": true,
+	"-D": true, "--dump-header": true,
+	"--trace": true, "--trace-ascii": true, "--stderr": true,
+	"-U": true, "--proxy-user": true, "--noproxy": true,
+	"--interface": true, "--unix-socket": true, "--connect-to": true,
+	"--capath": true, "--cert-type": true, "--key-type": true, "--pass": true, "--ciphers": true,
+	"-Y": true, "--speed-limit": true, "-y": true, "--speed-time": true,
+}
+
+// booleanFlags are flags without a value that we don't import.
+var booleanFlags = map[string]bool{
+	"-s": true, "--silent": true,
+	"-S": true, "--show-error": true,
+	"-L": true, "--location": true, "--location-trusted": true,
+	"-k": true, "--insecure": true,
+	"-v": true, "--verbose": true,
+	"-i": true, "--include": true,
+	"-f": true, "--fail": true, "--fail-with-body": true,
+	"-g": true, "--globoff": true,
+	"-N": true, "--no-buffer": true,
+	"-n": true, "--netrc": true,
+	"-O": true, "--remote-name": true,
+	"-J": true, "--remote-header-name": true,
+	"-#": true, "--progress-bar": true, "--no-progress-meter": true,
+	"-0": true, "--http1.0": true, "--http1.1": true, "--http2": true, "--http2-prior-knowledge": true, "--http3": true,
+	"-4": true, "--ipv4": true, "-6": true, "--ipv6": true,
+	"--compressed": true, "--path-as-is": true, "--no-keepalive": true, "--tcp-nodelay": true,
+	"--basic": true, "--digest": true, "--ntlm": true, "--negotiate": true, "--anyauth": true,
+	"--tlsv1.2": true, "--tlsv1.3": true, "--ssl": true, "--ssl-reqd": true,
+}
+
+// fileValueFlags are flags that take a file, these can't be imported.
+var fileValueFlags = map[string]bool{
+	"-K": true, "--config": true,
+}
+
+// shortValueFlags are the short flags that take a value, which can also be
+// attached, like -XPOST.
+var shortValueFlags = map[string]bool{
+	"-X": true,
+	"-H": true,
+	"-d": true,
+	"-u": true,
+	"-F": true,
+	"-A": true,
+	"-b": true,
+	"-e": true,
+}
+
+type parser struct {
+	method  string
+	rawURL  string
+	headers []hoppscotch.KeyValue
+	data    []string
+	form    []hoppscotch.FormDataKeyValue
+	user    *string
+	get     bool
+	head    bool
+	isJSON  bool
+	hasBody bool
+}
+
+// Parse converts a cURL command line into a request.
+func Parse(command string) (*hoppscotch.RESTRequest, error) {
+	args, err := Split(command)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(args) > 0 && args[0] == "curl" {
+		args = args[1:]
+	}
+
+	if len(args) == 0 {
+		return nil, errors.New("the cURL command is empty")
+	}
+
+	p := &parser{}
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+
+		// Attached values like -XPOST or -H'Accept: */*'.
+		if len(arg) > 2 && arg[0] == '-' && arg[1] != '-' && shortValueFlags[arg[:2]] {
+			if err := p.flag(arg[:2], arg[2:]); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		if shortValueFlags[arg] || isLongValueFlag(arg) {
+			if i+1 >= len(args) {
+				return nil, fmt.Errorf("the option %s requires a value", arg)
+			}
+			i++
+			if err := p.flag(arg, args[i]); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		if ignoredValueFlags[arg] {
+			if i+1 >= len(args) {
+				return nil, fmt.Errorf("the option %s requires a value", arg)
+			}
+			i++
+			continue
+		}
+
+		if fileValueFlags[arg] {
+			return nil, fmt.Errorf("the option %s reads a file, which can't be imported", arg)
+		}
+
+		if !strings.HasPrefix(arg, "-") || arg == "-" {
+			if p.rawURL == "" {
+				p.rawURL = arg
+			}
+			continue
+		}
+
+		if strings.HasPrefix(arg, "--") || len(arg) == 2 {
+			if err := p.booleanFlag(arg); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		// Short flags can be combined, like -sSL, and the last one can take
+		// a value, like -sX POST or -sXPOST.
+		for j := 1; j < len(arg); j++ {
+			flag := "-" + arg[j:j+1]
+			if fileValueFlags[flag] {
+				return nil, fmt.Errorf("the option %s reads a file, which can't be imported", flag)
+			}
+			if shortValueFlags[flag] || ignoredValueFlags[flag] {
+				value := arg[j+1:]
+				if value == "" {
+					if i+1 >= len(args) {
+						return nil, fmt.Errorf("the option %s requires a value", flag)
+					}
+					i++
+					value = args[i]
+				}
+				if shortValueFlags[flag] {
+					if err := p.flag(flag, value); err != nil {
+						return nil, err
+					}
+				}
+				break
+			}
+			if err := p.booleanFlag(flag); err != nil {
+				return nil, fmt.Errorf("%s in %s", err.Error(), arg)
+			}
+		}
+	}
+
+	return p.request()
+}
+
+func (p *parser) booleanFlag(flag string) error {
+	switch {
+	case flag == "-G" || flag == "--get":
+		p.get = true
+	case flag == "-I" || flag == "--head":
+		p.head = true
+	case booleanFlags[flag]:
+	default:
+		return fmt.Errorf("the option %s is not supported", flag)
+	}
+	return nil
+}
+
+func isLongValueFlag(arg string) bool {
+	switch arg {
+	case "--request", "--header", "--data", "--data-ascii", "--data-binary", "--data-raw", "--data-urlencode",
+		"--json", "--user", "--form", "--form-string", "--url", "--user-agent", "--cookie", "--referer",
+		"--oauth2-bearer":
+		return true
+	}
+	return false
+}
+
+func (p *parser) flag(flag string, value string) error {
+	switch flag {
+	case "-X", "--request":
+		p.method = strings.ToUpper(value)
+	case "-H", "--header":
+		// "Name;" is how cURL sends a header with an empty value.
+		if strings.HasSuffix(value, ";") && !strings.Contains(value, ":") {
+			p.headers = append(p.headers, hoppscotch.KeyValue{Key: strings.TrimSuffix(value, ";"), Active: true})
+			return nil
+		}
+		parts := strings.SplitN(value, ":", 2)
+		if len(parts) != 2 {
+			return fmt.Errorf("invalid header: %s", value)
+		}
+		p.headers = append(p.headers, hoppscotch.KeyValue{
+			Key:    strings.TrimSpace(parts[0]),
+			Value:  strings.TrimSpace(parts[1]),
+			Active: true,
+		})
+	case "-d", "--data", "--data-ascii", "--data-binary", "--data-raw":
+		if flag != "--data-raw" && strings.HasPrefix(value, "@") {
+			return fmt.Errorf("the option %s reads a file, which can't be imported", flag)
+		}
+		p.hasBody = true
+		p.data = append(p.data, value)
+	case "--data-urlencode":
+		// Both @file and name@file read the value from a file.
+		if index := strings.IndexAny(value, "=@"); index > -1 && value[index] == '@' {
+			return fmt.Errorf("the option %s reads a file, which can't be imported", flag)
+		}
+		p.hasBody = true
+		if index := strings.Index(value, "="); index > -1 {
+			p.data = append(p.data, value[:index+1]+url.QueryEscape(value[index+1:]))
+		} else {
+			p.data = append(p.data, url.QueryEscape(value))
+		}
+	case "--json":
+		p.hasBody = true
+		p.isJSON = true
+		p.data = append(p.data, value)
+	case "-u", "--user":
+		p.user = &value
+	case "--oauth2-bearer":
+		p.headers = append(p.headers, hoppscotch.KeyValue{Key: "Authorization", Value: "Bearer " + value, Active: true})
+	case "-F", "--form", "--form-string":
+		p.hasBody = true
+		parts := strings.SplitN(value, "=", 2)
+		field := hoppscotch.FormDataKeyValue{
+			Key:    parts[0],
+			Active: true,
+		}
+		if len(parts) == 2 {
+			field.Value = parts[1]
+		}
+		// Files can't be imported, the user has to select them again.
+		if flag != "--form-string" && (strings.HasPrefix(field.Value, "@") || strings.HasPrefix(field.Value, "<")) {
+			field.Value = ""
+			field.IsFile = true
+		}
+		p.form = append(p.form, field)
+	case "--url":
+		p.rawURL = value
+	case "-A", "--user-agent":
+		p.headers = append(p.headers, hoppscotch.KeyValue{Key: "User-Agent", Value: value, Active: true})
+	case "-b", "--cookie":
+		p.headers = append(p.headers, hoppscotch.KeyValue{Key: "Cookie", Value: value, Active: true})
+	case "-e", "--referer":
+		p.headers = append(p.headers, hoppscotch.KeyValue{Key: "Referer", Value: value, Active: true})
+	}
+	return nil
+}
+
+func (p *parser) request() (*hoppscotch.RESTRequest, error) {
+	if p.rawURL == "" {
+		return nil, errors.New("the cURL command has no URL")
+	}
+
+	method := p.method
+	if method == "" {
+		switch {
+		case p.head:
+			method = "HEAD"
+		case p.hasBody && !p.get:
+			method = "POST"
+		default:
+			method = "GET"
+		}
+	}
+
+	endpoint := p.rawURL
+	query := ""
+	if index := strings.Index(endpoint, "?"); index > -1 {
+		query = endpoint[index+1:]
+		endpoint = endpoint[:index]
+	}
+
+	// With -G the data is sent as query.
+	data := strings.Join(p.data, "&")
+	if p.get && len(p.data) > 0 {
+		if query != "" {
+			query += "&"
+		}
+		query += data
+		p.data = nil
+	}
+
+	request := hoppscotch.NewRESTRequest(endpoint, method, endpoint)
+	request.Params = parseQuery(query)
+
+	contentType := ""
+	for _, header := range p.headers {
+		if strings.EqualFold(header.Key, "Content-Type") {
+			contentType = header.Value
+			continue
+		}
+		if strings.EqualFold(header.Key, "Authorization") && p.user == nil {
+			if auth, ok := authFromHeader(header.Value); ok {
+				request.Auth = auth
+				continue
+			}
+		}
+		request.Headers = append(request.Headers, header)
+	}
+
+	if p.user != nil {
+		parts := strings.SplitN(*p.user, ":", 2)
+		request.Auth = hoppscotch.Auth{
+			AuthType:   hoppscotch.AuthTypeBasic,
+			AuthActive: true,
+			Username:   parts[0],
+		}
+		if len(parts) == 2 {
+			request.Auth.Password = parts[1]
+		}
+	}
+
+	switch {
+	case len(p.form) > 0:
+		request.SetFormDataBody(p.form)
+	case len(p.data) > 0:
+		if contentType == "" {
+			// This is what cURL sends by default.
+			contentType = hoppscotch.ContentTypeForm
+			if p.isJSON {
+				contentType = hoppscotch.ContentTypeJSON
+			}
+		}
+		contentType = hoppscotch.ContentTypeFromMimeType(contentType)
+		if contentType == hoppscotch.ContentTypeForm {
+			request.SetRawBody(contentType, hoppscotch.RawKeyValue(parseQuery(data)))
+		} else {
+			request.SetRawBody(contentType, data)
+		}
+	case contentType != "":
+		request.Headers = append(request.Headers, hoppscotch.KeyValue{Key: "Content-Type", Value: contentType, Active: true})
+	}
+
+	return request, nil
+}
+
+// parseQuery parses a query string and keeps the order of the parameters.
+func parseQuery(query string) []hoppscotch.KeyValue {
+	params := []hoppscotch.KeyValue{}
+	for _, part := range strings.Split(query, "&") {
+		if part == "" {
+			continue
+		}
+		keyValue := strings.SplitN(part, "=", 2)
+		param := hoppscotch.KeyValue{
+			Key:    unescape(keyValue[0]),
+			Active: true,
+		}
+		if len(keyValue) == 2 {
+			param.Value = unescape(keyValue[1])
+		}
+		params = append(params, param)
+	}
+	return params
+}
+
+func unescape(input string) string {
+	output, err := url.QueryUnescape(input)
+	if err != nil {
+		return input
+	}
+	return output
+}
+
+// authFromHeader converts a basic or bearer Authorization header to auth.
+func authFromHeader(value string) (hoppscotch.Auth, bool) {
+	parts := strings.SplitN(strings.TrimSpace(value), " ", 2)
+	if len(parts) != 2 {
+		return hoppscotch.Auth{}, false
+	}
+
+	switch strings.ToLower(parts[0]) {
+	case "bearer":
+		return hoppscotch.Auth{
+			AuthType:   hoppscotch.AuthTypeBearer,
+			AuthActive: true,
+			Token:      strings.TrimSpace(parts[1]),
+		}, true
+	case "basic":
+		credentials, err := base64.StdEncoding.DecodeString(strings.TrimSpace(parts[1]))
+		if err != nil {
+			return hoppscotch.Auth{}, false
+		}
+		userPassword := strings.SplitN(string(credentials), ":", 2)
+		auth := hoppscotch.Auth{
+			AuthType:   hoppscotch.AuthTypeBasic,
+			AuthActive: true,
+			Username:   userPassword[0],
+		}
+		if len(userPassword) == 2 {
+			auth.Password = userPassword[1]
+		}
+		return auth, true
+	}
+
+	return hoppscotch.Auth{}, false
+}
+
+// Render renders a prepared request as a cURL command.
+func Render(request *hoppscotch.PreparedRequest) string {
+	lines := []string{"curl --request " + Quote(request.Method) + " " + Quote(request.URL)}
+
+	for _, header := range request.Headers {
+		lines = append(lines, "--header "+Quote(header.Key+": "+header.Value))
+	}
+
+	if request.ContentType == hoppscotch.ContentTypeMultipart {
+		for _, field := range request.FormData {
+			if field.IsFile {
+				// The file itself is not stored, so there is only a placeholder.
+				fileName := field.Value
+				if fileName == "" {
+					fileName = "/path/to/file"
+				}
+				lines = append(lines, "--form "+Quote(field.Key+"=@"+fileName))
+			} else {
+				lines = append(lines, "--form-string "+Quote(field.Key+"="+field.Value))
+			}
+		}
+	} else if request.Body != nil && *request.Body != "" {
+		lines = append(lines, "--data-raw "+Quote(*request.Body))
+	}
+
+	return strings.Join(lines, " \\\n  ")
+}
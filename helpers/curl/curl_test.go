@@ -0,0 +1,219 @@
+package curl
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/jerbob92/hoppscotch-backend/helpers/hoppscotch"
+)
+
+func TestSplit(t *testing.T) {
+	tests := []struct {
+		command string
+		want    []string
+	}{
+		{command: `curl https://example.com`, want: []string{"curl", "https://example.com"}},
+		{command: `curl  -H 'A: b c'   "x y"`, want: []string{"curl", "-H", "A: b c", "x y"}},
+		{command: `a\ b 'it'\''s' "say \"hi\" \$HOME"`, want: []string{"a b", "it's", `say "hi" $HOME`}},
+		{command: "curl \\\n  -X POST \\\r\n  url", want: []string{"curl", "-X", "POST", "url"}},
+		{command: `$'a\nb' $'it\'s'`, want: []string{"a\nb", "it's"}},
+		{command: `'' ""`, want: []string{"", ""}},
+		{command: `a"b"'c'`, want: []string{"abc"}},
+	}
+
+	for _, test := range tests {
+		got, err := Split(test.command)
+		if err != nil {
+			t.Errorf("%q: %v", test.command, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%q: got %q, want %q", test.command, got, test.want)
+		}
+	}
+
+	for _, command := range []string{`'unterminated`, `"unterminated`, `$'unterminated`, `ends with \`} {
+		if _, err := Split(command); err == nil {
+			t.Errorf("%q: expected an error", command)
+		}
+	}
+}
+
+func TestQuoteRoundTrip(t *testing.T) {
+	for _, input := range []string{"", "plain", "it's", `a "b" $c`, "line\nbreak", "'; rm -rf / #", `\`} {
+		args, err := Split("echo " + Quote(input))
+		if err != nil {
+			t.Fatalf("%q: %v", input, err)
+		}
+		if len(args) != 2 || args[1] != input {
+			t.Errorf("%q: got %q", input, args)
+		}
+	}
+}
+
+func TestParse(t *testing.T) {
+	request, err := Parse(`curl -X post 'https://api.example.com/users?page=2&q=a%20b' \
+		-H 'Content-Type: application/json' -H "X-Empty;" -H 'Authorization: Bearer abc' \
+		--data-raw '{"name": "Alice"}' -o out.json`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if request.Method != "POST" || request.Endpoint != "https://api.example.com/users" {
+		t.Errorf("method = %q, endpoint = %q", request.Method, request.Endpoint)
+	}
+	wantParams := []hoppscotch.KeyValue{{Key: "page", Value: "2", Active: true}, {Key: "q", Value: "a b", Active: true}}
+	if !reflect.DeepEqual(request.Params, wantParams) {
+		t.Errorf("params = %+v", request.Params)
+	}
+	wantHeaders := []hoppscotch.KeyValue{{Key: "X-Empty", Active: true}}
+	if !reflect.DeepEqual(request.Headers, wantHeaders) {
+		t.Errorf("headers = %+v", request.Headers)
+	}
+	if request.Auth.AuthType != hoppscotch.AuthTypeBearer || request.Auth.Token != "abc" {
+		t.Errorf("auth = %+v", request.Auth)
+	}
+	if request.Body.ContentType == nil || *request.Body.ContentType != hoppscotch.ContentTypeJSON || request.Body.Raw == nil || *request.Body.Raw != `{"name": "Alice"}` {
+		t.Errorf("body = %+v", request.Body)
+	}
+}
+
+func TestParseDefaults(t *testing.T) {
+	tests := []struct {
+		command string
+		method  string
+	}{
+		{command: `curl example.com`, method: "GET"},
+		{command: `curl -d a=b example.com`, method: "POST"},
+		{command: `curl -G -d a=b example.com`, method: "GET"},
+		{command: `curl -I example.com`, method: "HEAD"},
+		{command: `curl -XPUT example.com`, method: "PUT"},
+	}
+
+	for _, test := range tests {
+		request, err := Parse(test.command)
+		if err != nil {
+			t.Errorf("%q: %v", test.command, err)
+			continue
+		}
+		if request.Method != test.method {
+			t.Errorf("%q: method = %q, want %q", test.command, request.Method, test.method)
+		}
+	}
+
+	request, err := Parse(`curl -u alice:secret -G -d 'a=1' 'example.com?b=2'`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if request.Auth.AuthType != hoppscotch.AuthTypeBasic || request.Auth.Username != "alice" || request.Auth.Password != "secret" {
+		t.Errorf("auth = %+v", request.Auth)
+	}
+	if len(request.Params) != 2 || request.Params[1].Key != "a" {
+		t.Errorf("params = %+v", request.Params)
+	}
+
+	for _, command := range []string{``, `curl`, `curl -X`, `curl -H 'no colon' example.com`} {
+		if _, err := Parse(command); err == nil {
+			t.Errorf("%q: expected an error", command)
+		}
+	}
+}
+
+func TestParseOptions(t *testing.T) {
+	tests := []struct {
+		command  string
+		endpoint string
+		method   string
+	}{
+		{command: `curl -sSL https://example.com`, endpoint: "https://example.com", method: "GET"},
+		{command: `curl -sX PUT https://example.com`, endpoint: "https://example.com", method: "PUT"},
+		{command: `curl -sXDELETE https://example.com`, endpoint: "https://example.com", method: "DELETE"},
+		{command: `curl -so out.json https://example.com`, endpoint: "https://example.com", method: "GET"},
+		{command: `curl --max-redirs 5 --compressed https://example.com`, endpoint: "https://example.com", method: "GET"},
+		{command: `curl -U proxy:secret https://example.com`, endpoint: "https://example.com", method: "GET"},
+		{command: `curl --oauth2-bearer token https://example.com`, endpoint: "https://example.com", method: "GET"},
+	}
+
+	for _, test := range tests {
+		request, err := Parse(test.command)
+		if err != nil {
+			t.Errorf("%q: %v", test.command, err)
+			continue
+		}
+		if request.Endpoint != test.endpoint || request.Method != test.method {
+			t.Errorf("%q: endpoint = %q, method = %q", test.command, request.Endpoint, request.Method)
+		}
+	}
+
+	request, err := Parse(`curl --oauth2-bearer abc https://example.com`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if request.Auth.AuthType != hoppscotch.AuthTypeBearer || request.Auth.Token != "abc" || len(request.Headers) != 0 {
+		t.Errorf("auth = %+v, headers = %+v", request.Auth, request.Headers)
+	}
+
+	for _, command := range []string{
+		`curl -K curl.conf https://example.com`,
+		`curl --config curl.conf https://example.com`,
+		`curl -sK curl.conf https://example.com`,
+		`curl --data-urlencode @body.txt https://example.com`,
+		`curl --data-urlencode name@body.txt https://example.com`,
+		`curl -d @body.json https://example.com`,
+		`curl --aws-sigv4 aws:amz https://example.com`,
+		`curl -sz yesterday https://example.com`,
+		`curl https://example.com --max-redirs`,
+	} {
+		if _, err := Parse(command); err == nil {
+			t.Errorf("%q: expected an error", command)
+		}
+	}
+
+	// Values that look like a file but are sent as they are.
+	request, err = Parse(`curl --data-urlencode 'email=a@example.com' --data-raw '@raw' https://example.com`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if request.Body.Raw == nil || *request.Body.Raw != "email: a@example.com\n@raw: " {
+		t.Errorf("body = %+v", request.Body)
+	}
+}
+
+func TestRender(t *testing.T) {
+	body := `{"it's": true}`
+	command := Render(&hoppscotch.PreparedRequest{
+		Method:      "POST",
+		URL:         "https://example.com/a?b=c",
+		Headers:     []hoppscotch.KeyValue{{Key: "X-Name", Value: "O'Brien"}, {Key: "Content-Type", Value: "application/json"}},
+		ContentType: hoppscotch.ContentTypeJSON,
+		Body:        &body,
+	})
+
+	want := "curl --request 'POST' 'https://example.com/a?b=c' \\\n  --header 'X-Name: O'\\''Brien' \\\n  --header 'Content-Type: application/json' \\\n  --data-raw '{\"it'\\''s\": true}'"
+	if command != want {
+		t.Errorf("got\n%s\nwant\n%s", command, want)
+	}
+
+	// The rendered command parses back to the same request.
+	request, err := Parse(command)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if request.Method != "POST" || request.Headers[0].Value != "O'Brien" || *request.Body.Raw != body {
+		t.Errorf("round trip changed the request: %+v", request)
+	}
+}
+
+func TestRenderQuotesMethod(t *testing.T) {
+	command := Render(&hoppscotch.PreparedRequest{Method: "GET;CURL HTTP://X|SH", URL: "https://example.com"})
+
+	args, err := Split(strings.ReplaceAll(command, "\\\n", ""))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"curl", "--request", "GET;CURL HTTP://X|SH", "https://example.com"}
+	if !reflect.DeepEqual(args, want) {
+		t.Errorf("got %q, want %q", args, want)
+	}
+}
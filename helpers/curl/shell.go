@@ -0,0 +1,127 @@
+package curl
+
+import (
+	"errors"
+	"strings"
+)
+
+// Split splits a command line into arguments the way a POSIX shell does.
+// Single quotes, double quotes, $'...' strings, backslash escapes and line
+// continuations are supported, expansions are not.
+func Split(command string) ([]string, error) {
+	args := []string{}
+	current := strings.Builder{}
+	inArg := false
+
+	runes := []rune(command)
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+
+		switch {
+		case r == '\\':
+			if i+1 >= len(runes) {
+				return nil, errors.New("the command ends with an escape character")
+			}
+			i++
+			// A backslash before a newline continues the command.
+			if runes[i] == '\n' || (runes[i] == '\r' && i+1 < len(runes) && runes[i+1] == '\n') {
+				if runes[i] == '\r' {
+					i++
+				}
+				continue
+			}
+			current.WriteRune(runes[i])
+			inArg = true
+		case r == '\'':
+			end := indexRune(runes, i+1, '\'')
+			if end == -1 {
+				return nil, errors.New("the command has an unterminated single quote")
+			}
+			current.WriteString(string(runes[i+1 : end]))
+			i = end
+			inArg = true
+		case r == '$' && i+1 < len(runes) && runes[i+1] == '\'':
+			end, value, err := ansiCString(runes, i+2)
+			if err != nil {
+				return nil, err
+			}
+			current.WriteString(value)
+			i = end
+			inArg = true
+		case r == '"':
+			i++
+			for ; i < len(runes) && runes[i] != '"'; i++ {
+				if runes[i] == '\\' && i+1 < len(runes) && strings.ContainsRune("\"\\$`\n", runes[i+1]) {
+					i++
+					if runes[i] == '\n' {
+						continue
+					}
+				}
+				current.WriteRune(runes[i])
+			}
+			if i >= len(runes) {
+				return nil, errors.New("the command has an unterminated double quote")
+			}
+			inArg = true
+		case r == ' ' || r == '\t' || r == '\n' || r == '\r':
+			if inArg {
+				args = append(args, current.String())
+				current.Reset()
+				inArg = false
+			}
+		default:
+			current.WriteRune(r)
+			inArg = true
+		}
+	}
+
+	if inArg {
+		args = append(args, current.String())
+	}
+
+	return args, nil
+}
+
+func indexRune(runes []rune, start int, r rune) int {
+	for i := start; i < len(runes); i++ {
+		if runes[i] == r {
+			return i
+		}
+	}
+	return -1
+}
+
+// ansiCString reads a $'...' string starting after the opening quote and
+// returns the index of the closing quote.
+func ansiCString(runes []rune, start int) (int, string, error) {
+	output := strings.Builder{}
+	for i := start; i < len(runes); i++ {
+		switch runes[i] {
+		case '\'':
+			return i, output.String(), nil
+		case '\\':
+			if i+1 >= len(runes) {
+				break
+			}
+			i++
+			switch runes[i] {
+			case 'n':
+				output.WriteRune('\n')
+			case 't':
+				output.WriteRune('\t')
+			case 'r':
+				output.WriteRune('\r')
+			default:
+				output.WriteRune(runes[i])
+			}
+		default:
+			output.WriteRune(runes[i])
+		}
+	}
+	return 0, "", errors.New("the command has an unterminated single quote")
+}
+
+// Quote quotes an argument for a POSIX shell.
+func Quote(input string) string {
+	return "'" + strings.ReplaceAll(input, "'", `'\''`) + "'"
+}
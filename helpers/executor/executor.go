@@ -0,0 +1,242 @@
+package executor
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/base64"
+	"errors"
+	"io"
+	"mime/multipart"
+	"net"
+	"net/http"
+	"net/http/httptrace"
+	"net/url"
+	"sort"
+	"strconv"
+	"time"
+	"unicode/utf8"
+
+	"github.com/jerbob92/hoppscotch-backend/helpers/hoppscotch"
+)
+
+// maxRedirects is the amount of redirects that are followed.
+const maxRedirects = 10
+
+const (
+	BodyEncodingText   = "text"
+	BodyEncodingBase64 = "base64"
+)
+
+// Options configure how requests are executed.
+type Options struct {
+	Policy          *Policy
+	Timeout         time.Duration
+	MaxResponseSize int64
+}
+
+type Header struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+// Timings are the durations of the phases of the request in milliseconds,
+// phases that didn't happen, like TLS for plain HTTP, are 0.
+type Timings struct {
+	DNS       float64 `json:"dns"`
+	Connect   float64 `json:"connect"`
+	TLS       float64 `json:"tls"`
+	FirstByte float64 `json:"firstByte"`
+	Total     float64 `json:"total"`
+}
+
+// Response is the response of an executed request. Bodies that are not valid
+// UTF-8 are base64 encoded.
+type Response struct {
+	Status       int      `json:"status"`
+	StatusText   string   `json:"statusText"`
+	Headers      []Header `json:"headers"`
+	Body         string   `json:"body"`
+	BodyEncoding string   `json:"bodyEncoding"`
+	Truncated    bool     `json:"truncated"`
+	Timings      Timings  `json:"timings"`
+}
+
+func milliseconds(duration time.Duration) float64 {
+	return float64(duration) / float64(time.Millisecond)
+}
+
+// Execute sends the prepared request and reads the response. File fields of
+// multipart bodies are left out, because the files themselves are not
+// stored.
+func Execute(ctx context.Context, request *hoppscotch.PreparedRequest, options Options) (*Response, error) {
+	if options.Policy == nil {
+		return nil, errors.New("no target policy configured")
+	}
+
+	targetURL, err := url.Parse(request.URL)
+	if err != nil {
+		return nil, errors.New("invalid URL: " + err.Error())
+	}
+	if targetURL.Scheme != "http" && targetURL.Scheme != "https" {
+		return nil, errors.New("only http and https URLs can be requested")
+	}
+	if targetURL.Hostname() == "" {
+		return nil, errors.New("the URL has no host")
+	}
+	if !options.Policy.Allowed(targetURL.Hostname(), net.ParseIP(targetURL.Hostname())) {
+		return nil, &TargetNotAllowedError{Host: targetURL.Hostname()}
+	}
+
+	body, contentType, err := requestBody(request)
+	if err != nil {
+		return nil, err
+	}
+
+	if options.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, options.Timeout)
+		defer cancel()
+	}
+
+	timings := Timings{}
+	var dnsStart, connectStart, tlsStart time.Time
+	trace := &httptrace.ClientTrace{
+		DNSStart: func(httptrace.DNSStartInfo) {
+			dnsStart = time.Now()
+		},
+		DNSDone: func(httptrace.DNSDoneInfo) {
+			timings.DNS += milliseconds(time.Since(dnsStart))
+		},
+		ConnectStart: func(string, string) {
+			connectStart = time.Now()
+		},
+		ConnectDone: func(string, string, error) {
+			timings.Connect += milliseconds(time.Since(connectStart))
+		},
+		TLSHandshakeStart: func() {
+			tlsStart = time.Now()
+		},
+		TLSHandshakeDone: func(tls.ConnectionState, error) {
+			timings.TLS += milliseconds(time.Since(tlsStart))
+		},
+	}
+
+	httpRequest, err := http.NewRequestWithContext(httptrace.WithClientTrace(ctx, trace), request.Method, targetURL.String(), body)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, header := range request.Headers {
+		if http.CanonicalHeaderKey(header.Key) == "Host" {
+			httpRequest.Host = header.Value
+			continue
+		}
+		httpRequest.Header.Add(header.Key, header.Value)
+	}
+	if contentType != "" {
+		httpRequest.Header.Set("Content-Type", contentType)
+	}
+
+	dialer := &net.Dialer{Timeout: 30 * time.Second, KeepAlive: 30 * time.Second}
+	client := &http.Client{
+		Transport: &http.Transport{
+			// Environment proxies would bypass the policy.
+			Proxy:               nil,
+			DialContext:         options.Policy.dialContext(dialer),
+			TLSHandshakeTimeout: 10 * time.Second,
+			DisableKeepAlives:   true,
+		},
+		CheckRedirect: func(redirect *http.Request, via []*http.Request) error {
+			if len(via) >= maxRedirects {
+				return errors.New("stopped after " + strconv.Itoa(maxRedirects) + " redirects")
+			}
+			if redirect.URL.Scheme != "http" && redirect.URL.Scheme != "https" {
+				return errors.New("only http and https URLs can be requested")
+			}
+			return nil
+		},
+	}
+
+	start := time.Now()
+	httpResponse, err := client.Do(httpRequest)
+	if err != nil {
+		var targetErr *TargetNotAllowedError
+		if errors.As(err, &targetErr) {
+			return nil, targetErr
+		}
+		return nil, err
+	}
+	defer httpResponse.Body.Close()
+	timings.FirstByte = milliseconds(time.Since(start))
+
+	reader := io.Reader(httpResponse.Body)
+	if options.MaxResponseSize > 0 {
+		reader = io.LimitReader(httpResponse.Body, options.MaxResponseSize+1)
+	}
+	responseBody, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, err
+	}
+	timings.Total = milliseconds(time.Since(start))
+
+	response := &Response{
+		Status:       httpResponse.StatusCode,
+		StatusText:   http.StatusText(httpResponse.StatusCode),
+		Headers:      []Header{},
+		BodyEncoding: BodyEncodingText,
+		Timings:      timings,
+	}
+
+	if options.MaxResponseSize > 0 && int64(len(responseBody)) > options.MaxResponseSize {
+		responseBody = responseBody[:options.MaxResponseSize]
+		response.Truncated = true
+	}
+
+	if utf8.Valid(responseBody) {
+		response.Body = string(responseBody)
+	} else {
+		response.Body = base64.StdEncoding.EncodeToString(responseBody)
+		response.BodyEncoding = BodyEncodingBase64
+	}
+
+	keys := []string{}
+	for key := range httpResponse.Header {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		for _, value := range httpResponse.Header[key] {
+			response.Headers = append(response.Headers, Header{Key: key, Value: value})
+		}
+	}
+
+	return response, nil
+}
+
+// requestBody returns the body of the request, and the content type when it
+// has to be set by us.
+func requestBody(request *hoppscotch.PreparedRequest) (io.Reader, string, error) {
+	if request.ContentType == hoppscotch.ContentTypeMultipart {
+		body := &bytes.Buffer{}
+		writer := multipart.NewWriter(body)
+		for _, field := range request.FormData {
+			if field.IsFile {
+				continue
+			}
+			if err := writer.WriteField(field.Key, field.Value); err != nil {
+				return nil, "", err
+			}
+		}
+		if err := writer.Close(); err != nil {
+			return nil, "", err
+		}
+		return body, writer.FormDataContentType(), nil
+	}
+
+	if request.Body != nil && *request.Body != "" {
+		return bytes.NewBufferString(*request.Body), "", nil
+	}
+
+	return nil, "", nil
+}
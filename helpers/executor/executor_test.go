@@ -0,0 +1,172 @@
+package executor
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jerbob92/hoppscotch-backend/helpers/hoppscotch"
+)
+
+// testOptions allows requests to the loopback address the test servers
+// listen on.
+func testOptions(t *testing.T, maxResponseSize int64) Options {
+	policy, err := NewPolicy([]string{"127.0.0.1"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return Options{Policy: policy, Timeout: 5 * time.Second, MaxResponseSize: maxResponseSize}
+}
+
+func TestExecuteAllowed(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Method", r.Method)
+		w.Write([]byte("hello " + r.Header.Get("X-Name")))
+	}))
+	defer server.Close()
+
+	response, err := Execute(context.Background(), &hoppscotch.PreparedRequest{
+		Method:  http.MethodGet,
+		URL:     server.URL,
+		Headers: []hoppscotch.KeyValue{{Key: "X-Name", Value: "world"}},
+	}, testOptions(t, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if response.Status != http.StatusOK {
+		t.Errorf("status = %d, want 200", response.Status)
+	}
+	if response.Body != "hello world" {
+		t.Errorf("body = %q, want %q", response.Body, "hello world")
+	}
+	if response.Truncated {
+		t.Error("response should not be truncated")
+	}
+}
+
+func TestExecuteDenied(t *testing.T) {
+	requested := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requested = true
+	}))
+	defer server.Close()
+
+	serverURL, _ := url.Parse(server.URL)
+	port := serverURL.Port()
+
+	policy, err := NewPolicy(nil, []string{"127.0.0.0/8"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, target := range []string{
+		server.URL,
+		"http://localhost:" + port,
+		"http://[::]:" + port,
+		"http://0.0.0.0:" + port,
+		"http://[::ffff:127.0.0.1]:" + port,
+	} {
+		_, err := Execute(context.Background(), &hoppscotch.PreparedRequest{Method: http.MethodGet, URL: target}, Options{Policy: policy})
+		var targetErr *TargetNotAllowedError
+		if !errors.As(err, &targetErr) {
+			t.Errorf("%s: expected TargetNotAllowedError, got %v", target, err)
+		}
+	}
+
+	if requested {
+		t.Error("a denied target was requested")
+	}
+}
+
+func TestExecuteUnspecifiedNotAllowedByLoopbackRule(t *testing.T) {
+	listener, err := net.Listen("tcp", "[::]:0")
+	if err != nil {
+		t.Skip("no IPv6 listener available: " + err.Error())
+	}
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	server.Listener.Close()
+	server.Listener = listener
+	server.Start()
+	defer server.Close()
+
+	_, port, _ := net.SplitHostPort(listener.Addr().String())
+	_, err = Execute(context.Background(), &hoppscotch.PreparedRequest{Method: http.MethodGet, URL: "http://[::]:" + port}, testOptions(t, 0))
+	var targetErr *TargetNotAllowedError
+	if !errors.As(err, &targetErr) {
+		t.Errorf("expected TargetNotAllowedError, got %v", err)
+	}
+}
+
+func TestExecuteRedirectToDeniedTarget(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "http://10.0.0.1/metadata", http.StatusFound)
+	}))
+	defer server.Close()
+
+	_, err := Execute(context.Background(), &hoppscotch.PreparedRequest{Method: http.MethodGet, URL: server.URL}, testOptions(t, 0))
+	var targetErr *TargetNotAllowedError
+	if !errors.As(err, &targetErr) {
+		t.Fatalf("expected TargetNotAllowedError, got %v", err)
+	}
+	if targetErr.Host != "10.0.0.1" {
+		t.Errorf("host = %q, want 10.0.0.1", targetErr.Host)
+	}
+}
+
+func TestExecuteRedirectAllowed(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/start" {
+			http.Redirect(w, r, "/end", http.StatusFound)
+			return
+		}
+		w.Write([]byte(r.URL.Path))
+	}))
+	defer server.Close()
+
+	response, err := Execute(context.Background(), &hoppscotch.PreparedRequest{Method: http.MethodGet, URL: server.URL + "/start"}, testOptions(t, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if response.Body != "/end" {
+		t.Errorf("body = %q, want /end", response.Body)
+	}
+}
+
+func TestExecuteMaxResponseSize(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(strings.Repeat("a", 100)))
+	}))
+	defer server.Close()
+
+	request := &hoppscotch.PreparedRequest{Method: http.MethodGet, URL: server.URL}
+
+	response, err := Execute(context.Background(), request, testOptions(t, 10))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !response.Truncated || len(response.Body) != 10 {
+		t.Errorf("truncated = %v, body length = %d, want true and 10", response.Truncated, len(response.Body))
+	}
+
+	response, err = Execute(context.Background(), request, testOptions(t, 100))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if response.Truncated || len(response.Body) != 100 {
+		t.Errorf("truncated = %v, body length = %d, want false and 100", response.Truncated, len(response.Body))
+	}
+}
+
+func TestExecuteRejectsOtherSchemes(t *testing.T) {
+	_, err := Execute(context.Background(), &hoppscotch.PreparedRequest{Method: http.MethodGet, URL: "file:///etc/passwd"}, testOptions(t, 0))
+	if err == nil {
+		t.Fatal("expected an error for a file URL")
+	}
+}
@@ -0,0 +1,181 @@
+package executor
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strings"
+)
+
+// TargetNotAllowedError is returned when a request is sent to a host that the
+// policy doesn't allow.
+type TargetNotAllowedError struct {
+	Host string
+}
+
+func (e *TargetNotAllowedError) Error() string {
+	return "sending requests to " + e.Host + " is not allowed"
+}
+
+// Policy decides which hosts requests may be sent to, to prevent requests to
+// internal services. A rule is a host name, where *.example.com also matches
+// the subdomains, an IP address or a CIDR. Denied targets are never allowed,
+// when there are allow rules only the targets that match one are allowed.
+type Policy struct {
+	allowHosts    []string
+	allowNetworks []*net.IPNet
+	denyHosts     []string
+	denyNetworks  []*net.IPNet
+}
+
+// NewPolicy parses the allow and deny rules.
+func NewPolicy(allow []string, deny []string) (*Policy, error) {
+	policy := &Policy{}
+
+	var err error
+	policy.allowHosts, policy.allowNetworks, err = parseRules(allow)
+	if err != nil {
+		return nil, err
+	}
+
+	policy.denyHosts, policy.denyNetworks, err = parseRules(deny)
+	if err != nil {
+		return nil, err
+	}
+
+	return policy, nil
+}
+
+func parseRules(rules []string) ([]string, []*net.IPNet, error) {
+	hosts := []string{}
+	networks := []*net.IPNet{}
+	for _, rule := range rules {
+		rule = strings.ToLower(strings.TrimSpace(rule))
+		if rule == "" {
+			continue
+		}
+
+		if strings.Contains(rule, "/") {
+			_, network, err := net.ParseCIDR(rule)
+			if err != nil {
+				return nil, nil, errors.New("invalid CIDR " + rule + ": " + err.Error())
+			}
+			networks = append(networks, network)
+			continue
+		}
+
+		if ip := net.ParseIP(rule); ip != nil {
+			bits := 128
+			if ip.To4() != nil {
+				ip = ip.To4()
+				bits = 32
+			}
+			networks = append(networks, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+
+		hosts = append(hosts, strings.TrimSuffix(rule, "."))
+	}
+	return hosts, networks, nil
+}
+
+func matchHost(rules []string, host string) bool {
+	for _, rule := range rules {
+		if rule == "*" || rule == host {
+			return true
+		}
+		if strings.HasPrefix(rule, "*.") && (host == rule[2:] || strings.HasSuffix(host, rule[1:])) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchNetwork(networks []*net.IPNet, ip net.IP) bool {
+	if ip == nil {
+		return false
+	}
+	for _, network := range networks {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// nat64Prefix is the well-known NAT64 prefix, its addresses reach the IPv4
+// address in their last 4 bytes.
+var nat64Prefix = &net.IPNet{IP: net.ParseIP("64:ff9b::"), Mask: net.CIDRMask(96, 128)}
+
+// internalIP returns whether the IP belongs to the host itself or to a
+// private network. IPv4-mapped IPv6 addresses are checked as their IPv4
+// address, because net.IP treats them as the same address.
+func internalIP(ip net.IP) bool {
+	if ip.To4() == nil && nat64Prefix.Contains(ip) {
+		ip = net.IP(ip.To16()[12:16])
+	}
+	return ip.IsUnspecified() || ip.IsLoopback() || ip.IsPrivate() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsMulticast()
+}
+
+// Allowed returns whether a request may be sent to the host, which resolved
+// to the IP. The IP is nil when the host was not resolved yet. Loopback,
+// unspecified, private, link-local and multicast addresses are never
+// allowed, unless an allow rule lists their IP or CIDR.
+func (p *Policy) Allowed(host string, ip net.IP) bool {
+	host = strings.ToLower(strings.TrimSuffix(host, "."))
+	if ip == nil {
+		ip = net.ParseIP(host)
+	}
+	if matchHost(p.denyHosts, host) || matchNetwork(p.denyNetworks, ip) {
+		return false
+	}
+
+	if ip != nil && internalIP(ip) {
+		return matchNetwork(p.allowNetworks, ip)
+	}
+
+	if len(p.allowHosts) == 0 && len(p.allowNetworks) == 0 {
+		return true
+	}
+
+	return matchHost(p.allowHosts, host) || matchNetwork(p.allowNetworks, ip)
+}
+
+// dialContext returns a dial function that checks every address the host
+// resolves to before connecting, so redirects and DNS records that point to
+// internal addresses are caught as well.
+func (p *Policy) dialContext(dialer *net.Dialer) func(ctx context.Context, network string, address string) (net.Conn, error) {
+	return func(ctx context.Context, network string, address string) (net.Conn, error) {
+		host, port, err := net.SplitHostPort(address)
+		if err != nil {
+			return nil, err
+		}
+
+		addresses, err := net.DefaultResolver.LookupIPAddr(ctx, host)
+		if err != nil {
+			return nil, err
+		}
+
+		var lastErr error
+		for _, ipAddress := range addresses {
+			if !p.Allowed(host, ipAddress.IP) {
+				lastErr = &TargetNotAllowedError{Host: host}
+				continue
+			}
+
+			conn, err := dialer.DialContext(ctx, network, net.JoinHostPort(ipAddress.IP.String(), port))
+			if err == nil {
+				return conn, nil
+			}
+			lastErr = err
+		}
+
+		if lastErr == nil {
+			lastErr = errors.New("no addresses found for " + host)
+		}
+
+		return nil, lastErr
+	}
+}
@@ -0,0 +1,75 @@
+package executor
+
+import (
+	"net"
+	"testing"
+)
+
+func TestPolicyAllowed(t *testing.T) {
+	defaultDeny := []string{"localhost", "127.0.0.0/8", "::1/128", "::/128", "10.0.0.0/8", "64:ff9b::/96"}
+
+	tests := []struct {
+		name  string
+		allow []string
+		deny  []string
+		host  string
+		ip    string
+		want  bool
+	}{
+		{name: "public host", host: "example.com", ip: "93.184.216.34", want: true},
+		{name: "unresolved host", host: "example.com", want: true},
+		{name: "denied host", deny: defaultDeny, host: "localhost", want: false},
+		{name: "denied host with trailing dot", deny: defaultDeny, host: "LOCALHOST.", want: false},
+		{name: "denied network", deny: defaultDeny, host: "internal.example.com", ip: "10.1.2.3", want: false},
+		{name: "loopback without rules", host: "127.0.0.1", want: false},
+		{name: "unspecified IPv6", host: "::", want: false},
+		{name: "unspecified IPv4", host: "0.0.0.0", want: false},
+		{name: "IPv6 loopback", host: "::1", want: false},
+		{name: "IPv4-mapped loopback", host: "::ffff:127.0.0.1", want: false},
+		{name: "IPv4-mapped private", host: "::ffff:10.0.0.1", want: false},
+		{name: "IPv4-mapped denied network", deny: defaultDeny, host: "::ffff:10.0.0.1", want: false},
+		{name: "NAT64 loopback", host: "64:ff9b::7f00:1", want: false},
+		{name: "NAT64 public", host: "64:ff9b::5db8:d822", want: true},
+		{name: "private", host: "a.example.com", ip: "192.168.1.1", want: false},
+		{name: "unique local", host: "a.example.com", ip: "fd00::1", want: false},
+		{name: "link-local", host: "a.example.com", ip: "169.254.169.254", want: false},
+		{name: "IPv6 link-local", host: "a.example.com", ip: "fe80::1", want: false},
+		{name: "multicast", host: "a.example.com", ip: "224.0.0.1", want: false},
+		{name: "IPv6 multicast", host: "a.example.com", ip: "ff02::1", want: false},
+		{name: "public host resolving to loopback", host: "evil.example.com", ip: "127.0.0.1", want: false},
+		{name: "loopback allowed by IP", allow: []string{"127.0.0.1"}, host: "127.0.0.1", want: true},
+		{name: "private allowed by CIDR", allow: []string{"10.0.0.0/8"}, host: "a.example.com", ip: "10.0.0.5", want: true},
+		{name: "private not allowed by host rule", allow: []string{"*.example.com"}, host: "a.example.com", ip: "10.0.0.5", want: false},
+		{name: "private not allowed by wildcard", allow: []string{"*"}, host: "127.0.0.1", want: false},
+		{name: "deny wins over allow", allow: []string{"127.0.0.1"}, deny: defaultDeny, host: "127.0.0.1", want: false},
+		{name: "allowed subdomain", allow: []string{"*.example.com"}, host: "api.example.com", ip: "93.184.216.34", want: true},
+		{name: "allowed domain itself", allow: []string{"*.example.com"}, host: "example.com", ip: "93.184.216.34", want: true},
+		{name: "not allowed domain", allow: []string{"*.example.com"}, host: "example.org", ip: "93.184.216.34", want: false},
+		{name: "suffix is not a subdomain", allow: []string{"*.example.com"}, host: "badexample.com", ip: "93.184.216.34", want: false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			policy, err := NewPolicy(test.allow, test.deny)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var ip net.IP
+			if test.ip != "" {
+				ip = net.ParseIP(test.ip)
+			}
+
+			if got := policy.Allowed(test.host, ip); got != test.want {
+				t.Errorf("Allowed(%q, %v) = %v, want %v", test.host, ip, got, test.want)
+			}
+		})
+	}
+}
+
+func TestNewPolicyInvalidCIDR(t *testing.T) {
+	_, err := NewPolicy(nil, []string{"10.0.0.0/33"})
+	if err == nil {
+		t.Fatal("expected an error for an invalid CIDR")
+	}
+}
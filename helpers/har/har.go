@@ -0,0 +1,242 @@
+package har
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/jerbob92/hoppscotch-backend/helpers/hoppscotch"
+)
+
+// HAR is an HTTP Archive 1.2 document, only the parts that are needed to
+// recreate the requests are decoded.
+type HAR struct {
+	Log Log `json:"log"`
+}
+
+type Log struct {
+	Version string  `json:"version"`
+	Creator Creator `json:"creator"`
+	Pages   []Page  `json:"pages"`
+	Entries []Entry `json:"entries"`
+}
+
+type Creator struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+type Page struct {
+	ID    string `json:"id"`
+	Title string `json:"title"`
+}
+
+type Entry struct {
+	PageRef string  `json:"pageref"`
+	Request Request `json:"request"`
+}
+
+type Request struct {
+	Method      string    `json:"method"`
+	URL         string    `json:"url"`
+	Headers     []Pair    `json:"headers"`
+	QueryString []Pair    `json:"queryString"`
+	PostData    *PostData `json:"postData"`
+}
+
+type Pair struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type PostData struct {
+	MimeType string  `json:"mimeType"`
+	Text     string  `json:"text"`
+	Params   []Param `json:"params"`
+}
+
+type Param struct {
+	Name     string `json:"name"`
+	Value    string `json:"value"`
+	FileName string `json:"fileName"`
+}
+
+// skippedHeaders are set by the client when the request is sent, or are part
+// of the body in Hoppscotch.
+var skippedHeaders = map[string]bool{
+	"content-length": true,
+	"content-type":   true,
+	"host":           true,
+	"connection":     true,
+}
+
+// Import converts the archive into a single collection. Entries are grouped
+// in folders by page, or by host when the archive has no pages.
+func Import(data []byte) (*hoppscotch.Collection, error) {
+	archive := &HAR{}
+	if err := json.Unmarshal(data, archive); err != nil {
+		return nil, fmt.Errorf("could not read HAR: %w", err)
+	}
+
+	if archive.Log.Version == "" || archive.Log.Entries == nil {
+		return nil, errors.New("document is not a HAR 1.2 archive")
+	}
+
+	name := "HAR import"
+	if archive.Log.Creator.Name != "" {
+		name += " (" + archive.Log.Creator.Name + ")"
+	}
+
+	collection := &hoppscotch.Collection{
+		Name:     name,
+		Folders:  []hoppscotch.Collection{},
+		Requests: []*hoppscotch.RESTRequest{},
+	}
+
+	pageTitles := map[string]string{}
+	for _, page := range archive.Log.Pages {
+		pageTitles[page.ID] = page.Title
+		if page.Title == "" {
+			pageTitles[page.ID] = page.ID
+		}
+	}
+
+	folderIndex := map[string]int{}
+	for _, entry := range archive.Log.Entries {
+		request, host, err := requestFromEntry(entry)
+		if err != nil {
+			return nil, err
+		}
+
+		folderName := host
+		if title, ok := pageTitles[entry.PageRef]; ok {
+			folderName = title
+		}
+
+		index, ok := folderIndex[folderName]
+		if !ok {
+			index = len(collection.Folders)
+			folderIndex[folderName] = index
+			collection.Folders = append(collection.Folders, hoppscotch.Collection{
+				Name:     folderName,
+				Folders:  []hoppscotch.Collection{},
+				Requests: []*hoppscotch.RESTRequest{},
+			})
+		}
+
+		collection.Folders[index].Requests = append(collection.Folders[index].Requests, request)
+	}
+
+	return collection, nil
+}
+
+func requestFromEntry(entry Entry) (*hoppscotch.RESTRequest, string, error) {
+	requestURL, err := url.Parse(entry.Request.URL)
+	if err != nil {
+		return nil, "", fmt.Errorf("could not parse URL %s: %w", entry.Request.URL, err)
+	}
+
+	query := entry.Request.QueryString
+	if query == nil {
+		query = pairsFromValues(requestURL.Query())
+	}
+
+	requestURL.RawQuery = ""
+	requestURL.Fragment = ""
+
+	method := strings.ToUpper(entry.Request.Method)
+	name := method + " " + requestURL.Path
+	if requestURL.Path == "" {
+		name = method + " /"
+	}
+
+	request := hoppscotch.NewRESTRequest(name, method, requestURL.String())
+
+	for _, param := range query {
+		request.Params = append(request.Params, hoppscotch.KeyValue{
+			Key:    param.Name,
+			Value:  param.Value,
+			Active: true,
+		})
+	}
+
+	for _, header := range entry.Request.Headers {
+		// HTTP/2 pseudo headers like :authority can't be sent by clients.
+		if strings.HasPrefix(header.Name, ":") || skippedHeaders[strings.ToLower(header.Name)] {
+			continue
+		}
+		request.Headers = append(request.Headers, hoppscotch.KeyValue{
+			Key:    header.Name,
+			Value:  header.Value,
+			Active: true,
+		})
+	}
+
+	if entry.Request.PostData != nil && entry.Request.PostData.MimeType != "" {
+		postData := entry.Request.PostData
+		contentType := hoppscotch.ContentTypeFromMimeType(postData.MimeType)
+		switch {
+		case contentType == hoppscotch.ContentTypeMultipart:
+			fields := []hoppscotch.FormDataKeyValue{}
+			for _, param := range postData.Params {
+				field := hoppscotch.FormDataKeyValue{
+					Key:    param.Name,
+					Value:  param.Value,
+					Active: true,
+				}
+				// Files can't be imported, the user has to select them again.
+				if param.FileName != "" {
+					field.Value = ""
+					field.IsFile = true
+				}
+				fields = append(fields, field)
+			}
+			request.SetFormDataBody(fields)
+		case contentType == hoppscotch.ContentTypeForm && len(postData.Params) > 0:
+			fields := []hoppscotch.KeyValue{}
+			for _, param := range postData.Params {
+				fields = append(fields, hoppscotch.KeyValue{
+					Key:    param.Name,
+					Value:  param.Value,
+					Active: true,
+				})
+			}
+			request.SetRawBody(contentType, hoppscotch.RawKeyValue(fields))
+		case contentType == hoppscotch.ContentTypeForm:
+			fields := []hoppscotch.KeyValue{}
+			values, _ := url.ParseQuery(postData.Text)
+			for _, pair := range pairsFromValues(values) {
+				fields = append(fields, hoppscotch.KeyValue{
+					Key:    pair.Name,
+					Value:  pair.Value,
+					Active: true,
+				})
+			}
+			request.SetRawBody(contentType, hoppscotch.RawKeyValue(fields))
+		default:
+			request.SetRawBody(contentType, postData.Text)
+		}
+	}
+
+	return request, requestURL.Host, nil
+}
+
+// pairsFromValues returns the values sorted by key.
+func pairsFromValues(values url.Values) []Pair {
+	keys := []string{}
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	pairs := []Pair{}
+	for _, key := range keys {
+		for _, value := range values[key] {
+			pairs = append(pairs, Pair{Name: key, Value: value})
+		}
+	}
+	return pairs
+}
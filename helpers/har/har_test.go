@@ -0,0 +1,83 @@
+package har
+
+import (
+	"reflect"
+	"testing"
+)
+
+type folder struct {
+	name     string
+	requests []string
+}
+
+func TestImportFolders(t *testing.T) {
+	tests := []struct {
+		name    string
+		archive string
+		folders []folder
+	}{
+		{
+			name: "pages",
+			archive: `{"log": {"version": "1.2", "creator": {"name": "Firefox"},
+				"pages": [{"id": "page_1", "title": "Home"}, {"id": "page_2"}],
+				"entries": [
+					{"pageref": "page_1", "request": {"method": "get", "url": "https://example.com/"}},
+					{"pageref": "page_2", "request": {"method": "GET", "url": "https://api.example.com/users?page=2"}},
+					{"pageref": "page_1", "request": {"method": "POST", "url": "https://api.example.com/events"}}]}}`,
+			folders: []folder{
+				{name: "Home", requests: []string{"GET /", "POST /events"}},
+				{name: "page_2", requests: []string{"GET /users"}},
+			},
+		},
+		{
+			name: "hosts",
+			archive: `{"log": {"version": "1.2", "entries": [
+				{"request": {"method": "GET", "url": "https://example.com"}},
+				{"request": {"method": "GET", "url": "https://api.example.com/users"}},
+				{"request": {"method": "DELETE", "url": "https://example.com/session"}}]}}`,
+			folders: []folder{
+				{name: "example.com", requests: []string{"GET /", "DELETE /session"}},
+				{name: "api.example.com", requests: []string{"GET /users"}},
+			},
+		},
+		{
+			name: "unknown page",
+			archive: `{"log": {"version": "1.2", "pages": [{"id": "page_1", "title": "Home"}], "entries": [
+				{"pageref": "page_1", "request": {"method": "GET", "url": "https://example.com/"}},
+				{"pageref": "page_9", "request": {"method": "GET", "url": "https://cdn.example.com/app.js"}}]}}`,
+			folders: []folder{
+				{name: "Home", requests: []string{"GET /"}},
+				{name: "cdn.example.com", requests: []string{"GET /app.js"}},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		collection, err := Import([]byte(test.archive))
+		if err != nil {
+			t.Errorf("%s: %v", test.name, err)
+			continue
+		}
+
+		folders := []folder{}
+		for _, collectionFolder := range collection.Folders {
+			requests := []string{}
+			for _, request := range collectionFolder.Requests {
+				requests = append(requests, request.Name)
+			}
+			folders = append(folders, folder{name: collectionFolder.Name, requests: requests})
+		}
+		if !reflect.DeepEqual(folders, test.folders) {
+			t.Errorf("%s: folders = %+v, want %+v", test.name, folders, test.folders)
+		}
+		if len(collection.Requests) != 0 {
+			t.Errorf("%s: got %d requests outside of folders", test.name, len(collection.Requests))
+		}
+	}
+
+	for _, archive := range []string{`{}`, `{"log": {"entries": []}}`, `{"log": {"version": "1.2"}}`, `[]`} {
+		if _, err := Import([]byte(archive)); err == nil {
+			t.Errorf("%s: expected an error", archive)
+		}
+	}
+}
@@ -0,0 +1,9 @@
+package hoppscotch
+
+// Collection is a folder of requests, it's what importers of other formats
+// produce before it's stored as team collections.
+type Collection struct {
+	Name     string
+	Folders  []Collection
+	Requests []*RESTRequest
+}
@@ -0,0 +1,40 @@
+package hoppscotch
+
+import (
+	"strings"
+)
+
+var knownContentTypes = []string{
+	ContentTypeJSON,
+	ContentTypeLDJSON,
+	ContentTypeHALJSON,
+	ContentTypeVNDAPIJSON,
+	ContentTypeXML,
+	ContentTypeForm,
+	ContentTypeMultipart,
+	ContentTypeHTML,
+	ContentTypePlain,
+}
+
+// ContentTypeFromMimeType maps the mime type another tool stored for a body
+// to one of the content types the frontend knows about.
+func ContentTypeFromMimeType(mimeType string) string {
+	mimeType = strings.ToLower(strings.TrimSpace(strings.SplitN(mimeType, ";", 2)[0]))
+
+	for _, contentType := range knownContentTypes {
+		if mimeType == contentType {
+			return contentType
+		}
+	}
+
+	switch {
+	case strings.HasPrefix(mimeType, "multipart/"):
+		return ContentTypeMultipart
+	case strings.HasSuffix(mimeType, "+json"), strings.HasSuffix(mimeType, "/json"):
+		return ContentTypeJSON
+	case strings.HasSuffix(mimeType, "+xml"), strings.HasSuffix(mimeType, "/xml"):
+		return ContentTypeXML
+	}
+
+	return ContentTypePlain
+}
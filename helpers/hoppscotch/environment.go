@@ -0,0 +1,79 @@
+package hoppscotch
+
+import (
+	"encoding/json"
+	"regexp"
+)
+
+// EnvironmentVariable is a single variable in TeamEnvironment.Variables.
+type EnvironmentVariable struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+// ParseEnvironmentVariables parses the JSON string of a stored environment.
+// An empty string is an empty environment.
+func ParseEnvironmentVariables(data string) ([]EnvironmentVariable, error) {
+	variables := []EnvironmentVariable{}
+	if data == "" {
+		return variables, nil
+	}
+	if err := json.Unmarshal([]byte(data), &variables); err != nil {
+		return nil, err
+	}
+	return variables, nil
+}
+
+// EnvironmentVariablesJSON returns the variables as a JSON string, ready to
+// be stored.
+func EnvironmentVariablesJSON(variables []EnvironmentVariable) (string, error) {
+	if variables == nil {
+		variables = []EnvironmentVariable{}
+	}
+	variablesJSON, err := json.Marshal(variables)
+	if err != nil {
+		return "", err
+	}
+	return string(variablesJSON), nil
+}
+
+// VariableMap turns a list of variables into a lookup map, later variables
+// override earlier ones.
+func VariableMap(variables []EnvironmentVariable) map[string]string {
+	output := map[string]string{}
+	for i := range variables {
+		output[variables[i].Key] = variables[i].Value
+	}
+	return output
+}
+
+// VariablePattern matches a Hoppscotch template variable like <<baseURL>>.
+var VariablePattern = regexp.MustCompile(`<<([^<>]+)>>`)
+
+// maxVariableExpansion is the same limit the frontend uses for variables
+// that reference other variables.
+const maxVariableExpansion = 10
+
+// ReplaceVariables replaces all known <<variables>> in the input, variables
+// can contain other variables. Unknown variables are left alone.
+func ReplaceVariables(input string, variables map[string]string) string {
+	if len(variables) == 0 {
+		return input
+	}
+
+	for i := 0; i < maxVariableExpansion; i++ {
+		output := VariablePattern.ReplaceAllStringFunc(input, func(match string) string {
+			value, ok := variables[match[2:len(match)-2]]
+			if !ok {
+				return match
+			}
+			return value
+		})
+		if output == input {
+			break
+		}
+		input = output
+	}
+
+	return input
+}
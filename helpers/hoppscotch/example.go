@@ -0,0 +1,109 @@
+package hoppscotch
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+)
+
+// ExamplesField is the field of an exported request that holds its example
+// responses.
+const ExamplesField = "examples"
+
+// ExampleResponse is a saved response of a request, used for mocks,
+// documentation and contract checks.
+type ExampleResponse struct {
+	Name    string          `json:"name"`
+	Status  int             `json:"status"`
+	Headers []ExampleHeader `json:"headers"`
+	Body    string          `json:"body"`
+}
+
+type ExampleHeader struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+// Validate returns all problems of the example, or nil when it's valid.
+func (e *ExampleResponse) Validate() error {
+	validationErr := &ValidationError{}
+	if strings.TrimSpace(e.Name) == "" {
+		validationErr.Errors = append(validationErr.Errors, FieldError{Field: "name", Message: "is required"})
+	}
+	if e.Status < 100 || e.Status > 599 {
+		validationErr.Errors = append(validationErr.Errors, FieldError{Field: "status", Message: "must be a HTTP status code between 100 and 599"})
+	}
+	for i, header := range e.Headers {
+		if strings.TrimSpace(header.Key) == "" {
+			validationErr.Errors = append(validationErr.Errors, FieldError{Field: "headers[" + strconv.Itoa(i) + "].key", Message: "is required"})
+		}
+	}
+
+	if len(validationErr.Errors) > 0 {
+		return validationErr
+	}
+
+	return nil
+}
+
+// ParseExampleHeaders parses the JSON string of stored example headers. An
+// empty string are no headers.
+func ParseExampleHeaders(data string) ([]ExampleHeader, error) {
+	headers := []ExampleHeader{}
+	if data == "" {
+		return headers, nil
+	}
+
+	if err := json.Unmarshal([]byte(data), &headers); err != nil {
+		return nil, err
+	}
+
+	if headers == nil {
+		headers = []ExampleHeader{}
+	}
+
+	return headers, nil
+}
+
+// ExampleHeadersJSON returns the headers as a JSON string, ready to be stored.
+func ExampleHeadersJSON(headers []ExampleHeader) (string, error) {
+	if headers == nil {
+		headers = []ExampleHeader{}
+	}
+
+	headersJSON, err := json.Marshal(headers)
+	if err != nil {
+		return "", err
+	}
+
+	return string(headersJSON), nil
+}
+
+// ParseExamples reads the example responses of an exported request. Requests
+// without examples have none.
+func ParseExamples(data interface{}) ([]ExampleResponse, error) {
+	examples := []ExampleResponse{}
+	if data == nil {
+		return examples, nil
+	}
+
+	examplesJSON, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(examplesJSON, &examples); err != nil {
+		return nil, &ValidationError{Errors: []FieldError{{Field: ExamplesField, Message: "must be a list of example responses"}}}
+	}
+
+	for i := range examples {
+		if err := examples[i].Validate(); err != nil {
+			return nil, err.(*ValidationError).Prefix(ExamplesField + "[" + strconv.Itoa(i) + "]")
+		}
+		if examples[i].Headers == nil {
+			examples[i].Headers = []ExampleHeader{}
+		}
+	}
+
+	return examples, nil
+}
@@ -0,0 +1,17 @@
+package hoppscotch
+
+// GraphQLRequestVersion is the version of the GraphQL request document this
+// backend reads and writes. Version 2 added the auth.
+const GraphQLRequestVersion = "2"
+
+// GraphQLRequest is the document the Hoppscotch frontend stores for a
+// GraphQL request.
+type GraphQLRequest struct {
+	Version   Version    `json:"v"`
+	Name      string     `json:"name"`
+	URL       string     `json:"url"`
+	Headers   []KeyValue `json:"headers"`
+	Query     string     `json:"query"`
+	Variables string     `json:"variables"`
+	Auth      Auth       `json:"auth"`
+}
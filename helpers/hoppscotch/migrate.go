@@ -0,0 +1,246 @@
+package hoppscotch
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// RequestMigration upgrades a request document from one version to the next.
+type RequestMigration struct {
+	From    int
+	Migrate func(document map[string]interface{}) error
+}
+
+// restMigrations and graphQLMigrations are the registries of migrations, a
+// document of version N is migrated by the migration with From N until it is
+// at the current version.
+var restMigrations = []RequestMigration{
+	{From: 0, Migrate: migrateRESTFromLegacy},
+}
+
+var graphQLMigrations = []RequestMigration{
+	{From: 0, Migrate: migrateGraphQLFromLegacy},
+	{From: 1, Migrate: migrateGraphQLToV2},
+}
+
+// MigrateRequest upgrades a decoded request document to the current version
+// of its kind. It returns whether the document was changed, documents of a
+// newer version than this server knows are rejected.
+func MigrateRequest(document map[string]interface{}) (bool, error) {
+	var migrations []RequestMigration
+	var current int
+	switch requestKind(document) {
+	case RequestKindREST:
+		migrations = restMigrations
+		current, _ = strconv.Atoi(RESTRequestVersion)
+	case RequestKindGraphQL:
+		migrations = graphQLMigrations
+		current, _ = strconv.Atoi(GraphQLRequestVersion)
+	default:
+		return false, nil
+	}
+
+	version, err := documentVersion(document)
+	if err != nil {
+		return false, err
+	}
+	if version > current {
+		return false, fmt.Errorf("version %d is newer than the supported version %d", version, current)
+	}
+
+	changed := false
+	for version < current {
+		var migration *RequestMigration
+		for i := range migrations {
+			if migrations[i].From == version {
+				migration = &migrations[i]
+				break
+			}
+		}
+		if migration == nil {
+			return changed, fmt.Errorf("no migration from version %d", version)
+		}
+
+		if err := migration.Migrate(document); err != nil {
+			return changed, fmt.Errorf("migrating from version %d: %w", version, err)
+		}
+		changed = true
+
+		version, err = documentVersion(document)
+		if err != nil {
+			return changed, err
+		}
+		if version <= migration.From {
+			return changed, fmt.Errorf("the migration from version %d did not change the version", migration.From)
+		}
+	}
+
+	return changed, nil
+}
+
+// MigrateRequestJSON is MigrateRequest for a stored JSON string. The input is
+// returned as is when nothing had to be changed.
+func MigrateRequestJSON(data string) (string, bool, error) {
+	document := map[string]interface{}{}
+	decoder := json.NewDecoder(strings.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(&document); err != nil {
+		return data, false, err
+	}
+
+	changed, err := MigrateRequest(document)
+	if err != nil || !changed {
+		return data, false, err
+	}
+
+	output, err := json.Marshal(document)
+	if err != nil {
+		return data, false, err
+	}
+	return string(output), true, nil
+}
+
+// documentVersion returns the "v" field as a number, documents without a
+// version are version 0.
+func documentVersion(document map[string]interface{}) (int, error) {
+	switch value := document["v"].(type) {
+	case nil:
+		return 0, nil
+	case string:
+		if value == "" {
+			return 0, nil
+		}
+		return strconv.Atoi(value)
+	case json.Number:
+		return strconv.Atoi(value.String())
+	case float64:
+		return int(value), nil
+	}
+	return 0, fmt.Errorf("invalid version %v", document["v"])
+}
+
+// migrateRESTFromLegacy converts the request the frontend stored before
+// versioning, with the URL split in url and path, body fields on the request
+// and the auth as a label, to version 1.
+func migrateRESTFromLegacy(document map[string]interface{}) error {
+	if _, ok := document["endpoint"]; !ok {
+		url, _ := document["url"].(string)
+		path, _ := document["path"].(string)
+		document["endpoint"] = url + path
+	}
+
+	document["headers"] = migrateLegacyKeyValues(document["headers"])
+	document["params"] = migrateLegacyKeyValues(document["params"])
+
+	if _, ok := document["body"]; !ok {
+		contentType, _ := document["contentType"].(string)
+		body := map[string]interface{}{
+			"contentType": nil,
+			"body":        nil,
+		}
+		rawParams, _ := document["rawParams"].(string)
+		bodyParams := migrateLegacyKeyValues(document["bodyParams"])
+
+		switch {
+		case contentType == "":
+		case contentType == ContentTypeMultipart:
+			formData := []interface{}{}
+			for _, param := range bodyParams {
+				field := param.(map[string]interface{})
+				field["isFile"] = false
+				formData = append(formData, field)
+			}
+			body["contentType"] = contentType
+			body["body"] = formData
+		case contentType == ContentTypeForm && document["rawInput"] != true:
+			lines := []KeyValue{}
+			for _, param := range bodyParams {
+				field := param.(map[string]interface{})
+				key, _ := field["key"].(string)
+				value, _ := field["value"].(string)
+				active, _ := field["active"].(bool)
+				lines = append(lines, KeyValue{Key: key, Value: value, Active: active})
+			}
+			body["contentType"] = contentType
+			body["body"] = RawKeyValue(lines)
+		default:
+			body["contentType"] = contentType
+			body["body"] = rawParams
+		}
+		document["body"] = body
+	}
+
+	if auth, ok := document["auth"].(string); ok || document["auth"] == nil {
+		newAuth := map[string]interface{}{
+			"authType":   AuthTypeNone,
+			"authActive": true,
+		}
+		switch auth {
+		case "Basic Auth":
+			newAuth["authType"] = AuthTypeBasic
+			newAuth["username"], _ = document["httpUser"].(string)
+			newAuth["password"], _ = document["httpPassword"].(string)
+		case "Bearer Token":
+			newAuth["authType"] = AuthTypeBearer
+			newAuth["token"], _ = document["bearerToken"].(string)
+		}
+		document["auth"] = newAuth
+	}
+
+	for _, key := range []string{"url", "path", "contentType", "rawParams", "rawInput", "bodyParams", "httpUser", "httpPassword", "bearerToken", "requestType"} {
+		delete(document, key)
+	}
+
+	document["v"] = "1"
+	return nil
+}
+
+// migrateLegacyKeyValues converts a legacy list of key values, where active
+// was optional, to a list with active set on every item.
+func migrateLegacyKeyValues(input interface{}) []interface{} {
+	output := []interface{}{}
+	items, _ := input.([]interface{})
+	for _, item := range items {
+		object, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		key, _ := object["key"].(string)
+		value, _ := object["value"].(string)
+		active, ok := object["active"].(bool)
+		if !ok {
+			active = true
+		}
+
+		output = append(output, map[string]interface{}{
+			"key":    key,
+			"value":  value,
+			"active": active,
+		})
+	}
+	return output
+}
+
+// migrateGraphQLFromLegacy adds the version to a GraphQL request from before
+// versioning, the shape is the same as version 1.
+func migrateGraphQLFromLegacy(document map[string]interface{}) error {
+	document["headers"] = migrateLegacyKeyValues(document["headers"])
+	document["v"] = "1"
+	return nil
+}
+
+// migrateGraphQLToV2 adds the auth, which GraphQL requests didn't have in
+// version 1.
+func migrateGraphQLToV2(document map[string]interface{}) error {
+	if _, ok := document["auth"]; !ok {
+		document["auth"] = map[string]interface{}{
+			"authType":   AuthTypeNone,
+			"authActive": true,
+		}
+	}
+	document["v"] = "2"
+	return nil
+}
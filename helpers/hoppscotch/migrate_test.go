@@ -0,0 +1,162 @@
+package hoppscotch
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// sameJSON returns whether both documents decode to the same value.
+func sameJSON(t *testing.T, a string, b string) bool {
+	var decodedA, decodedB interface{}
+	if err := json.Unmarshal([]byte(a), &decodedA); err != nil {
+		t.Fatalf("%s: %v", a, err)
+	}
+	if err := json.Unmarshal([]byte(b), &decodedB); err != nil {
+		t.Fatalf("%s: %v", b, err)
+	}
+	return reflect.DeepEqual(decodedA, decodedB)
+}
+
+func TestMigrateRequestJSON(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    string
+		changed bool
+	}{
+		{
+			name:  "REST 0 to 1",
+			input: `{"name": "a", "url": "https://example.com", "path": "/users", "method": "POST", "headers": [{"key": "A", "value": "b"}, {"key": "C", "value": "d", "active": false}], "params": [], "contentType": "application/json", "rawParams": "{\"a\": 1}", "auth": "Bearer Token", "bearerToken": "abc", "requestType": "REST", "x-custom": {"kept": true}}`,
+			want: `{"v": "1", "name": "a", "endpoint": "https://example.com/users", "method": "POST",
+				"headers": [{"key": "A", "value": "b", "active": true}, {"key": "C", "value": "d", "active": false}], "params": [],
+				"body": {"contentType": "application/json", "body": "{\"a\": 1}"},
+				"auth": {"authType": "bearer", "authActive": true, "token": "abc"}, "x-custom": {"kept": true}}`,
+			changed: true,
+		},
+		{
+			name:  "REST 0 to 1 form body and basic auth",
+			input: `{"name": "a", "url": "https://example.com", "method": "POST", "contentType": "application/x-www-form-urlencoded", "bodyParams": [{"key": "a", "value": "1"}, {"key": "b", "value": "2", "active": false}], "auth": "Basic Auth", "httpUser": "alice", "httpPassword": "secret"}`,
+			want: `{"v": "1", "name": "a", "endpoint": "https://example.com", "method": "POST", "headers": [], "params": [],
+				"body": {"contentType": "application/x-www-form-urlencoded", "body": "a: 1\n# b: 2"},
+				"auth": {"authType": "basic", "authActive": true, "username": "alice", "password": "secret"}}`,
+			changed: true,
+		},
+		{
+			name:  "REST 0 to 1 without body",
+			input: `{"v": 0, "name": "a", "endpoint": "https://example.com", "method": "GET"}`,
+			want: `{"v": "1", "name": "a", "endpoint": "https://example.com", "method": "GET", "headers": [], "params": [],
+				"body": {"contentType": null, "body": null}, "auth": {"authType": "none", "authActive": true}}`,
+			changed: true,
+		},
+		{
+			name:    "REST current",
+			input:   `{"v": "1", "name": "a", "endpoint": "https://example.com", "method": "GET", "x-custom": 1}`,
+			want:    `{"v": "1", "name": "a", "endpoint": "https://example.com", "method": "GET", "x-custom": 1}`,
+			changed: false,
+		},
+		{
+			name:    "GraphQL 0 to 2",
+			input:   `{"name": "a", "url": "https://example.com/graphql", "headers": [{"key": "A", "value": "b"}], "query": "{ a }", "variables": "{}", "x-custom": "kept"}`,
+			want:    `{"v": "2", "name": "a", "url": "https://example.com/graphql", "headers": [{"key": "A", "value": "b", "active": true}], "query": "{ a }", "variables": "{}", "auth": {"authType": "none", "authActive": true}, "x-custom": "kept"}`,
+			changed: true,
+		},
+		{
+			name:    "GraphQL 1 to 2 keeps the auth",
+			input:   `{"v": 1, "name": "a", "url": "https://example.com/graphql", "headers": [], "query": "{ a }", "auth": {"authType": "bearer", "authActive": true, "token": "abc"}}`,
+			want:    `{"v": "2", "name": "a", "url": "https://example.com/graphql", "headers": [], "query": "{ a }", "auth": {"authType": "bearer", "authActive": true, "token": "abc"}}`,
+			changed: true,
+		},
+		{
+			name:    "GraphQL current",
+			input:   `{"v": "2", "name": "a", "url": "https://example.com/graphql", "query": "{ a }"}`,
+			want:    `{"v": "2", "name": "a", "url": "https://example.com/graphql", "query": "{ a }"}`,
+			changed: false,
+		},
+		{
+			name:    "unknown kind",
+			input:   `{"name": "a"}`,
+			want:    `{"name": "a"}`,
+			changed: false,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			output, changed, err := MigrateRequestJSON(test.input)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if changed != test.changed {
+				t.Errorf("changed = %v, want %v", changed, test.changed)
+			}
+			if !changed && output != test.input {
+				t.Errorf("an unchanged document should be returned as is, got %s", output)
+			}
+			if !sameJSON(t, output, test.want) {
+				t.Errorf("got\n%s\nwant\n%s", output, test.want)
+			}
+		})
+	}
+}
+
+func TestMigrateRequestJSONErrors(t *testing.T) {
+	tests := map[string]string{
+		"future REST version":    `{"v": "2", "endpoint": "https://example.com", "method": "GET"}`,
+		"future GraphQL version": `{"v": 3, "url": "https://example.com/graphql", "query": "{ a }"}`,
+		"invalid version":        `{"v": "one", "endpoint": "https://example.com", "method": "GET"}`,
+		"version of wrong type":  `{"v": true, "endpoint": "https://example.com", "method": "GET"}`,
+		"not an object":          `[]`,
+	}
+
+	for name, input := range tests {
+		t.Run(name, func(t *testing.T) {
+			output, changed, err := MigrateRequestJSON(input)
+			if err == nil {
+				t.Fatalf("expected an error, got %s", output)
+			}
+			if changed || output != input {
+				t.Errorf("the input should be returned as is, got %v and %s", changed, output)
+			}
+		})
+	}
+}
+
+func TestMigrateRequestMissingMigration(t *testing.T) {
+	migrations := restMigrations
+	defer func() {
+		restMigrations = migrations
+	}()
+	restMigrations = []RequestMigration{}
+
+	_, err := MigrateRequest(map[string]interface{}{"endpoint": "https://example.com"})
+	if err == nil || !strings.Contains(err.Error(), "no migration from version 0") {
+		t.Errorf("expected a missing migration error, got %v", err)
+	}
+
+	restMigrations = []RequestMigration{{From: 0, Migrate: func(document map[string]interface{}) error {
+		return nil
+	}}}
+	_, err = MigrateRequest(map[string]interface{}{"endpoint": "https://example.com"})
+	if err == nil || !strings.Contains(err.Error(), "did not change the version") {
+		t.Errorf("expected an error for a migration that doesn't change the version, got %v", err)
+	}
+}
+
+func TestNormalizeRequestMigrates(t *testing.T) {
+	normalized, err := NormalizeRequest(`{"name": "a", "url": "https://example.com", "path": "/a", "method": "get"}`, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(normalized.JSON, `"endpoint":"https://example.com/a"`) || !strings.Contains(normalized.JSON, `"v":"1"`) {
+		t.Errorf("the request should be migrated before it is validated, got %s", normalized.JSON)
+	}
+
+	_, err = NormalizeRequest(`{"v": "2", "name": "a", "endpoint": "https://example.com", "method": "GET"}`, "")
+	var validationErr *ValidationError
+	if !errors.As(err, &validationErr) || validationErr.Errors[0].Field != "v" {
+		t.Errorf("expected a version validation error, got %v", err)
+	}
+}
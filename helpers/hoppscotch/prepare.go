@@ -0,0 +1,170 @@
+package hoppscotch
+
+import (
+	"encoding/base64"
+	"net/url"
+	"strings"
+)
+
+// PreparedRequest is a request as it would be sent: variables are replaced,
+// disabled fields are left out and the auth is applied to the headers or the
+// query.
+type PreparedRequest struct {
+	Method  string
+	URL     string
+	Headers []KeyValue
+
+	// ContentType is empty when the request has no body.
+	ContentType string
+
+	// Body is set for every content type except multipart/form-data, which
+	// uses FormData.
+	Body     *string
+	FormData []FormDataKeyValue
+}
+
+// Header returns the value of the first header with the given name.
+func (p *PreparedRequest) Header(name string) (string, bool) {
+	for i := range p.Headers {
+		if strings.EqualFold(p.Headers[i].Key, name) {
+			return p.Headers[i].Value, true
+		}
+	}
+	return "", false
+}
+
+// Prepare resolves the request with the given variables, variables that
+// aren't known are left in the output.
+func Prepare(request *RESTRequest, variables map[string]string) *PreparedRequest {
+	replace := func(input string) string {
+		return ReplaceVariables(input, variables)
+	}
+
+	prepared := &PreparedRequest{
+		Method:  strings.ToUpper(request.Method),
+		URL:     replace(request.Endpoint),
+		Headers: []KeyValue{},
+	}
+
+	if prepared.Method == "" {
+		prepared.Method = "GET"
+	}
+
+	query := []string{}
+	for _, param := range request.Params {
+		if !param.Active || param.Key == "" {
+			continue
+		}
+		query = append(query, queryEscape(replace(param.Key))+"="+queryEscape(replace(param.Value)))
+	}
+
+	for _, header := range request.Headers {
+		if !header.Active || header.Key == "" {
+			continue
+		}
+		prepared.Headers = append(prepared.Headers, KeyValue{
+			Key:    replace(header.Key),
+			Value:  replace(header.Value),
+			Active: true,
+		})
+	}
+
+	if request.Auth.IsActive() {
+		switch request.Auth.AuthType {
+		case AuthTypeBasic:
+			credentials := replace(request.Auth.Username) + ":" + replace(request.Auth.Password)
+			prepared.setHeader("Authorization", "Basic "+base64.StdEncoding.EncodeToString([]byte(credentials)))
+		case AuthTypeBearer, AuthTypeOAuth2:
+			prepared.setHeader("Authorization", "Bearer "+replace(request.Auth.Token))
+		case AuthTypeAPIKey:
+			if request.Auth.AddTo == "Query params" {
+				query = append(query, queryEscape(replace(request.Auth.Key))+"="+queryEscape(replace(request.Auth.Value)))
+			} else {
+				prepared.setHeader(replace(request.Auth.Key), replace(request.Auth.Value))
+			}
+		}
+	}
+
+	if len(query) > 0 {
+		separator := "?"
+		if strings.Contains(prepared.URL, "?") {
+			separator = "&"
+		}
+		prepared.URL += separator + strings.Join(query, "&")
+	}
+
+	if request.Body.ContentType != nil && *request.Body.ContentType != "" {
+		prepared.ContentType = *request.Body.ContentType
+
+		switch prepared.ContentType {
+		case ContentTypeMultipart:
+			prepared.FormData = []FormDataKeyValue{}
+			for _, field := range request.Body.FormData {
+				if !field.Active || field.Key == "" {
+					continue
+				}
+				field.Key = replace(field.Key)
+				if !field.IsFile {
+					field.Value = replace(field.Value)
+				}
+				prepared.FormData = append(prepared.FormData, field)
+			}
+		case ContentTypeForm:
+			values := []string{}
+			if request.Body.Raw != nil {
+				for _, field := range ParseRawKeyValue(*request.Body.Raw) {
+					if !field.Active || field.Key == "" {
+						continue
+					}
+					values = append(values, queryEscape(replace(field.Key))+"="+queryEscape(replace(field.Value)))
+				}
+			}
+			body := strings.Join(values, "&")
+			prepared.Body = &body
+		default:
+			body := ""
+			if request.Body.Raw != nil {
+				body = replace(*request.Body.Raw)
+			}
+			prepared.Body = &body
+		}
+
+		// The boundary of multipart bodies is set by the client.
+		if _, ok := prepared.Header("Content-Type"); !ok && prepared.ContentType != ContentTypeMultipart {
+			prepared.Headers = append(prepared.Headers, KeyValue{
+				Key:    "Content-Type",
+				Value:  prepared.ContentType,
+				Active: true,
+			})
+		}
+	}
+
+	return prepared
+}
+
+// setHeader replaces the header with the given name, or adds it.
+func (p *PreparedRequest) setHeader(name string, value string) {
+	for i := range p.Headers {
+		if strings.EqualFold(p.Headers[i].Key, name) {
+			p.Headers[i].Value = value
+			return
+		}
+	}
+	p.Headers = append(p.Headers, KeyValue{
+		Key:    name,
+		Value:  value,
+		Active: true,
+	})
+}
+
+// queryEscape escapes a query key or value, but keeps unresolved variables
+// readable.
+func queryEscape(input string) string {
+	output := ""
+	last := 0
+	for _, match := range VariablePattern.FindAllStringIndex(input, -1) {
+		output += url.QueryEscape(input[last:match[0]]) + input[match[0]:match[1]]
+		last = match[1]
+	}
+	return output + url.QueryEscape(input[last:])
+}
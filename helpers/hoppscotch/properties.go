@@ -0,0 +1,139 @@
+package hoppscotch
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// CollectionProperties are the defaults a collection passes on to its child
+// collections and requests.
+type CollectionProperties struct {
+	Headers   []KeyValue            `json:"headers"`
+	Auth      Auth                  `json:"auth"`
+	Variables []EnvironmentVariable `json:"variables"`
+}
+
+// NewCollectionProperties returns empty properties that inherit everything.
+func NewCollectionProperties() CollectionProperties {
+	return CollectionProperties{
+		Headers: []KeyValue{},
+		Auth: Auth{
+			AuthType:   AuthTypeInherit,
+			AuthActive: true,
+		},
+		Variables: []EnvironmentVariable{},
+	}
+}
+
+// ParseCollectionProperties parses the JSON string of stored properties. An
+// empty string are empty properties.
+func ParseCollectionProperties(data string) (CollectionProperties, error) {
+	properties := NewCollectionProperties()
+	if data == "" {
+		return properties, nil
+	}
+
+	if err := json.Unmarshal([]byte(data), &properties); err != nil {
+		return properties, err
+	}
+
+	properties.normalize()
+
+	return properties, nil
+}
+
+func (p *CollectionProperties) normalize() {
+	if p.Headers == nil {
+		p.Headers = []KeyValue{}
+	}
+	if p.Variables == nil {
+		p.Variables = []EnvironmentVariable{}
+	}
+	if p.Auth.AuthType == "" {
+		p.Auth.AuthType = AuthTypeInherit
+	}
+}
+
+// JSON returns the properties as a JSON string, ready to be stored.
+func (p CollectionProperties) JSON() (string, error) {
+	p.normalize()
+	propertiesJSON, err := json.Marshal(p)
+	if err != nil {
+		return "", err
+	}
+	return string(propertiesJSON), nil
+}
+
+// Inherit returns the properties of the child applied on top of these
+// properties. Headers and variables of the child override the ones with the
+// same name, the auth is only used when the child doesn't inherit it.
+func (p CollectionProperties) Inherit(child CollectionProperties) CollectionProperties {
+	output := NewCollectionProperties()
+
+	output.Headers = append(output.Headers, p.Headers...)
+	for _, header := range child.Headers {
+		output.Headers = setKeyValue(output.Headers, header)
+	}
+
+	output.Auth = p.Auth
+	if child.Auth.AuthType != "" && child.Auth.AuthType != AuthTypeInherit {
+		output.Auth = child.Auth
+	}
+
+	variables := append([]EnvironmentVariable{}, p.Variables...)
+	for _, variable := range child.Variables {
+		found := false
+		for i := range variables {
+			if variables[i].Key == variable.Key {
+				variables[i] = variable
+				found = true
+				break
+			}
+		}
+		if !found {
+			variables = append(variables, variable)
+		}
+	}
+	output.Variables = variables
+
+	return output
+}
+
+// ApplyTo adds the inherited headers the request doesn't set itself, and the
+// inherited auth when the request inherits its auth.
+func (p CollectionProperties) ApplyTo(request *RESTRequest) {
+	headers := []KeyValue{}
+	for _, header := range p.Headers {
+		if !header.Active {
+			continue
+		}
+
+		overridden := false
+		for i := range request.Headers {
+			if request.Headers[i].Active && strings.EqualFold(request.Headers[i].Key, header.Key) {
+				overridden = true
+				break
+			}
+		}
+		if !overridden {
+			headers = append(headers, header)
+		}
+	}
+	request.Headers = append(headers, request.Headers...)
+
+	if request.Auth.AuthType == AuthTypeInherit {
+		request.Auth = p.Auth
+	}
+}
+
+// setKeyValue replaces the entry with the same key (case insensitive), or
+// adds it.
+func setKeyValue(list []KeyValue, keyValue KeyValue) []KeyValue {
+	for i := range list {
+		if strings.EqualFold(list[i].Key, keyValue.Key) {
+			list[i] = keyValue
+			return list
+		}
+	}
+	return append(list, keyValue)
+}
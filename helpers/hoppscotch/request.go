@@ -0,0 +1,301 @@
+package hoppscotch
+
+import (
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// RESTRequestVersion is the version of the REST request document this
+// backend reads and writes.
+const RESTRequestVersion = "1"
+
+// Version is the "v" field of a Hoppscotch document. Older documents store it
+// as a number, newer ones as a string, so we accept both.
+type Version string
+
+func (v *Version) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		*v = ""
+		return nil
+	}
+
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		*v = Version(s)
+		return nil
+	}
+
+	var n json.Number
+	if err := json.Unmarshal(data, &n); err != nil {
+		return errors.New("version must be a string or a number")
+	}
+	*v = Version(n.String())
+	return nil
+}
+
+// Int returns the version as a number, legacy documents without a version
+// are version 0.
+func (v Version) Int() int {
+	n, err := strconv.Atoi(string(v))
+	if err != nil {
+		return 0
+	}
+	return n
+}
+
+type KeyValue struct {
+	Key    string `json:"key"`
+	Value  string `json:"value"`
+	Active bool   `json:"active"`
+}
+
+type FormDataKeyValue struct {
+	Key    string `json:"key"`
+	Value  string `json:"value"`
+	Active bool   `json:"active"`
+	IsFile bool   `json:"isFile"`
+}
+
+const (
+	AuthTypeNone    = "none"
+	AuthTypeBasic   = "basic"
+	AuthTypeBearer  = "bearer"
+	AuthTypeOAuth2  = "oauth-2"
+	AuthTypeAPIKey  = "api-key"
+	AuthTypeInherit = "inherit"
+)
+
+type Auth struct {
+	AuthType   string `json:"authType"`
+	AuthActive bool   `json:"authActive"`
+
+	// Basic
+	Username string `json:"username,omitempty"`
+	Password string `json:"password,omitempty"`
+
+	// Bearer and OAuth 2
+	Token string `json:"token,omitempty"`
+
+	// OAuth 2
+	OIDCDiscoveryURL string `json:"oidcDiscoveryURL,omitempty"`
+	AuthURL          string `json:"authURL,omitempty"`
+	AccessTokenURL   string `json:"accessTokenURL,omitempty"`
+	ClientID         string `json:"clientID,omitempty"`
+	Scope            string `json:"scope,omitempty"`
+
+	// API key
+	Key   string `json:"key,omitempty"`
+	Value string `json:"value,omitempty"`
+	AddTo string `json:"addTo,omitempty"`
+}
+
+// IsActive returns whether the auth config should be applied to a request.
+func (a Auth) IsActive() bool {
+	return a.AuthActive && a.AuthType != "" && a.AuthType != AuthTypeNone && a.AuthType != AuthTypeInherit
+}
+
+const (
+	ContentTypeJSON       = "application/json"
+	ContentTypeLDJSON     = "application/ld+json"
+	ContentTypeHALJSON    = "application/hal+json"
+	ContentTypeVNDAPIJSON = "application/vnd.api+json"
+	ContentTypeXML        = "application/xml"
+	ContentTypeForm       = "application/x-www-form-urlencoded"
+	ContentTypeMultipart  = "multipart/form-data"
+	ContentTypeHTML       = "text/html"
+	ContentTypePlain      = "text/plain"
+)
+
+// Body is the body of a REST request. For multipart/form-data the body is a
+// list of form fields, for every other content type it's a raw string.
+type Body struct {
+	ContentType *string
+	Raw         *string
+	FormData    []FormDataKeyValue
+}
+
+func (b *Body) UnmarshalJSON(data []byte) error {
+	body := struct {
+		ContentType *string         `json:"contentType"`
+		Body        json.RawMessage `json:"body"`
+	}{}
+	if err := json.Unmarshal(data, &body); err != nil {
+		return err
+	}
+
+	b.ContentType = body.ContentType
+	b.Raw = nil
+	b.FormData = nil
+
+	if len(body.Body) == 0 || string(body.Body) == "null" {
+		return nil
+	}
+
+	if body.ContentType != nil && *body.ContentType == ContentTypeMultipart {
+		return json.Unmarshal(body.Body, &b.FormData)
+	}
+
+	var raw string
+	if err := json.Unmarshal(body.Body, &raw); err != nil {
+		return err
+	}
+	b.Raw = &raw
+	return nil
+}
+
+func (b Body) MarshalJSON() ([]byte, error) {
+	body := struct {
+		ContentType *string     `json:"contentType"`
+		Body        interface{} `json:"body"`
+	}{
+		ContentType: b.ContentType,
+	}
+
+	if b.ContentType != nil && *b.ContentType == ContentTypeMultipart {
+		formData := b.FormData
+		if formData == nil {
+			formData = []FormDataKeyValue{}
+		}
+		body.Body = formData
+	} else if b.Raw != nil {
+		body.Body = *b.Raw
+	}
+
+	return json.Marshal(body)
+}
+
+// RESTRequest is the document the Hoppscotch frontend stores in
+// TeamRequest.Request.
+type RESTRequest struct {
+	Version          Version    `json:"v"`
+	Name             string     `json:"name"`
+	Method           string     `json:"method"`
+	Endpoint         string     `json:"endpoint"`
+	Params           []KeyValue `json:"params"`
+	Headers          []KeyValue `json:"headers"`
+	PreRequestScript string     `json:"preRequestScript"`
+	TestScript       string     `json:"testScript"`
+	Auth             Auth       `json:"auth"`
+	Body             Body       `json:"body"`
+}
+
+// NewRESTRequest creates an empty request with the same defaults the
+// frontend uses.
+func NewRESTRequest(name, method, endpoint string) *RESTRequest {
+	return &RESTRequest{
+		Version:  RESTRequestVersion,
+		Name:     name,
+		Method:   method,
+		Endpoint: endpoint,
+		Params:   []KeyValue{},
+		Headers:  []KeyValue{},
+		Auth: Auth{
+			AuthType:   AuthTypeNone,
+			AuthActive: true,
+		},
+	}
+}
+
+// SetRawBody sets a raw body with the given content type.
+func (r *RESTRequest) SetRawBody(contentType string, body string) {
+	r.Body = Body{
+		ContentType: &contentType,
+		Raw:         &body,
+	}
+}
+
+// SetFormDataBody sets a multipart/form-data body.
+func (r *RESTRequest) SetFormDataBody(fields []FormDataKeyValue) {
+	contentType := ContentTypeMultipart
+	r.Body = Body{
+		ContentType: &contentType,
+		FormData:    fields,
+	}
+}
+
+// ParseRESTRequest parses the JSON string of a stored request.
+func ParseRESTRequest(data string) (*RESTRequest, error) {
+	request := &RESTRequest{}
+	if err := json.Unmarshal([]byte(data), request); err != nil {
+		return nil, err
+	}
+	return request, nil
+}
+
+// ParseRESTRequestMap parses a request that has already been decoded into a
+// map, like the requests in a JSON export.
+func ParseRESTRequestMap(data map[string]interface{}) (*RESTRequest, error) {
+	requestJSON, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+	return ParseRESTRequest(string(requestJSON))
+}
+
+// JSON returns the request as a JSON string, ready to be stored.
+func (r *RESTRequest) JSON() (string, error) {
+	requestJSON, err := json.Marshal(r)
+	if err != nil {
+		return "", err
+	}
+	return string(requestJSON), nil
+}
+
+// Map returns the request as a generic map, like the requests in a JSON
+// export.
+func (r *RESTRequest) Map() (map[string]interface{}, error) {
+	requestJSON, err := json.Marshal(r)
+	if err != nil {
+		return nil, err
+	}
+
+	output := map[string]interface{}{}
+	if err := json.Unmarshal(requestJSON, &output); err != nil {
+		return nil, err
+	}
+	return output, nil
+}
+
+// ParseRawKeyValue parses the "key: value" lines the frontend uses to store
+// url encoded bodies. Lines starting with a # are disabled entries.
+func ParseRawKeyValue(input string) []KeyValue {
+	output := []KeyValue{}
+	for _, line := range strings.Split(input, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+
+		active := true
+		if strings.HasPrefix(line, "#") {
+			active = false
+			line = strings.TrimSpace(line[1:])
+		}
+
+		parts := strings.SplitN(line, ":", 2)
+		keyValue := KeyValue{
+			Key:    strings.TrimSpace(parts[0]),
+			Active: active,
+		}
+		if len(parts) == 2 {
+			keyValue.Value = strings.TrimSpace(parts[1])
+		}
+		output = append(output, keyValue)
+	}
+	return output
+}
+
+// RawKeyValue is the inverse of ParseRawKeyValue.
+func RawKeyValue(input []KeyValue) string {
+	lines := []string{}
+	for i := range input {
+		line := input[i].Key + ": " + input[i].Value
+		if !input[i].Active {
+			line = "# " + line
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n")
+}
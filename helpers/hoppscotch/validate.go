@@ -0,0 +1,488 @@
+package hoppscotch
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const (
+	RequestKindREST    = "rest"
+	RequestKindGraphQL = "graphql"
+)
+
+var knownAuthTypes = []string{
+	AuthTypeNone,
+	AuthTypeBasic,
+	AuthTypeBearer,
+	AuthTypeOAuth2,
+	AuthTypeAPIKey,
+	AuthTypeInherit,
+}
+
+// FieldError is a problem with a single field of a request document. The
+// field is a path like headers[0].key, empty for the document itself.
+type FieldError struct {
+	Field   string
+	Message string
+}
+
+// ValidationError contains all problems found in a request document.
+type ValidationError struct {
+	Errors []FieldError
+}
+
+func (e *ValidationError) Error() string {
+	messages := []string{}
+	for _, fieldError := range e.Errors {
+		if fieldError.Field == "" {
+			messages = append(messages, fieldError.Message)
+		} else {
+			messages = append(messages, fieldError.Field+": "+fieldError.Message)
+		}
+	}
+	return "invalid request: " + strings.Join(messages, ", ")
+}
+
+// Prefix returns the error with the given path in front of every field, for
+// requests that are part of a bigger document.
+func (e *ValidationError) Prefix(path string) *ValidationError {
+	output := &ValidationError{}
+	for _, fieldError := range e.Errors {
+		field := path
+		if fieldError.Field != "" {
+			field += "." + fieldError.Field
+		}
+		output.Errors = append(output.Errors, FieldError{Field: field, Message: fieldError.Message})
+	}
+	return output
+}
+
+// NormalizedRequest is a validated request document.
+type NormalizedRequest struct {
+	Kind string
+	Name string
+	JSON string
+}
+
+// NormalizeRequest migrates and validates a REST or GraphQL request document
+// and returns it in the current version, with all fields set. Fields that are unknown to
+// this version are kept as they are. When the document has no name, the
+// default name is used.
+func NormalizeRequest(data string, defaultName string) (*NormalizedRequest, error) {
+	document := map[string]interface{}{}
+	decoder := json.NewDecoder(strings.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(&document); err != nil {
+		return nil, &ValidationError{Errors: []FieldError{{Message: "the request must be a JSON object"}}}
+	}
+
+	switch name := document["name"].(type) {
+	case nil:
+		document["name"] = defaultName
+	case string:
+		if strings.TrimSpace(name) == "" {
+			document["name"] = defaultName
+		}
+	}
+
+	// Older documents are upgraded first, so they are validated against the
+	// current version.
+	changed, err := MigrateRequest(document)
+	if err != nil {
+		return nil, &ValidationError{Errors: []FieldError{{Field: "v", Message: err.Error()}}}
+	}
+	if changed {
+		migratedJSON, err := json.Marshal(document)
+		if err != nil {
+			return nil, err
+		}
+		data = string(migratedJSON)
+	}
+
+	v := &validator{}
+	kind := requestKind(document)
+	switch kind {
+	case RequestKindREST:
+		v.version(document, RESTRequestVersion)
+		v.requiredString(document, "name", true)
+		v.requiredString(document, "method", true)
+		v.method(document)
+		v.requiredString(document, "endpoint", false)
+		v.keyValues(document, "params")
+		v.keyValues(document, "headers")
+		v.optionalString(document, "preRequestScript")
+		v.optionalString(document, "testScript")
+		v.auth(document)
+		v.body(document)
+	case RequestKindGraphQL:
+		v.version(document, GraphQLRequestVersion)
+		v.requiredString(document, "name", true)
+		v.requiredString(document, "url", false)
+		v.keyValues(document, "headers")
+		v.optionalString(document, "query")
+		v.optionalString(document, "variables")
+		v.auth(document)
+	default:
+		v.add("", "the request is not a REST or GraphQL request")
+	}
+
+	if len(v.errors) > 0 {
+		return nil, &ValidationError{Errors: v.errors}
+	}
+
+	normalized := &NormalizedRequest{Kind: kind}
+	var known interface{}
+	switch kind {
+	case RequestKindREST:
+		request, err := ParseRESTRequest(data)
+		if err != nil {
+			return nil, err
+		}
+		request.Name = document["name"].(string)
+		request.normalize()
+		normalized.Name = request.Name
+		known = request
+	case RequestKindGraphQL:
+		request := &GraphQLRequest{}
+		if err := json.Unmarshal([]byte(data), request); err != nil {
+			return nil, err
+		}
+		request.Name = document["name"].(string)
+		request.normalize()
+		normalized.Name = request.Name
+		known = request
+	}
+
+	knownJSON, err := json.Marshal(known)
+	if err != nil {
+		return nil, err
+	}
+	knownFields := map[string]json.RawMessage{}
+	if err := json.Unmarshal(knownJSON, &knownFields); err != nil {
+		return nil, err
+	}
+	for key, value := range document {
+		if _, ok := knownFields[key]; ok {
+			continue
+		}
+		unknownJSON, err := json.Marshal(value)
+		if err != nil {
+			return nil, err
+		}
+		knownFields[key] = unknownJSON
+	}
+
+	output, err := json.Marshal(knownFields)
+	if err != nil {
+		return nil, err
+	}
+	normalized.JSON = string(output)
+
+	return normalized, nil
+}
+
+// requestKind detects the kind of request by its fields.
+func requestKind(document map[string]interface{}) string {
+	if _, ok := document["endpoint"]; ok {
+		return RequestKindREST
+	}
+	if _, ok := document["method"]; ok {
+		return RequestKindREST
+	}
+	if _, ok := document["query"]; ok {
+		return RequestKindGraphQL
+	}
+	if _, ok := document["url"]; ok {
+		return RequestKindGraphQL
+	}
+	return ""
+}
+
+func (r *RESTRequest) normalize() {
+	r.Version = RESTRequestVersion
+	r.Name = strings.TrimSpace(r.Name)
+	r.Method = strings.ToUpper(strings.TrimSpace(r.Method))
+	r.Endpoint = strings.TrimSpace(r.Endpoint)
+	if r.Params == nil {
+		r.Params = []KeyValue{}
+	}
+	if r.Headers == nil {
+		r.Headers = []KeyValue{}
+	}
+	r.Auth.normalize()
+	if r.Body.ContentType != nil && *r.Body.ContentType == "" {
+		r.Body.ContentType = nil
+	}
+	if r.Body.ContentType == nil {
+		r.Body.Raw = nil
+		r.Body.FormData = nil
+	}
+}
+
+func (r *GraphQLRequest) normalize() {
+	r.Version = GraphQLRequestVersion
+	r.Name = strings.TrimSpace(r.Name)
+	r.URL = strings.TrimSpace(r.URL)
+	if r.Headers == nil {
+		r.Headers = []KeyValue{}
+	}
+	r.Auth.normalize()
+}
+
+// normalize sets the defaults the frontend uses for documents without auth.
+func (a *Auth) normalize() {
+	if a.AuthType == "" {
+		a.AuthType = AuthTypeNone
+		a.AuthActive = true
+	}
+}
+
+type validator struct {
+	errors []FieldError
+}
+
+func (v *validator) add(field string, message string) {
+	v.errors = append(v.errors, FieldError{Field: field, Message: message})
+}
+
+func (v *validator) version(document map[string]interface{}, current string) {
+	value, ok := document["v"]
+	if !ok || value == nil {
+		return
+	}
+
+	var version string
+	switch typed := value.(type) {
+	case string:
+		version = typed
+	case json.Number:
+		version = typed.String()
+	default:
+		v.add("v", "must be a string or a number")
+		return
+	}
+
+	number, err := strconv.Atoi(version)
+	if err != nil {
+		v.add("v", "must be a version number")
+		return
+	}
+
+	currentNumber, _ := strconv.Atoi(current)
+	if number > currentNumber {
+		v.add("v", fmt.Sprintf("version %d is not supported, the latest supported version is %d", number, currentNumber))
+	}
+}
+
+// method checks that the method is an HTTP token (RFC 7230), a method with
+// spaces or separators can't be sent and could end up in generated code.
+func (v *validator) method(document map[string]interface{}) {
+	method, ok := document["method"].(string)
+	if !ok {
+		return
+	}
+	for _, r := range strings.TrimSpace(method) {
+		if !isTokenChar(r) {
+			v.add("method", "must be an HTTP method, without spaces or separators")
+			return
+		}
+	}
+}
+
+func isTokenChar(r rune) bool {
+	switch {
+	case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+		return true
+	}
+	return strings.ContainsRune("!#$%&'*+-.^_`|~", r)
+}
+
+func (v *validator) requiredString(object map[string]interface{}, field string, notEmpty bool) {
+	v.requiredStringAt(object, field, field, notEmpty)
+}
+
+func (v *validator) requiredStringAt(object map[string]interface{}, key string, path string, notEmpty bool) {
+	value, ok := object[key]
+	if !ok || value == nil {
+		v.add(path, "is required")
+		return
+	}
+	text, ok := value.(string)
+	if !ok {
+		v.add(path, "must be a string")
+		return
+	}
+	if notEmpty && strings.TrimSpace(text) == "" {
+		v.add(path, "must not be empty")
+	}
+}
+
+func (v *validator) optionalString(object map[string]interface{}, field string) {
+	v.optionalStringAt(object, field, field)
+}
+
+func (v *validator) optionalStringAt(object map[string]interface{}, key string, path string) {
+	value, ok := object[key]
+	if !ok || value == nil {
+		return
+	}
+	if _, ok := value.(string); !ok {
+		v.add(path, "must be a string")
+	}
+}
+
+func (v *validator) optionalBoolAt(object map[string]interface{}, key string, path string) {
+	value, ok := object[key]
+	if !ok || value == nil {
+		return
+	}
+	if _, ok := value.(bool); !ok {
+		v.add(path, "must be a boolean")
+	}
+}
+
+type listObject struct {
+	path   string
+	object map[string]interface{}
+}
+
+// objectList returns the objects in the list at the given field, and adds an
+// error for everything else.
+func (v *validator) objectList(list interface{}, path string) []listObject {
+	items, ok := list.([]interface{})
+	if !ok {
+		v.add(path, "must be a list")
+		return nil
+	}
+
+	objects := []listObject{}
+	for i, item := range items {
+		itemPath := path + "[" + strconv.Itoa(i) + "]"
+		object, ok := item.(map[string]interface{})
+		if !ok {
+			v.add(itemPath, "must be an object")
+			continue
+		}
+		objects = append(objects, listObject{path: itemPath, object: object})
+	}
+	return objects
+}
+
+func (v *validator) keyValues(document map[string]interface{}, field string) {
+	value, ok := document[field]
+	if !ok || value == nil {
+		return
+	}
+
+	for _, item := range v.objectList(value, field) {
+		v.requiredStringAt(item.object, "key", item.path+".key", false)
+		v.optionalStringAt(item.object, "value", item.path+".value")
+		v.optionalBoolAt(item.object, "active", item.path+".active")
+	}
+}
+
+func (v *validator) auth(document map[string]interface{}) {
+	value, ok := document["auth"]
+	if !ok || value == nil {
+		return
+	}
+
+	auth, ok := value.(map[string]interface{})
+	if !ok {
+		v.add("auth", "must be an object")
+		return
+	}
+
+	v.requiredStringAt(auth, "authType", "auth.authType", true)
+	if authType, ok := auth["authType"].(string); ok && authType != "" && !contains(knownAuthTypes, authType) {
+		v.add("auth.authType", "must be one of "+strings.Join(knownAuthTypes, ", "))
+	}
+	v.optionalBoolAt(auth, "authActive", "auth.authActive")
+	for _, key := range []string{"username", "password", "token", "oidcDiscoveryURL", "authURL", "accessTokenURL", "clientID", "scope", "key", "value", "addTo"} {
+		v.optionalStringAt(auth, key, "auth."+key)
+	}
+}
+
+func (v *validator) body(document map[string]interface{}) {
+	value, ok := document["body"]
+	if !ok || value == nil {
+		return
+	}
+
+	body, ok := value.(map[string]interface{})
+	if !ok {
+		v.add("body", "must be an object")
+		return
+	}
+
+	contentType := ""
+	if value, ok := body["contentType"]; ok && value != nil {
+		contentType, ok = value.(string)
+		if !ok {
+			v.add("body.contentType", "must be a string")
+			return
+		}
+		if contentType != "" && !contains(knownContentTypes, contentType) {
+			v.add("body.contentType", "must be one of "+strings.Join(knownContentTypes, ", "))
+			return
+		}
+	}
+
+	content, ok := body["body"]
+	if !ok || content == nil {
+		return
+	}
+
+	if contentType == ContentTypeMultipart {
+		for _, item := range v.objectList(content, "body.body") {
+			v.requiredStringAt(item.object, "key", item.path+".key", false)
+			v.optionalStringAt(item.object, "value", item.path+".value")
+			v.optionalBoolAt(item.object, "active", item.path+".active")
+			v.optionalBoolAt(item.object, "isFile", item.path+".isFile")
+		}
+		return
+	}
+
+	if _, ok := content.(string); !ok {
+		v.add("body.body", "must be a string")
+	}
+}
+
+func contains(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
+
+// Rename sets the name of the normalized request.
+func (n *NormalizedRequest) Rename(name string) error {
+	document := map[string]json.RawMessage{}
+	if err := json.Unmarshal([]byte(n.JSON), &document); err != nil {
+		return err
+	}
+
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return &ValidationError{Errors: []FieldError{{Field: "name", Message: "must not be empty"}}}
+	}
+
+	nameJSON, err := json.Marshal(name)
+	if err != nil {
+		return err
+	}
+	document["name"] = nameJSON
+
+	output, err := json.Marshal(document)
+	if err != nil {
+		return err
+	}
+
+	n.Name = name
+	n.JSON = string(output)
+	return nil
+}
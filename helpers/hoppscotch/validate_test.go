@@ -0,0 +1,199 @@
+package hoppscotch
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func jsonString(value string) string {
+	output, _ := json.Marshal(value)
+	return string(output)
+}
+
+func TestNormalizeRequestMethod(t *testing.T) {
+	tests := []struct {
+		method string
+		valid  bool
+	}{
+		{method: "GET", valid: true},
+		{method: " post ", valid: true},
+		{method: "PROPFIND", valid: true},
+		{method: "M-SEARCH", valid: true},
+		{method: "", valid: false},
+		{method: "GET;CURL HTTP://X|SH", valid: false},
+		{method: "GET POST", valid: false},
+		{method: "GET\n", valid: true},
+		{method: "GET\nX", valid: false},
+		{method: "G\"ET", valid: false},
+		{method: "GÉT", valid: false},
+	}
+
+	for _, test := range tests {
+		_, err := NormalizeRequest(`{"v": "1", "name": "a", "endpoint": "https://example.com", "method": `+jsonString(test.method)+`}`, "")
+		if test.valid && err != nil {
+			t.Errorf("%q: unexpected error %v", test.method, err)
+		}
+		if !test.valid {
+			var validationErr *ValidationError
+			if !errors.As(err, &validationErr) || validationErr.Errors[0].Field != "method" {
+				t.Errorf("%q: expected a method validation error, got %v", test.method, err)
+			}
+		}
+	}
+}
+
+// decodeJSON decodes a normalized document for comparison.
+func decodeJSON(t *testing.T, data string) map[string]interface{} {
+	document := map[string]interface{}{}
+	if err := json.Unmarshal([]byte(data), &document); err != nil {
+		t.Fatalf("%s: %v", data, err)
+	}
+	return document
+}
+
+func TestNormalizeRequestREST(t *testing.T) {
+	normalized, err := NormalizeRequest(`{"v": 1, "name": " Users ", "method": " get ", "endpoint": " https://example.com ", "body": {"contentType": "", "body": "ignored"}, "x-custom": [1, 2]}`, "Untitled")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if normalized.Kind != RequestKindREST || normalized.Name != "Users" {
+		t.Errorf("kind = %q, name = %q", normalized.Kind, normalized.Name)
+	}
+
+	document := decodeJSON(t, normalized.JSON)
+	want := map[string]interface{}{
+		"v":        "1",
+		"name":     "Users",
+		"method":   "GET",
+		"endpoint": "https://example.com",
+		"params":   []interface{}{},
+		"headers":  []interface{}{},
+		"auth":     map[string]interface{}{"authType": "none", "authActive": true},
+		"body":     map[string]interface{}{"contentType": nil, "body": nil},
+		"x-custom": []interface{}{float64(1), float64(2)},
+	}
+	for key, value := range want {
+		if !reflect.DeepEqual(document[key], value) {
+			t.Errorf("%s = %#v, want %#v", key, document[key], value)
+		}
+	}
+}
+
+func TestNormalizeRequestGraphQL(t *testing.T) {
+	normalized, err := NormalizeRequest(`{"url": "https://example.com/graphql", "query": "{ a }", "x-custom": true}`, "Untitled")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if normalized.Kind != RequestKindGraphQL || normalized.Name != "Untitled" {
+		t.Errorf("kind = %q, name = %q", normalized.Kind, normalized.Name)
+	}
+
+	document := decodeJSON(t, normalized.JSON)
+	if document["v"] != GraphQLRequestVersion || document["query"] != "{ a }" || document["x-custom"] != true {
+		t.Errorf("unexpected document %s", normalized.JSON)
+	}
+	if !reflect.DeepEqual(document["headers"], []interface{}{}) {
+		t.Errorf("headers = %#v, want an empty list", document["headers"])
+	}
+}
+
+func TestNormalizeRequestErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  string
+		fields []string
+	}{
+		{name: "not an object", input: `[]`, fields: []string{""}},
+		{name: "unknown kind", input: `{"name": "a"}`, fields: []string{""}},
+		{name: "missing endpoint", input: `{"v": "1", "name": "a", "method": "GET"}`, fields: []string{"endpoint"}},
+		{name: "empty method", input: `{"name": "a", "method": " ", "endpoint": ""}`, fields: []string{"method"}},
+		{name: "future version", input: `{"v": "2", "name": "a", "method": "GET", "endpoint": ""}`, fields: []string{"v"}},
+		{
+			name:   "key values",
+			input:  `{"name": "a", "method": "GET", "endpoint": "", "v": "1", "headers": [{"value": 1, "active": "yes"}, "x"], "params": {}}`,
+			fields: []string{"params", "headers[1]", "headers[0].key", "headers[0].value", "headers[0].active"},
+		},
+		{
+			name:   "auth",
+			input:  `{"name": "a", "method": "GET", "endpoint": "", "v": "1", "auth": {"authType": "magic", "token": 1}}`,
+			fields: []string{"auth.authType", "auth.token"},
+		},
+		{
+			name:   "body content type",
+			input:  `{"name": "a", "method": "GET", "endpoint": "", "v": "1", "body": {"contentType": "text/unknown", "body": ""}}`,
+			fields: []string{"body.contentType"},
+		},
+		{
+			name:   "raw body",
+			input:  `{"name": "a", "method": "GET", "endpoint": "", "v": "1", "body": {"contentType": "application/json", "body": {}}}`,
+			fields: []string{"body.body"},
+		},
+		{
+			name:   "multipart body",
+			input:  `{"name": "a", "method": "GET", "endpoint": "", "v": "1", "body": {"contentType": "multipart/form-data", "body": [{"key": "a", "isFile": "no"}]}}`,
+			fields: []string{"body.body[0].isFile"},
+		},
+		{
+			name:   "GraphQL",
+			input:  `{"v": "2", "name": "a", "url": 1, "query": 2}`,
+			fields: []string{"url", "query"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := NormalizeRequest(test.input, "Untitled")
+			var validationErr *ValidationError
+			if !errors.As(err, &validationErr) {
+				t.Fatalf("expected a ValidationError, got %v", err)
+			}
+			fields := []string{}
+			for _, fieldError := range validationErr.Errors {
+				fields = append(fields, fieldError.Field)
+			}
+			if !reflect.DeepEqual(fields, test.fields) {
+				t.Errorf("fields = %q, want %q (%v)", fields, test.fields, err)
+			}
+		})
+	}
+}
+
+func TestValidationError(t *testing.T) {
+	err := &ValidationError{Errors: []FieldError{
+		{Message: "the request must be a JSON object"},
+		{Field: "headers[0].key", Message: "is required"},
+	}}
+
+	if err.Error() != "invalid request: the request must be a JSON object, headers[0].key: is required" {
+		t.Errorf("unexpected message %q", err.Error())
+	}
+
+	prefixed := err.Prefix("requests[2]")
+	if prefixed.Errors[0].Field != "requests[2]" || prefixed.Errors[1].Field != "requests[2].headers[0].key" {
+		t.Errorf("unexpected fields %+v", prefixed.Errors)
+	}
+}
+
+func TestNormalizedRequestRename(t *testing.T) {
+	normalized, err := NormalizeRequest(`{"name": "a", "method": "GET", "endpoint": "https://example.com", "x-custom": 1}`, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := normalized.Rename("  b  "); err != nil {
+		t.Fatal(err)
+	}
+	document := decodeJSON(t, normalized.JSON)
+	if normalized.Name != "b" || document["name"] != "b" || document["x-custom"] != float64(1) {
+		t.Errorf("unexpected rename result %q %s", normalized.Name, normalized.JSON)
+	}
+
+	var validationErr *ValidationError
+	if err := normalized.Rename(" "); !errors.As(err, &validationErr) {
+		t.Errorf("expected a ValidationError for an empty name, got %v", err)
+	}
+}
@@ -0,0 +1,253 @@
+package insomnia
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/jerbob92/hoppscotch-backend/helpers/hoppscotch"
+)
+
+// ExportFormat is the version of the Insomnia export format we can read.
+const ExportFormat = 4
+
+const (
+	ResourceTypeWorkspace    = "workspace"
+	ResourceTypeRequestGroup = "request_group"
+	ResourceTypeRequest      = "request"
+)
+
+// Export is an Insomnia v4 export, every workspace, folder and request is a
+// resource that points to its parent.
+type Export struct {
+	Type         string     `json:"_type"`
+	ExportFormat int        `json:"__export_format"`
+	Resources    []Resource `json:"resources"`
+}
+
+// Resource contains the fields of all resource types we import, the other
+// resource types (environments, cookie jars, gRPC requests...) are ignored.
+type Resource struct {
+	ID          string    `json:"_id"`
+	Type        string    `json:"_type"`
+	ParentID    *string   `json:"parentId"`
+	Name        string    `json:"name"`
+	MetaSortKey float64   `json:"metaSortKey"`
+	Method      string    `json:"method"`
+	URL         string    `json:"url"`
+	Body        Body      `json:"body"`
+	Parameters  []Pair    `json:"parameters"`
+	Headers     []Pair    `json:"headers"`
+	Auth        *AuthData `json:"authentication"`
+}
+
+type Pair struct {
+	Name     string `json:"name"`
+	Value    string `json:"value"`
+	Disabled bool   `json:"disabled"`
+	Type     string `json:"type"`
+	FileName string `json:"fileName"`
+}
+
+type Body struct {
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text"`
+	Params   []Pair `json:"params"`
+}
+
+type AuthData struct {
+	Type     string `json:"type"`
+	Disabled bool   `json:"disabled"`
+
+	// Basic
+	Username string `json:"username"`
+	Password string `json:"password"`
+
+	// Bearer
+	Token  string `json:"token"`
+	Prefix string `json:"prefix"`
+
+	// API key
+	Key   string `json:"key"`
+	Value string `json:"value"`
+	AddTo string `json:"addTo"`
+
+	// OAuth 2
+	AuthorizationURL string `json:"authorizationUrl"`
+	AccessTokenURL   string `json:"accessTokenUrl"`
+	ClientID         string `json:"clientId"`
+	Scope            string `json:"scope"`
+}
+
+// variablePattern matches the Nunjucks variables Insomnia uses, like
+// {{ _.baseUrl }} or {{baseUrl}}.
+var variablePattern = regexp.MustCompile(`{{\s*(?:_\.)?([A-Za-z0-9_\-.]+)\s*}}`)
+
+// replaceVariables converts Insomnia variables to Hoppscotch variables.
+func replaceVariables(input string) string {
+	return variablePattern.ReplaceAllString(input, "<<$1>>")
+}
+
+// Import converts every workspace in the export to a collection.
+func Import(data []byte) ([]hoppscotch.Collection, error) {
+	export := &Export{}
+	if err := json.Unmarshal(data, export); err != nil {
+		return nil, fmt.Errorf("could not read Insomnia export: %w", err)
+	}
+
+	if export.Type != "export" || export.ExportFormat != ExportFormat {
+		return nil, errors.New("document is not an Insomnia v4 export")
+	}
+
+	children := map[string][]Resource{}
+	workspaces := []Resource{}
+	for _, resource := range export.Resources {
+		if resource.Type == ResourceTypeWorkspace {
+			workspaces = append(workspaces, resource)
+			continue
+		}
+		if resource.ParentID != nil {
+			children[*resource.ParentID] = append(children[*resource.ParentID], resource)
+		}
+	}
+
+	for parentID := range children {
+		resources := children[parentID]
+		sort.SliceStable(resources, func(i, j int) bool {
+			return resources[i].MetaSortKey < resources[j].MetaSortKey
+		})
+	}
+
+	collections := []hoppscotch.Collection{}
+	for _, workspace := range workspaces {
+		collections = append(collections, collectionFromResource(workspace, children))
+	}
+
+	return collections, nil
+}
+
+func collectionFromResource(parent Resource, children map[string][]Resource) hoppscotch.Collection {
+	collection := hoppscotch.Collection{
+		Name:     parent.Name,
+		Folders:  []hoppscotch.Collection{},
+		Requests: []*hoppscotch.RESTRequest{},
+	}
+
+	for _, resource := range children[parent.ID] {
+		switch resource.Type {
+		case ResourceTypeRequestGroup:
+			collection.Folders = append(collection.Folders, collectionFromResource(resource, children))
+		case ResourceTypeRequest:
+			collection.Requests = append(collection.Requests, requestFromResource(resource))
+		}
+	}
+
+	return collection
+}
+
+func requestFromResource(resource Resource) *hoppscotch.RESTRequest {
+	method := strings.ToUpper(resource.Method)
+	if method == "" {
+		method = "GET"
+	}
+
+	request := hoppscotch.NewRESTRequest(resource.Name, method, replaceVariables(resource.URL))
+
+	for _, param := range resource.Parameters {
+		request.Params = append(request.Params, hoppscotch.KeyValue{
+			Key:    replaceVariables(param.Name),
+			Value:  replaceVariables(param.Value),
+			Active: !param.Disabled,
+		})
+	}
+
+	for _, header := range resource.Headers {
+		// The content type is part of the body in Hoppscotch.
+		if strings.EqualFold(header.Name, "Content-Type") && resource.Body.MimeType != "" {
+			continue
+		}
+		request.Headers = append(request.Headers, hoppscotch.KeyValue{
+			Key:    replaceVariables(header.Name),
+			Value:  replaceVariables(header.Value),
+			Active: !header.Disabled,
+		})
+	}
+
+	if resource.Body.MimeType != "" {
+		contentType := hoppscotch.ContentTypeFromMimeType(resource.Body.MimeType)
+		switch contentType {
+		case hoppscotch.ContentTypeMultipart:
+			fields := []hoppscotch.FormDataKeyValue{}
+			for _, param := range resource.Body.Params {
+				field := hoppscotch.FormDataKeyValue{
+					Key:    replaceVariables(param.Name),
+					Value:  replaceVariables(param.Value),
+					Active: !param.Disabled,
+				}
+				// Files can't be imported, the user has to select them again.
+				if param.Type == "file" {
+					field.Value = ""
+					field.IsFile = true
+				}
+				fields = append(fields, field)
+			}
+			request.SetFormDataBody(fields)
+		case hoppscotch.ContentTypeForm:
+			fields := []hoppscotch.KeyValue{}
+			for _, param := range resource.Body.Params {
+				fields = append(fields, hoppscotch.KeyValue{
+					Key:    replaceVariables(param.Name),
+					Value:  replaceVariables(param.Value),
+					Active: !param.Disabled,
+				})
+			}
+			request.SetRawBody(contentType, hoppscotch.RawKeyValue(fields))
+		default:
+			request.SetRawBody(contentType, replaceVariables(resource.Body.Text))
+		}
+	}
+
+	if resource.Auth != nil {
+		request.Auth = authFromResource(*resource.Auth)
+	}
+
+	return request
+}
+
+func authFromResource(auth AuthData) hoppscotch.Auth {
+	output := hoppscotch.Auth{
+		AuthType:   hoppscotch.AuthTypeNone,
+		AuthActive: !auth.Disabled,
+	}
+
+	switch auth.Type {
+	case "basic":
+		output.AuthType = hoppscotch.AuthTypeBasic
+		output.Username = replaceVariables(auth.Username)
+		output.Password = replaceVariables(auth.Password)
+	case "bearer":
+		output.AuthType = hoppscotch.AuthTypeBearer
+		output.Token = replaceVariables(auth.Token)
+	case "apikey":
+		output.AuthType = hoppscotch.AuthTypeAPIKey
+		output.Key = replaceVariables(auth.Key)
+		output.Value = replaceVariables(auth.Value)
+		output.AddTo = "Headers"
+		if auth.AddTo == "queryParams" {
+			output.AddTo = "Query params"
+		}
+	case "oauth2":
+		output.AuthType = hoppscotch.AuthTypeOAuth2
+		output.AuthURL = replaceVariables(auth.AuthorizationURL)
+		output.AccessTokenURL = replaceVariables(auth.AccessTokenURL)
+		output.ClientID = replaceVariables(auth.ClientID)
+		output.Scope = replaceVariables(auth.Scope)
+	default:
+		output.AuthActive = true
+	}
+
+	return output
+}
@@ -0,0 +1,110 @@
+package insomnia
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/jerbob92/hoppscotch-backend/helpers/hoppscotch"
+)
+
+// paths lists the folders and requests of the collection as paths.
+func paths(collection hoppscotch.Collection, prefix string) []string {
+	output := []string{}
+	for _, request := range collection.Requests {
+		output = append(output, prefix+collection.Name+": "+request.Name)
+	}
+	for _, folder := range collection.Folders {
+		output = append(output, paths(folder, prefix+collection.Name+"/")...)
+	}
+	return output
+}
+
+func TestImportNestedFolders(t *testing.T) {
+	tests := []struct {
+		name   string
+		export string
+		paths  []string
+	}{
+		{
+			name: "nested folders",
+			export: `{"_type": "export", "__export_format": 4, "resources": [
+				{"_id": "wrk_1", "_type": "workspace", "parentId": null, "name": "API"},
+				{"_id": "fld_users", "_type": "request_group", "parentId": "wrk_1", "name": "Users", "metaSortKey": -2},
+				{"_id": "fld_admin", "_type": "request_group", "parentId": "fld_users", "name": "Admin", "metaSortKey": 1},
+				{"_id": "fld_roles", "_type": "request_group", "parentId": "fld_admin", "name": "Roles"},
+				{"_id": "req_roles", "_type": "request", "parentId": "fld_roles", "name": "List roles", "url": "{{ _.baseUrl }}/roles"},
+				{"_id": "req_ban", "_type": "request", "parentId": "fld_admin", "name": "Ban user", "method": "post"},
+				{"_id": "req_me", "_type": "request", "parentId": "fld_users", "name": "Me", "metaSortKey": 0},
+				{"_id": "req_health", "_type": "request", "parentId": "wrk_1", "name": "Health", "metaSortKey": -1}]}`,
+			paths: []string{
+				"API: Health",
+				"API/Users: Me",
+				"API/Users/Admin: Ban user",
+				"API/Users/Admin/Roles: List roles",
+			},
+		},
+		{
+			name: "sort order",
+			export: `{"_type": "export", "__export_format": 4, "resources": [
+				{"_id": "wrk_1", "_type": "workspace", "name": "API"},
+				{"_id": "fld_b", "_type": "request_group", "parentId": "wrk_1", "name": "B", "metaSortKey": 2},
+				{"_id": "fld_a", "_type": "request_group", "parentId": "wrk_1", "name": "A", "metaSortKey": 1},
+				{"_id": "req_2", "_type": "request", "parentId": "fld_a", "name": "Second", "metaSortKey": 20},
+				{"_id": "req_1", "_type": "request", "parentId": "fld_a", "name": "First", "metaSortKey": 10},
+				{"_id": "req_3", "_type": "request", "parentId": "fld_b", "name": "Third"}]}`,
+			paths: []string{
+				"API/A: First",
+				"API/A: Second",
+				"API/B: Third",
+			},
+		},
+		{
+			name: "workspaces and other resources",
+			export: `{"_type": "export", "__export_format": 4, "resources": [
+				{"_id": "wrk_1", "_type": "workspace", "name": "One"},
+				{"_id": "wrk_2", "_type": "workspace", "name": "Two"},
+				{"_id": "env_1", "_type": "environment", "parentId": "wrk_1", "name": "Base"},
+				{"_id": "fld_1", "_type": "request_group", "parentId": "wrk_2", "name": "Folder"},
+				{"_id": "grpc_1", "_type": "grpc_request", "parentId": "fld_1", "name": "gRPC"},
+				{"_id": "req_1", "_type": "request", "parentId": "fld_1", "name": "Request"},
+				{"_id": "req_orphan", "_type": "request", "parentId": "fld_missing", "name": "Orphan"}]}`,
+			paths: []string{
+				"Two/Folder: Request",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		collections, err := Import([]byte(test.export))
+		if err != nil {
+			t.Errorf("%s: %v", test.name, err)
+			continue
+		}
+
+		got := []string{}
+		for _, collection := range collections {
+			got = append(got, paths(collection, "")...)
+		}
+		if !reflect.DeepEqual(got, test.paths) {
+			t.Errorf("%s: paths = %q, want %q", test.name, got, test.paths)
+		}
+	}
+
+	collections, err := Import([]byte(`{"_type": "export", "__export_format": 4, "resources": [
+		{"_id": "wrk_1", "_type": "workspace", "name": "API"},
+		{"_id": "fld_1", "_type": "request_group", "parentId": "wrk_1", "name": "Folder"},
+		{"_id": "fld_2", "_type": "request_group", "parentId": "fld_1", "name": "Nested"},
+		{"_id": "req_1", "_type": "request", "parentId": "fld_2", "name": "Get", "url": "{{ _.baseUrl }}/items"}]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if endpoint := collections[0].Folders[0].Folders[0].Requests[0].Endpoint; endpoint != "<<baseUrl>>/items" {
+		t.Errorf("endpoint = %q", endpoint)
+	}
+
+	for _, export := range []string{`{}`, `{"_type": "export", "__export_format": 3, "resources": []}`, `[]`} {
+		if _, err := Import([]byte(export)); err == nil {
+			t.Errorf("%s: expected an error", export)
+		}
+	}
+}
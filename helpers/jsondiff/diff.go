@@ -0,0 +1,117 @@
+package jsondiff
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	OperationAdd     = "add"
+	OperationRemove  = "remove"
+	OperationReplace = "replace"
+)
+
+// Change is a single difference between two JSON documents. The path is a
+// JSON pointer, the values are JSON encoded.
+type Change struct {
+	Operation string
+	Path      string
+	OldValue  *string
+	NewValue  *string
+}
+
+// Diff returns the changes needed to go from the old to the new JSON
+// document. Objects are compared key by key and arrays index by index, so a
+// change deep in the document is reported at its own path.
+func Diff(oldJSON []byte, newJSON []byte) ([]Change, error) {
+	var oldValue, newValue interface{}
+	if err := json.Unmarshal(oldJSON, &oldValue); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(newJSON, &newValue); err != nil {
+		return nil, err
+	}
+
+	return DiffValues(oldValue, newValue), nil
+}
+
+// DiffValues is Diff for already decoded JSON values.
+func DiffValues(oldValue interface{}, newValue interface{}) []Change {
+	changes := []Change{}
+	diff("", oldValue, newValue, &changes)
+	return changes
+}
+
+func diff(path string, oldValue interface{}, newValue interface{}, changes *[]Change) {
+	switch oldTyped := oldValue.(type) {
+	case map[string]interface{}:
+		if newTyped, ok := newValue.(map[string]interface{}); ok {
+			keys := []string{}
+			for key := range oldTyped {
+				keys = append(keys, key)
+			}
+			for key := range newTyped {
+				if _, ok := oldTyped[key]; !ok {
+					keys = append(keys, key)
+				}
+			}
+			sort.Strings(keys)
+
+			for _, key := range keys {
+				childPath := path + "/" + escape(key)
+				oldChild, inOld := oldTyped[key]
+				newChild, inNew := newTyped[key]
+				switch {
+				case !inNew:
+					*changes = append(*changes, Change{Operation: OperationRemove, Path: childPath, OldValue: encode(oldChild)})
+				case !inOld:
+					*changes = append(*changes, Change{Operation: OperationAdd, Path: childPath, NewValue: encode(newChild)})
+				default:
+					diff(childPath, oldChild, newChild, changes)
+				}
+			}
+			return
+		}
+	case []interface{}:
+		if newTyped, ok := newValue.([]interface{}); ok {
+			for i := 0; i < len(oldTyped) || i < len(newTyped); i++ {
+				childPath := path + "/" + strconv.Itoa(i)
+				switch {
+				case i >= len(newTyped):
+					*changes = append(*changes, Change{Operation: OperationRemove, Path: childPath, OldValue: encode(oldTyped[i])})
+				case i >= len(oldTyped):
+					*changes = append(*changes, Change{Operation: OperationAdd, Path: childPath, NewValue: encode(newTyped[i])})
+				default:
+					diff(childPath, oldTyped[i], newTyped[i], changes)
+				}
+			}
+			return
+		}
+	}
+
+	if !reflect.DeepEqual(oldValue, newValue) {
+		*changes = append(*changes, Change{
+			Operation: OperationReplace,
+			Path:      path,
+			OldValue:  encode(oldValue),
+			NewValue:  encode(newValue),
+		})
+	}
+}
+
+// escape escapes a key for use in a JSON pointer.
+func escape(key string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(key, "~", "~0"), "/", "~1")
+}
+
+func encode(value interface{}) *string {
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return nil
+	}
+	output := string(encoded)
+	return &output
+}
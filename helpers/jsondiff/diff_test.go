@@ -0,0 +1,95 @@
+package jsondiff
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+// format writes the change as "operation path old -> new".
+func format(change Change) string {
+	output := change.Operation + " " + change.Path
+	if change.OldValue != nil {
+		output += " " + *change.OldValue
+	}
+	output += " ->"
+	if change.NewValue != nil {
+		output += " " + *change.NewValue
+	}
+	return output
+}
+
+func TestDiffValues(t *testing.T) {
+	tests := []struct {
+		name    string
+		old     string
+		new     string
+		changes []string
+	}{
+		{
+			name:    "equal",
+			old:     `{"a": [1, {"b": null}], "c": "d"}`,
+			new:     `{"c": "d", "a": [1, {"b": null}]}`,
+			changes: []string{},
+		},
+		{
+			name:    "array items",
+			old:     `[1, 2, 3]`,
+			new:     `[1, 4]`,
+			changes: []string{"replace /1 2 -> 4", "remove /2 3 ->"},
+		},
+		{
+			name:    "array append",
+			old:     `[]`,
+			new:     `["a", {"b": 1}]`,
+			changes: []string{`add /0 -> "a"`, `add /1 -> {"b":1}`},
+		},
+		{
+			name: "nested objects",
+			old:  `{"auth": {"authType": "basic", "username": "alice"}, "headers": [{"key": "A", "value": "1"}]}`,
+			new:  `{"auth": {"authType": "bearer", "token": "abc"}, "headers": [{"key": "A", "value": "2"}]}`,
+			changes: []string{
+				`replace /auth/authType "basic" -> "bearer"`,
+				`add /auth/token -> "abc"`,
+				`remove /auth/username "alice" ->`,
+				`replace /headers/0/value "1" -> "2"`,
+			},
+		},
+		{
+			name:    "type changes",
+			old:     `{"body": {"raw": "x"}, "params": []}`,
+			new:     `{"body": null, "params": {}}`,
+			changes: []string{`replace /body {"raw":"x"} -> null`, `replace /params [] -> {}`},
+		},
+		{
+			name:    "escaped keys",
+			old:     `{"a/b": {"c~d": 1}}`,
+			new:     `{"a/b": {"c~d": 2}}`,
+			changes: []string{`replace /a~1b/c~0d 1 -> 2`},
+		},
+		{
+			name:    "root value",
+			old:     `"a"`,
+			new:     `["a"]`,
+			changes: []string{`replace  "a" -> ["a"]`},
+		},
+	}
+
+	for _, test := range tests {
+		var oldValue, newValue interface{}
+		if err := json.Unmarshal([]byte(test.old), &oldValue); err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+		if err := json.Unmarshal([]byte(test.new), &newValue); err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+
+		changes := []string{}
+		for _, change := range DiffValues(oldValue, newValue) {
+			changes = append(changes, format(change))
+		}
+		if !reflect.DeepEqual(changes, test.changes) {
+			t.Errorf("%s: changes = %q, want %q", test.name, changes, test.changes)
+		}
+	}
+}
@@ -0,0 +1,237 @@
+// Package mock matches incoming requests against stored requests and picks
+// the example response to answer with.
+package mock
+
+import (
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/jerbob92/hoppscotch-backend/helpers/hoppscotch"
+)
+
+const (
+	// ResponseNameHeader selects the example response with the given name.
+	ResponseNameHeader = "X-Mock-Response-Name"
+
+	// ResponseCodeHeader selects the first example response with the given
+	// status code.
+	ResponseCodeHeader = "X-Mock-Response-Code"
+)
+
+// Route is a stored request that a mock server answers with its examples.
+type Route struct {
+	RequestID uint
+	Name      string
+	Method    string
+	Examples  []hoppscotch.ExampleResponse
+
+	// segments of the path, an empty segment matches any value.
+	segments []string
+	query    []hoppscotch.KeyValue
+	headers  []hoppscotch.KeyValue
+}
+
+// NewRoute creates the route of a request. Path segments with a variable
+// (<<id>>), a :param or a {param} match any value, a leading variable or host
+// of the endpoint is ignored. Only active query parameters and headers with a
+// value without variables are matched.
+func NewRoute(requestID uint, request *hoppscotch.RESTRequest, examples []hoppscotch.ExampleResponse) *Route {
+	params := request.Params
+	path := endpointPath(request.Endpoint)
+	if index := strings.Index(path, "?"); index >= 0 {
+		values, err := url.ParseQuery(path[index+1:])
+		if err == nil {
+			params = append([]hoppscotch.KeyValue{}, params...)
+			for key := range values {
+				params = append(params, hoppscotch.KeyValue{Key: key, Value: values.Get(key), Active: true})
+			}
+		}
+		path = path[:index]
+	}
+
+	route := &Route{
+		RequestID: requestID,
+		Name:      request.Name,
+		Method:    strings.ToUpper(request.Method),
+		Examples:  examples,
+		segments:  []string{},
+		query:     literalKeyValues(params),
+		headers:   literalKeyValues(request.Headers),
+	}
+	if route.Method == "" {
+		route.Method = http.MethodGet
+	}
+
+	for _, segment := range strings.Split(path, "/") {
+		if segment == "" {
+			continue
+		}
+		if strings.Contains(segment, "<<") || strings.HasPrefix(segment, ":") || (strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}")) {
+			route.segments = append(route.segments, "")
+			continue
+		}
+		if unescaped, err := url.PathUnescape(segment); err == nil {
+			segment = unescaped
+		}
+		route.segments = append(route.segments, segment)
+	}
+
+	return route
+}
+
+// endpointPath returns the endpoint without the scheme, the host or a leading
+// variable, which is usually the base URL.
+func endpointPath(endpoint string) string {
+	endpoint = strings.TrimSpace(endpoint)
+	if index := strings.Index(endpoint, "#"); index >= 0 {
+		endpoint = endpoint[:index]
+	}
+
+	if strings.HasPrefix(endpoint, "<<") {
+		if index := strings.Index(endpoint, ">>"); index >= 0 {
+			return endpoint[index+2:]
+		}
+	}
+
+	if index := strings.Index(endpoint, "://"); index >= 0 {
+		endpoint = endpoint[index+3:]
+	} else if strings.HasPrefix(endpoint, "/") {
+		return endpoint
+	}
+
+	if index := strings.IndexAny(endpoint, "/?"); index >= 0 {
+		return endpoint[index:]
+	}
+	return ""
+}
+
+func literalKeyValues(input []hoppscotch.KeyValue) []hoppscotch.KeyValue {
+	output := []hoppscotch.KeyValue{}
+	for _, keyValue := range input {
+		if !keyValue.Active || keyValue.Key == "" || strings.Contains(keyValue.Key, "<<") || strings.Contains(keyValue.Value, "<<") {
+			continue
+		}
+		output = append(output, keyValue)
+	}
+	return output
+}
+
+// match returns whether the request matches the route, with a score that is
+// higher for more specific routes.
+func (r *Route) match(request *http.Request, path string, matchHeaders bool) (int, bool) {
+	if r.Method != request.Method && !(request.Method == http.MethodHead && r.Method == http.MethodGet) {
+		return 0, false
+	}
+
+	segments := []string{}
+	for _, segment := range strings.Split(path, "/") {
+		if segment != "" {
+			segments = append(segments, segment)
+		}
+	}
+	if len(segments) != len(r.segments) {
+		return 0, false
+	}
+
+	score := 0
+	for i := range segments {
+		if r.segments[i] == "" {
+			continue
+		}
+		if r.segments[i] != segments[i] {
+			return 0, false
+		}
+		// A literal segment weighs more than all conditions together.
+		score += 1000
+	}
+
+	query := request.URL.Query()
+	for _, param := range r.query {
+		values, ok := query[param.Key]
+		if !ok || (param.Value != "" && !contains(values, param.Value)) {
+			return 0, false
+		}
+		score++
+	}
+
+	if matchHeaders {
+		for _, header := range r.headers {
+			values := request.Header.Values(header.Key)
+			if len(values) == 0 || (header.Value != "" && !contains(values, header.Value)) {
+				return 0, false
+			}
+			score++
+		}
+	}
+
+	return score, true
+}
+
+func contains(values []string, value string) bool {
+	for i := range values {
+		if values[i] == value {
+			return true
+		}
+	}
+	return false
+}
+
+// Find returns the most specific route that matches the method, the path,
+// the query and, when matchHeaders is set, the headers of the request. The
+// first route wins when routes are equally specific. It returns nil when no
+// route matches.
+func Find(routes []*Route, request *http.Request, path string, matchHeaders bool) *Route {
+	var found *Route
+	bestScore := -1
+	for _, route := range routes {
+		score, ok := route.match(request, path, matchHeaders)
+		if ok && score > bestScore {
+			found = route
+			bestScore = score
+		}
+	}
+	return found
+}
+
+// Example picks the example to answer the request with. The request can ask
+// for an example by name or by status code with the ResponseNameHeader and
+// ResponseCodeHeader headers, otherwise the first example with a 2xx status
+// is used, or the first example when there is none. It returns nil when
+// there is no example or no example matches the headers.
+func (r *Route) Example(request *http.Request) *hoppscotch.ExampleResponse {
+	if name := request.Header.Get(ResponseNameHeader); name != "" {
+		for i := range r.Examples {
+			if strings.EqualFold(r.Examples[i].Name, name) {
+				return &r.Examples[i]
+			}
+		}
+		return nil
+	}
+
+	if code := request.Header.Get(ResponseCodeHeader); code != "" {
+		status, err := strconv.Atoi(code)
+		if err != nil {
+			return nil
+		}
+		for i := range r.Examples {
+			if r.Examples[i].Status == status {
+				return &r.Examples[i]
+			}
+		}
+		return nil
+	}
+
+	for i := range r.Examples {
+		if r.Examples[i].Status >= 200 && r.Examples[i].Status < 300 {
+			return &r.Examples[i]
+		}
+	}
+
+	if len(r.Examples) > 0 {
+		return &r.Examples[0]
+	}
+
+	return nil
+}
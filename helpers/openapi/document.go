@@ -0,0 +1,294 @@
+package openapi
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Document is an OpenAPI 3.x or Swagger 2 document. Only the parts that are
+// needed to create requests are decoded.
+type Document struct {
+	Swagger    string                `json:"swagger"`
+	OpenAPI    string                `json:"openapi"`
+	Info       Info                  `json:"info"`
+	Servers    []Server              `json:"servers"`
+	Paths      map[string]PathItem   `json:"paths"`
+	Tags       []Tag                 `json:"tags"`
+	Components Components            `json:"components"`
+	Security   []map[string][]string `json:"security"`
+
+	// Swagger 2
+	Host                string                     `json:"host"`
+	BasePath            string                     `json:"basePath"`
+	Schemes             []string                   `json:"schemes"`
+	Consumes            []string                   `json:"consumes"`
+	SecurityDefinitions map[string]*SecurityScheme `json:"securityDefinitions"`
+
+	// raw is the generic representation of the document, used to resolve
+	// references.
+	raw interface{}
+}
+
+type Info struct {
+	Title   string `json:"title"`
+	Version string `json:"version"`
+}
+
+type Server struct {
+	URL       string                    `json:"url"`
+	Variables map[string]ServerVariable `json:"variables"`
+}
+
+type ServerVariable struct {
+	Default string   `json:"default"`
+	Enum    []string `json:"enum,omitempty"`
+}
+
+type Tag struct {
+	Name string `json:"name"`
+}
+
+type Components struct {
+	SecuritySchemes map[string]*SecurityScheme `json:"securitySchemes"`
+}
+
+type PathItem struct {
+	Ref        string       `json:"$ref"`
+	Parameters []*Parameter `json:"parameters"`
+	Get        *Operation   `json:"get"`
+	Put        *Operation   `json:"put"`
+	Post       *Operation   `json:"post"`
+	Delete     *Operation   `json:"delete"`
+	Options    *Operation   `json:"options"`
+	Head       *Operation   `json:"head"`
+	Patch      *Operation   `json:"patch"`
+	Trace      *Operation   `json:"trace"`
+}
+
+// Operations returns the operations of the path in a fixed order.
+func (p PathItem) Operations() []MethodOperation {
+	operations := []MethodOperation{}
+	for _, operation := range []MethodOperation{
+		{"GET", p.Get},
+		{"POST", p.Post},
+		{"PUT", p.Put},
+		{"PATCH", p.Patch},
+		{"DELETE", p.Delete},
+		{"HEAD", p.Head},
+		{"OPTIONS", p.Options},
+		{"TRACE", p.Trace},
+	} {
+		if operation.Operation != nil {
+			operations = append(operations, operation)
+		}
+	}
+	return operations
+}
+
+type MethodOperation struct {
+	Method    string
+	Operation *Operation
+}
+
+type Operation struct {
+	Tags        []string               `json:"tags"`
+	Summary     string                 `json:"summary"`
+	OperationID string                 `json:"operationId"`
+	Parameters  []*Parameter           `json:"parameters"`
+	RequestBody *RequestBody           `json:"requestBody"`
+	Security    *[]map[string][]string `json:"security"`
+
+	// Swagger 2
+	Consumes []string `json:"consumes"`
+}
+
+type Parameter struct {
+	Ref      string             `json:"$ref"`
+	Name     string             `json:"name"`
+	In       string             `json:"in"`
+	Required bool               `json:"required"`
+	Schema   *Schema            `json:"schema"`
+	Example  interface{}        `json:"example"`
+	Examples map[string]Example `json:"examples"`
+
+	// Swagger 2 non-body parameters describe their type inline.
+	Type     string        `json:"type"`
+	Format   string        `json:"format"`
+	Default  interface{}   `json:"default"`
+	Enum     []interface{} `json:"enum"`
+	Items    *Schema       `json:"items"`
+	XExample interface{}   `json:"x-example"`
+}
+
+type RequestBody struct {
+	Ref     string               `json:"$ref"`
+	Content map[string]MediaType `json:"content"`
+}
+
+type MediaType struct {
+	Schema   *Schema            `json:"schema"`
+	Example  interface{}        `json:"example"`
+	Examples map[string]Example `json:"examples"`
+}
+
+type Example struct {
+	Ref   string      `json:"$ref"`
+	Value interface{} `json:"value"`
+}
+
+type SecurityScheme struct {
+	Ref    string `json:"$ref"`
+	Type   string `json:"type"`
+	Scheme string `json:"scheme"`
+	Name   string `json:"name"`
+	In     string `json:"in"`
+
+	// OAuth 2
+	Flows            map[string]OAuthFlow `json:"flows"`
+	AuthorizationURL string               `json:"authorizationUrl"`
+	TokenURL         string               `json:"tokenUrl"`
+}
+
+type OAuthFlow struct {
+	AuthorizationURL string            `json:"authorizationUrl"`
+	TokenURL         string            `json:"tokenUrl"`
+	Scopes           map[string]string `json:"scopes"`
+}
+
+type Schema struct {
+	Ref                  string             `json:"$ref"`
+	Type                 SchemaType         `json:"type"`
+	Format               string             `json:"format"`
+	Properties           map[string]*Schema `json:"properties"`
+	Required             []string           `json:"required"`
+	Items                *Schema            `json:"items"`
+	AdditionalProperties interface{}        `json:"additionalProperties"`
+	AllOf                []*Schema          `json:"allOf"`
+	OneOf                []*Schema          `json:"oneOf"`
+	AnyOf                []*Schema          `json:"anyOf"`
+	Example              interface{}        `json:"example"`
+	Examples             []interface{}      `json:"examples"`
+	Default              interface{}        `json:"default"`
+	Enum                 []interface{}      `json:"enum"`
+	Const                interface{}        `json:"const"`
+}
+
+// SchemaType is the type of a schema, OpenAPI 3.1 allows a list of types.
+type SchemaType []string
+
+func (t *SchemaType) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*t = SchemaType{single}
+		return nil
+	}
+
+	var multiple []string
+	if err := json.Unmarshal(data, &multiple); err != nil {
+		return err
+	}
+	*t = multiple
+	return nil
+}
+
+// First returns the first type that isn't null.
+func (t SchemaType) First() string {
+	for i := range t {
+		if t[i] != "null" {
+			return t[i]
+		}
+	}
+	return ""
+}
+
+// Parse reads an OpenAPI 3.x or Swagger 2 document in JSON or YAML.
+func Parse(data []byte) (*Document, error) {
+	var raw interface{}
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("could not read document: %w", err)
+	}
+
+	raw = normalizeYAML(raw)
+	if _, ok := raw.(map[string]interface{}); !ok {
+		return nil, errors.New("document is not an object")
+	}
+
+	documentJSON, err := json.Marshal(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	document := &Document{}
+	if err := json.Unmarshal(documentJSON, document); err != nil {
+		return nil, fmt.Errorf("could not read document: %w", err)
+	}
+
+	if !document.IsSwagger() && !strings.HasPrefix(document.OpenAPI, "3.") {
+		return nil, errors.New("document is not an OpenAPI 3.x or Swagger 2 document")
+	}
+
+	document.raw = raw
+
+	return document, nil
+}
+
+// IsSwagger returns whether this is a Swagger 2 document.
+func (d *Document) IsSwagger() bool {
+	return strings.HasPrefix(d.Swagger, "2.")
+}
+
+// normalizeYAML converts the maps YAML produces into maps with string keys,
+// so they can be converted to JSON.
+func normalizeYAML(input interface{}) interface{} {
+	switch value := input.(type) {
+	case map[string]interface{}:
+		for key := range value {
+			value[key] = normalizeYAML(value[key])
+		}
+		return value
+	case map[interface{}]interface{}:
+		output := map[string]interface{}{}
+		for key := range value {
+			output[fmt.Sprint(key)] = normalizeYAML(value[key])
+		}
+		return output
+	case []interface{}:
+		for i := range value {
+			value[i] = normalizeYAML(value[i])
+		}
+		return value
+	}
+	return input
+}
+
+// resolve looks up a local reference like #/components/schemas/Pet and
+// decodes it into target.
+func (d *Document) resolve(ref string, target interface{}) error {
+	if !strings.HasPrefix(ref, "#/") {
+		return fmt.Errorf("only local references are supported: %s", ref)
+	}
+
+	current := d.raw
+	for _, part := range strings.Split(ref[2:], "/") {
+		part = strings.ReplaceAll(strings.ReplaceAll(part, "~1", "/"), "~0", "~")
+		object, ok := current.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("could not resolve reference: %s", ref)
+		}
+		current, ok = object[part]
+		if !ok {
+			return fmt.Errorf("could not resolve reference: %s", ref)
+		}
+	}
+
+	refJSON, err := json.Marshal(current)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(refJSON, target)
+}
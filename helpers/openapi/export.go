@@ -0,0 +1,499 @@
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/jerbob92/hoppscotch-backend/helpers/hoppscotch"
+)
+
+// ExportVersion is the OpenAPI version of exported documents.
+const ExportVersion = "3.1.0"
+
+var leadingVariablePattern = regexp.MustCompile(`^<<([^<>]+)>>`)
+
+// ExportDocument is an OpenAPI 3.1 document created from a collection.
+type ExportDocument struct {
+	OpenAPI    string                            `json:"openapi"`
+	Info       Info                              `json:"info"`
+	Servers    []ExportServer                    `json:"servers,omitempty"`
+	Tags       []Tag                             `json:"tags,omitempty"`
+	Paths      map[string]map[string]interface{} `json:"paths"`
+	Components *ExportComponents                 `json:"components,omitempty"`
+}
+
+type ExportServer struct {
+	URL       string                    `json:"url"`
+	Variables map[string]ServerVariable `json:"variables,omitempty"`
+}
+
+type ExportComponents struct {
+	SecuritySchemes map[string]interface{} `json:"securitySchemes,omitempty"`
+}
+
+type ExportOperation struct {
+	Tags        []string               `json:"tags,omitempty"`
+	Summary     string                 `json:"summary,omitempty"`
+	OperationID string                 `json:"operationId,omitempty"`
+	Parameters  []ExportParameter      `json:"parameters,omitempty"`
+	RequestBody *ExportRequestBody     `json:"requestBody,omitempty"`
+	Responses   map[string]interface{} `json:"responses"`
+	Security    []map[string][]string  `json:"security,omitempty"`
+}
+
+type ExportParameter struct {
+	Name     string                 `json:"name"`
+	In       string                 `json:"in"`
+	Required bool                   `json:"required,omitempty"`
+	Schema   map[string]interface{} `json:"schema"`
+	Example  interface{}            `json:"example,omitempty"`
+}
+
+type ExportRequestBody struct {
+	Content map[string]ExportMediaType `json:"content"`
+}
+
+type ExportMediaType struct {
+	Schema  map[string]interface{} `json:"schema,omitempty"`
+	Example interface{}            `json:"example,omitempty"`
+}
+
+// ExportResult is the outcome of exporting a collection.
+type ExportResult struct {
+	Document *ExportDocument
+
+	// Warnings lists the requests that could not be (fully) mapped.
+	Warnings []string
+}
+
+type exporter struct {
+	result       *ExportResult
+	operationIDs map[string]bool
+	tags         map[string]bool
+}
+
+// Export turns every request in the collection tree into an operation. The
+// folder path of a request is used as its tag.
+func Export(collection hoppscotch.Collection) *ExportResult {
+	e := &exporter{
+		result: &ExportResult{
+			Document: &ExportDocument{
+				OpenAPI: ExportVersion,
+				Info: Info{
+					Title:   collection.Name,
+					Version: "1.0.0",
+				},
+				Servers: []ExportServer{},
+				Tags:    []Tag{},
+				Paths:   map[string]map[string]interface{}{},
+			},
+			Warnings: []string{},
+		},
+		operationIDs: map[string]bool{},
+		tags:         map[string]bool{},
+	}
+
+	e.collection(collection, collection.Name)
+
+	if len(e.result.Document.Servers) == 0 {
+		e.result.Document.Servers = nil
+	}
+
+	return e.result
+}
+
+func (e *exporter) warn(tag string, request *hoppscotch.RESTRequest, format string, args ...interface{}) {
+	e.result.Warnings = append(e.result.Warnings, fmt.Sprintf("%s / %s: %s", tag, request.Name, fmt.Sprintf(format, args...)))
+}
+
+func (e *exporter) collection(collection hoppscotch.Collection, tag string) {
+	for _, request := range collection.Requests {
+		e.request(request, tag)
+	}
+
+	for _, folder := range collection.Folders {
+		e.collection(folder, tag+" / "+folder.Name)
+	}
+}
+
+func (e *exporter) request(request *hoppscotch.RESTRequest, tag string) {
+	if request.Method == "" {
+		e.warn(tag, request, "the request is not a REST request")
+		return
+	}
+
+	method := strings.ToLower(request.Method)
+	switch method {
+	case "get", "put", "post", "delete", "options", "head", "patch", "trace":
+	default:
+		e.warn(tag, request, "method %q can not be mapped to an operation", request.Method)
+		return
+	}
+
+	server, path, query, err := splitEndpoint(request.Endpoint)
+	if err != nil {
+		e.warn(tag, request, "%s", err.Error())
+		return
+	}
+
+	pathItem, ok := e.result.Document.Paths[path]
+	if !ok {
+		pathItem = map[string]interface{}{}
+		e.result.Document.Paths[path] = pathItem
+	}
+
+	if _, ok := pathItem[method]; ok {
+		e.warn(tag, request, "%s %s is already used by another request", request.Method, path)
+		return
+	}
+
+	operation := &ExportOperation{
+		Tags:        []string{tag},
+		Summary:     request.Name,
+		OperationID: e.operationID(request.Name, method, path),
+		Parameters:  []ExportParameter{},
+		Responses: map[string]interface{}{
+			"default": map[string]interface{}{
+				"description": "Default response",
+			},
+		},
+	}
+
+	// Path parameters are the variables in the path.
+	for _, match := range pathParameterPattern.FindAllStringSubmatch(path, -1) {
+		operation.Parameters = append(operation.Parameters, ExportParameter{
+			Name:     match[1],
+			In:       "path",
+			Required: true,
+			Schema:   map[string]interface{}{"type": "string"},
+		})
+	}
+
+	for _, param := range append(query, request.Params...) {
+		if param.Key == "" {
+			continue
+		}
+		operation.Parameters = append(operation.Parameters, ExportParameter{
+			Name:     param.Key,
+			In:       "query",
+			Required: param.Active,
+			Schema:   map[string]interface{}{"type": "string"},
+			Example:  exampleValue(param.Value),
+		})
+	}
+
+	for _, header := range request.Headers {
+		if header.Key == "" || strings.EqualFold(header.Key, "Content-Type") || strings.EqualFold(header.Key, "Authorization") {
+			continue
+		}
+		operation.Parameters = append(operation.Parameters, ExportParameter{
+			Name:     header.Key,
+			In:       "header",
+			Required: header.Active,
+			Schema:   map[string]interface{}{"type": "string"},
+			Example:  exampleValue(header.Value),
+		})
+	}
+
+	if len(operation.Parameters) == 0 {
+		operation.Parameters = nil
+	}
+
+	operation.RequestBody = e.requestBody(request, tag)
+	operation.Security = e.security(request.Auth)
+
+	pathItem[method] = operation
+
+	if server != nil {
+		serverIndex := e.server(*server)
+		if serverIndex > 0 {
+			pathItem["servers"] = []ExportServer{*server}
+		}
+	}
+
+	if !e.tags[tag] {
+		e.tags[tag] = true
+		e.result.Document.Tags = append(e.result.Document.Tags, Tag{Name: tag})
+	}
+}
+
+// server registers the server and returns its index in the server list.
+func (e *exporter) server(server ExportServer) int {
+	for i := range e.result.Document.Servers {
+		if e.result.Document.Servers[i].URL == server.URL {
+			return i
+		}
+	}
+	e.result.Document.Servers = append(e.result.Document.Servers, server)
+	return len(e.result.Document.Servers) - 1
+}
+
+// splitEndpoint splits the request endpoint in the server, the templated path
+// and the query parameters.
+func splitEndpoint(endpoint string) (*ExportServer, string, []hoppscotch.KeyValue, error) {
+	endpoint = strings.TrimSpace(endpoint)
+	if endpoint == "" {
+		return nil, "", nil, fmt.Errorf("the request has no URL")
+	}
+
+	query := []hoppscotch.KeyValue{}
+	if index := strings.Index(endpoint, "#"); index > -1 {
+		endpoint = endpoint[:index]
+	}
+	if index := strings.Index(endpoint, "?"); index > -1 {
+		values, err := url.ParseQuery(endpoint[index+1:])
+		if err == nil {
+			keys := []string{}
+			for key := range values {
+				keys = append(keys, key)
+			}
+			sort.Strings(keys)
+			for _, key := range keys {
+				query = append(query, hoppscotch.KeyValue{Key: key, Value: values.Get(key), Active: true})
+			}
+		}
+		endpoint = endpoint[:index]
+	}
+
+	var server *ExportServer
+	path := ""
+
+	if match := leadingVariablePattern.FindStringSubmatch(endpoint); match != nil {
+		// The origin is a variable, like <<baseURL>>/users.
+		server = &ExportServer{
+			URL: "{" + match[1] + "}",
+			Variables: map[string]ServerVariable{
+				match[1]: {Default: ""},
+			},
+		}
+		path = endpoint[len(match[0]):]
+	} else {
+		templated := hoppscotch.VariablePattern.ReplaceAllString(endpoint, "{$1}")
+		if !strings.Contains(templated, "://") {
+			templated = "https://" + templated
+		}
+
+		parsedURL, err := url.Parse(templated)
+		if err != nil || parsedURL.Host == "" {
+			return nil, "", nil, fmt.Errorf("the URL %q can not be mapped to a path", endpoint)
+		}
+
+		server = &ExportServer{
+			URL: parsedURL.Scheme + "://" + parsedURL.Host,
+		}
+		for _, match := range pathParameterPattern.FindAllStringSubmatch(parsedURL.Host, -1) {
+			if server.Variables == nil {
+				server.Variables = map[string]ServerVariable{}
+			}
+			server.Variables[match[1]] = ServerVariable{Default: ""}
+		}
+
+		path = templated[len(parsedURL.Scheme+"://"+parsedURL.Host):]
+	}
+
+	path = hoppscotch.VariablePattern.ReplaceAllString(path, "{$1}")
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+
+	return server, path, query, nil
+}
+
+func (e *exporter) operationID(name string, method string, path string) string {
+	base := ""
+	upperNext := false
+	for _, character := range name {
+		if !unicode.IsLetter(character) && !unicode.IsDigit(character) {
+			upperNext = base != ""
+			continue
+		}
+		if upperNext {
+			character = unicode.ToUpper(character)
+			upperNext = false
+		} else if base == "" {
+			character = unicode.ToLower(character)
+		}
+		base += string(character)
+	}
+
+	if base == "" {
+		base = method + strings.ReplaceAll(strings.ReplaceAll(strings.ReplaceAll(path, "/", "_"), "{", ""), "}", "")
+	}
+
+	operationID := base
+	for i := 2; e.operationIDs[operationID]; i++ {
+		operationID = fmt.Sprintf("%s%d", base, i)
+	}
+	e.operationIDs[operationID] = true
+
+	return operationID
+}
+
+func (e *exporter) requestBody(request *hoppscotch.RESTRequest, tag string) *ExportRequestBody {
+	if request.Body.ContentType == nil {
+		return nil
+	}
+
+	contentType := *request.Body.ContentType
+	mediaType := ExportMediaType{}
+
+	switch contentType {
+	case hoppscotch.ContentTypeMultipart:
+		properties := map[string]interface{}{}
+		for _, field := range request.Body.FormData {
+			if field.Key == "" {
+				continue
+			}
+			if field.IsFile {
+				properties[field.Key] = map[string]interface{}{"type": "string", "format": "binary"}
+			} else {
+				properties[field.Key] = map[string]interface{}{"type": "string", "examples": []interface{}{field.Value}}
+			}
+		}
+		mediaType.Schema = map[string]interface{}{"type": "object", "properties": properties}
+	case hoppscotch.ContentTypeForm:
+		properties := map[string]interface{}{}
+		example := map[string]interface{}{}
+		if request.Body.Raw != nil {
+			for _, field := range hoppscotch.ParseRawKeyValue(*request.Body.Raw) {
+				properties[field.Key] = map[string]interface{}{"type": "string"}
+				example[field.Key] = field.Value
+			}
+		}
+		mediaType.Schema = map[string]interface{}{"type": "object", "properties": properties}
+		mediaType.Example = example
+	case hoppscotch.ContentTypeJSON, hoppscotch.ContentTypeLDJSON, hoppscotch.ContentTypeHALJSON, hoppscotch.ContentTypeVNDAPIJSON:
+		if request.Body.Raw == nil || strings.TrimSpace(*request.Body.Raw) == "" {
+			mediaType.Schema = map[string]interface{}{}
+			break
+		}
+
+		var example interface{}
+		if err := json.Unmarshal([]byte(*request.Body.Raw), &example); err != nil {
+			e.warn(tag, request, "the body is not valid JSON, no schema could be generated")
+			mediaType.Example = *request.Body.Raw
+			break
+		}
+		mediaType.Schema = InferSchema(example)
+		mediaType.Example = example
+	default:
+		mediaType.Schema = map[string]interface{}{"type": "string"}
+		if request.Body.Raw != nil && *request.Body.Raw != "" {
+			mediaType.Example = *request.Body.Raw
+		}
+	}
+
+	return &ExportRequestBody{
+		Content: map[string]ExportMediaType{
+			contentType: mediaType,
+		},
+	}
+}
+
+func (e *exporter) security(auth hoppscotch.Auth) []map[string][]string {
+	if !auth.IsActive() {
+		return nil
+	}
+
+	name := ""
+	var scheme map[string]interface{}
+
+	switch auth.AuthType {
+	case hoppscotch.AuthTypeBasic:
+		name = "basicAuth"
+		scheme = map[string]interface{}{"type": "http", "scheme": "basic"}
+	case hoppscotch.AuthTypeBearer:
+		name = "bearerAuth"
+		scheme = map[string]interface{}{"type": "http", "scheme": "bearer"}
+	case hoppscotch.AuthTypeAPIKey:
+		in := "header"
+		if auth.AddTo == "Query params" {
+			in = "query"
+		}
+		name = "apiKeyAuth_" + in + "_" + auth.Key
+		scheme = map[string]interface{}{"type": "apiKey", "name": auth.Key, "in": in}
+	case hoppscotch.AuthTypeOAuth2:
+		name = "oauth2"
+		scheme = map[string]interface{}{
+			"type": "oauth2",
+			"flows": map[string]interface{}{
+				"authorizationCode": map[string]interface{}{
+					"authorizationUrl": auth.AuthURL,
+					"tokenUrl":         auth.AccessTokenURL,
+					"scopes":           map[string]interface{}{},
+				},
+			},
+		}
+	default:
+		return nil
+	}
+
+	if e.result.Document.Components == nil {
+		e.result.Document.Components = &ExportComponents{
+			SecuritySchemes: map[string]interface{}{},
+		}
+	}
+	e.result.Document.Components.SecuritySchemes[name] = scheme
+
+	scopes := []string{}
+	if auth.AuthType == hoppscotch.AuthTypeOAuth2 && auth.Scope != "" {
+		scopes = strings.Fields(auth.Scope)
+	}
+
+	return []map[string][]string{{name: scopes}}
+}
+
+// exampleValue returns the value as example, unless it's only a variable.
+func exampleValue(value string) interface{} {
+	if value == "" || leadingVariablePattern.FindString(value) == value {
+		return nil
+	}
+	return value
+}
+
+// InferSchema creates a JSON schema that describes the example value.
+func InferSchema(example interface{}) map[string]interface{} {
+	switch value := example.(type) {
+	case nil:
+		return map[string]interface{}{"type": "null"}
+	case bool:
+		return map[string]interface{}{"type": "boolean"}
+	case float64:
+		if value == float64(int64(value)) {
+			return map[string]interface{}{"type": "integer"}
+		}
+		return map[string]interface{}{"type": "number"}
+	case string:
+		return map[string]interface{}{"type": "string"}
+	case []interface{}:
+		schema := map[string]interface{}{"type": "array"}
+		if len(value) > 0 {
+			schema["items"] = InferSchema(value[0])
+		}
+		return schema
+	case map[string]interface{}:
+		properties := map[string]interface{}{}
+		required := []string{}
+		for key := range value {
+			properties[key] = InferSchema(value[key])
+			required = append(required, key)
+		}
+		sort.Strings(required)
+
+		schema := map[string]interface{}{
+			"type":       "object",
+			"properties": properties,
+		}
+		if len(required) > 0 {
+			schema["required"] = required
+		}
+		return schema
+	}
+
+	return map[string]interface{}{}
+}
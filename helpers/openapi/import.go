@@ -0,0 +1,711 @@
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/jerbob92/hoppscotch-backend/helpers/hoppscotch"
+)
+
+// maxExampleDepth limits how deep nested schemas are turned into examples.
+const maxExampleDepth = 8
+
+var pathParameterPattern = regexp.MustCompile(`\{([^{}]+)\}`)
+
+// Result is the outcome of importing a document.
+type Result struct {
+	// Collection is the root collection, operations are grouped in child
+	// collections by their first tag.
+	Collection hoppscotch.Collection
+
+	// Variables are the environment variables used by the requests, like the
+	// server URL and the path parameters.
+	Variables []hoppscotch.EnvironmentVariable
+}
+
+type importer struct {
+	document      *Document
+	variables     []hoppscotch.EnvironmentVariable
+	seenVariables map[string]bool
+}
+
+// Import parses the document and turns every operation into a request.
+func Import(data []byte) (*Result, error) {
+	document, err := Parse(data)
+	if err != nil {
+		return nil, err
+	}
+
+	i := &importer{
+		document:      document,
+		variables:     []hoppscotch.EnvironmentVariable{},
+		seenVariables: map[string]bool{},
+	}
+
+	return i.run()
+}
+
+func (i *importer) addVariable(key, value string) {
+	if i.seenVariables[key] {
+		return
+	}
+	i.seenVariables[key] = true
+	i.variables = append(i.variables, hoppscotch.EnvironmentVariable{
+		Key:   key,
+		Value: value,
+	})
+}
+
+func (i *importer) run() (*Result, error) {
+	title := i.document.Info.Title
+	if title == "" {
+		title = "Imported API"
+	}
+
+	result := &Result{
+		Collection: hoppscotch.Collection{
+			Name:     title,
+			Folders:  []hoppscotch.Collection{},
+			Requests: []*hoppscotch.RESTRequest{},
+		},
+	}
+
+	i.addVariable("baseUrl", i.baseURL())
+
+	// Create the folders for the tags in the order they are documented in.
+	tagFolders := map[string]int{}
+	for _, tag := range i.document.Tags {
+		if _, ok := tagFolders[tag.Name]; ok || tag.Name == "" {
+			continue
+		}
+		tagFolders[tag.Name] = len(result.Collection.Folders)
+		result.Collection.Folders = append(result.Collection.Folders, hoppscotch.Collection{
+			Name:     tag.Name,
+			Folders:  []hoppscotch.Collection{},
+			Requests: []*hoppscotch.RESTRequest{},
+		})
+	}
+
+	paths := []string{}
+	for path := range i.document.Paths {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	for _, path := range paths {
+		pathItem := i.document.Paths[path]
+		if pathItem.Ref != "" {
+			if err := i.document.resolve(pathItem.Ref, &pathItem); err != nil {
+				return nil, err
+			}
+		}
+
+		for _, methodOperation := range pathItem.Operations() {
+			request, err := i.request(path, pathItem, methodOperation.Method, methodOperation.Operation)
+			if err != nil {
+				return nil, fmt.Errorf("%s %s: %w", methodOperation.Method, path, err)
+			}
+
+			if len(methodOperation.Operation.Tags) == 0 || methodOperation.Operation.Tags[0] == "" {
+				result.Collection.Requests = append(result.Collection.Requests, request)
+				continue
+			}
+
+			tag := methodOperation.Operation.Tags[0]
+			folderIndex, ok := tagFolders[tag]
+			if !ok {
+				folderIndex = len(result.Collection.Folders)
+				tagFolders[tag] = folderIndex
+				result.Collection.Folders = append(result.Collection.Folders, hoppscotch.Collection{
+					Name:     tag,
+					Folders:  []hoppscotch.Collection{},
+					Requests: []*hoppscotch.RESTRequest{},
+				})
+			}
+			result.Collection.Folders[folderIndex].Requests = append(result.Collection.Folders[folderIndex].Requests, request)
+		}
+	}
+
+	// Don't import tags without operations.
+	folders := []hoppscotch.Collection{}
+	for _, folder := range result.Collection.Folders {
+		if len(folder.Requests) > 0 {
+			folders = append(folders, folder)
+		}
+	}
+	result.Collection.Folders = folders
+	result.Variables = i.variables
+
+	return result, nil
+}
+
+// baseURL returns the URL of the first server, server variables are turned
+// into environment variables.
+func (i *importer) baseURL() string {
+	if i.document.IsSwagger() {
+		if i.document.Host == "" {
+			return strings.TrimSuffix(i.document.BasePath, "/")
+		}
+
+		scheme := "https"
+		if len(i.document.Schemes) > 0 {
+			scheme = i.document.Schemes[0]
+		}
+		return strings.TrimSuffix(scheme+"://"+i.document.Host+i.document.BasePath, "/")
+	}
+
+	if len(i.document.Servers) == 0 {
+		return ""
+	}
+
+	server := i.document.Servers[0]
+	names := []string{}
+	for name := range server.Variables {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		i.addVariable(name, server.Variables[name].Default)
+	}
+
+	return strings.TrimSuffix(pathParameterPattern.ReplaceAllString(server.URL, "<<$1>>"), "/")
+}
+
+func (i *importer) request(path string, pathItem PathItem, method string, operation *Operation) (*hoppscotch.RESTRequest, error) {
+	name := operation.Summary
+	if name == "" {
+		name = operation.OperationID
+	}
+	if name == "" {
+		name = method + " " + path
+	}
+
+	request := hoppscotch.NewRESTRequest(name, method, "<<baseUrl>>"+pathParameterPattern.ReplaceAllString(path, "<<$1>>"))
+
+	parameters, err := i.parameters(pathItem.Parameters, operation.Parameters)
+	if err != nil {
+		return nil, err
+	}
+
+	formParameters := []*Parameter{}
+	for _, parameter := range parameters {
+		switch parameter.In {
+		case "path":
+			i.addVariable(parameter.Name, i.parameterExample(parameter))
+		case "query":
+			request.Params = append(request.Params, hoppscotch.KeyValue{
+				Key:    parameter.Name,
+				Value:  i.parameterExample(parameter),
+				Active: parameter.Required,
+			})
+		case "header":
+			if strings.EqualFold(parameter.Name, "Content-Type") {
+				continue
+			}
+			request.Headers = append(request.Headers, hoppscotch.KeyValue{
+				Key:    parameter.Name,
+				Value:  i.parameterExample(parameter),
+				Active: parameter.Required,
+			})
+		case "body":
+			contentType := i.preferredContentType(operation.Consumes, hoppscotch.ContentTypeJSON)
+			i.setBody(request, contentType, MediaType{Schema: parameter.Schema})
+		case "formData":
+			formParameters = append(formParameters, parameter)
+		}
+	}
+
+	if len(formParameters) > 0 {
+		i.setSwaggerFormBody(request, operation.Consumes, formParameters)
+	}
+
+	if operation.RequestBody != nil {
+		requestBody := operation.RequestBody
+		if requestBody.Ref != "" {
+			requestBody = &RequestBody{}
+			if err := i.document.resolve(operation.RequestBody.Ref, requestBody); err != nil {
+				return nil, err
+			}
+		}
+
+		contentTypes := []string{}
+		for contentType := range requestBody.Content {
+			contentTypes = append(contentTypes, contentType)
+		}
+		sort.Strings(contentTypes)
+
+		if len(contentTypes) > 0 {
+			contentType := i.preferredContentType(contentTypes, contentTypes[0])
+			i.setBody(request, contentType, requestBody.Content[contentType])
+		}
+	}
+
+	security := i.document.Security
+	if operation.Security != nil {
+		security = *operation.Security
+	}
+	i.setAuth(request, security)
+
+	return request, nil
+}
+
+// parameters merges the path and operation parameters, the operation
+// parameters override the path parameters.
+func (i *importer) parameters(pathParameters []*Parameter, operationParameters []*Parameter) ([]*Parameter, error) {
+	output := []*Parameter{}
+	indexes := map[string]int{}
+
+	for _, parameter := range append(append([]*Parameter{}, pathParameters...), operationParameters...) {
+		if parameter == nil {
+			continue
+		}
+
+		if parameter.Ref != "" {
+			resolved := &Parameter{}
+			if err := i.document.resolve(parameter.Ref, resolved); err != nil {
+				return nil, err
+			}
+			parameter = resolved
+		}
+
+		key := parameter.In + ":" + parameter.Name
+		if index, ok := indexes[key]; ok {
+			output[index] = parameter
+			continue
+		}
+
+		indexes[key] = len(output)
+		output = append(output, parameter)
+	}
+
+	return output, nil
+}
+
+// preferredContentType picks the content type that we can best represent.
+func (i *importer) preferredContentType(available []string, fallback string) string {
+	if len(available) == 0 {
+		available = i.document.Consumes
+	}
+
+	for _, preferred := range []string{"json", hoppscotch.ContentTypeForm, hoppscotch.ContentTypeMultipart, "xml", "text/"} {
+		for _, contentType := range available {
+			if strings.Contains(contentType, preferred) {
+				return contentType
+			}
+		}
+	}
+
+	if len(available) > 0 {
+		return available[0]
+	}
+
+	return fallback
+}
+
+func (i *importer) setBody(request *hoppscotch.RESTRequest, contentType string, mediaType MediaType) {
+	switch {
+	case contentType == hoppscotch.ContentTypeForm || contentType == hoppscotch.ContentTypeMultipart:
+		fields := []hoppscotch.FormDataKeyValue{}
+		schema := i.resolveSchema(mediaType.Schema, 0)
+		if schema != nil {
+			example, _ := i.mediaTypeExample(mediaType).(map[string]interface{})
+			names := []string{}
+			for name := range schema.Properties {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+
+			for _, name := range names {
+				property := i.resolveSchema(schema.Properties[name], 0)
+				field := hoppscotch.FormDataKeyValue{
+					Key:    name,
+					Value:  exampleString(example[name]),
+					Active: true,
+				}
+				if property != nil && property.Format == "binary" {
+					field.IsFile = true
+					field.Value = ""
+				}
+				fields = append(fields, field)
+			}
+		}
+
+		if contentType == hoppscotch.ContentTypeMultipart {
+			request.SetFormDataBody(fields)
+			return
+		}
+
+		keyValues := []hoppscotch.KeyValue{}
+		for _, field := range fields {
+			keyValues = append(keyValues, hoppscotch.KeyValue{
+				Key:    field.Key,
+				Value:  field.Value,
+				Active: field.Active,
+			})
+		}
+		request.SetRawBody(contentType, hoppscotch.RawKeyValue(keyValues))
+	case strings.Contains(contentType, "json"):
+		body := ""
+		if example := i.mediaTypeExample(mediaType); example != nil {
+			exampleJSON, err := json.MarshalIndent(example, "", "  ")
+			if err == nil {
+				body = string(exampleJSON)
+			}
+		}
+		switch contentType {
+		case hoppscotch.ContentTypeJSON, hoppscotch.ContentTypeLDJSON, hoppscotch.ContentTypeHALJSON, hoppscotch.ContentTypeVNDAPIJSON:
+		default:
+			contentType = hoppscotch.ContentTypeJSON
+		}
+		request.SetRawBody(contentType, body)
+	default:
+		body := ""
+		if example, ok := i.mediaTypeExample(mediaType).(string); ok {
+			body = example
+		}
+		switch {
+		case strings.Contains(contentType, "xml"):
+			contentType = hoppscotch.ContentTypeXML
+		case contentType == hoppscotch.ContentTypeHTML:
+		default:
+			contentType = hoppscotch.ContentTypePlain
+		}
+		request.SetRawBody(contentType, body)
+	}
+}
+
+func (i *importer) setSwaggerFormBody(request *hoppscotch.RESTRequest, consumes []string, parameters []*Parameter) {
+	hasFile := false
+	for _, parameter := range parameters {
+		if parameter.Type == "file" {
+			hasFile = true
+		}
+	}
+
+	contentType := hoppscotch.ContentTypeForm
+	if hasFile || i.preferredContentType(consumes, hoppscotch.ContentTypeForm) == hoppscotch.ContentTypeMultipart {
+		contentType = hoppscotch.ContentTypeMultipart
+	}
+
+	if contentType == hoppscotch.ContentTypeMultipart {
+		fields := []hoppscotch.FormDataKeyValue{}
+		for _, parameter := range parameters {
+			field := hoppscotch.FormDataKeyValue{
+				Key:    parameter.Name,
+				Active: true,
+				IsFile: parameter.Type == "file",
+			}
+			if !field.IsFile {
+				field.Value = i.parameterExample(parameter)
+			}
+			fields = append(fields, field)
+		}
+		request.SetFormDataBody(fields)
+		return
+	}
+
+	keyValues := []hoppscotch.KeyValue{}
+	for _, parameter := range parameters {
+		keyValues = append(keyValues, hoppscotch.KeyValue{
+			Key:    parameter.Name,
+			Value:  i.parameterExample(parameter),
+			Active: true,
+		})
+	}
+	request.SetRawBody(contentType, hoppscotch.RawKeyValue(keyValues))
+}
+
+func (i *importer) setAuth(request *hoppscotch.RESTRequest, security []map[string][]string) {
+	for _, requirement := range security {
+		names := []string{}
+		for name := range requirement {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			scheme := i.securityScheme(name)
+			if scheme == nil {
+				continue
+			}
+
+			switch {
+			case scheme.Type == "basic" || (scheme.Type == "http" && strings.EqualFold(scheme.Scheme, "basic")):
+				i.addVariable("username", "")
+				i.addVariable("password", "")
+				request.Auth = hoppscotch.Auth{
+					AuthType:   hoppscotch.AuthTypeBasic,
+					AuthActive: true,
+					Username:   "<<username>>",
+					Password:   "<<password>>",
+				}
+			case scheme.Type == "http" && strings.EqualFold(scheme.Scheme, "bearer"):
+				i.addVariable("bearerToken", "")
+				request.Auth = hoppscotch.Auth{
+					AuthType:   hoppscotch.AuthTypeBearer,
+					AuthActive: true,
+					Token:      "<<bearerToken>>",
+				}
+			case scheme.Type == "apiKey" && scheme.In != "cookie":
+				i.addVariable("apiKey", "")
+				addTo := "Headers"
+				if scheme.In == "query" {
+					addTo = "Query params"
+				}
+				request.Auth = hoppscotch.Auth{
+					AuthType:   hoppscotch.AuthTypeAPIKey,
+					AuthActive: true,
+					Key:        scheme.Name,
+					Value:      "<<apiKey>>",
+					AddTo:      addTo,
+				}
+			case scheme.Type == "oauth2":
+				i.addVariable("accessToken", "")
+				auth := hoppscotch.Auth{
+					AuthType:       hoppscotch.AuthTypeOAuth2,
+					AuthActive:     true,
+					Token:          "<<accessToken>>",
+					AuthURL:        scheme.AuthorizationURL,
+					AccessTokenURL: scheme.TokenURL,
+					Scope:          strings.Join(requirement[name], " "),
+				}
+				for _, flowName := range []string{"authorizationCode", "implicit", "clientCredentials", "password"} {
+					flow, ok := scheme.Flows[flowName]
+					if !ok {
+						continue
+					}
+					if auth.AuthURL == "" {
+						auth.AuthURL = flow.AuthorizationURL
+					}
+					if auth.AccessTokenURL == "" {
+						auth.AccessTokenURL = flow.TokenURL
+					}
+				}
+				request.Auth = auth
+			default:
+				continue
+			}
+
+			// Hoppscotch only supports one auth method per request.
+			return
+		}
+	}
+}
+
+func (i *importer) securityScheme(name string) *SecurityScheme {
+	var scheme *SecurityScheme
+	if i.document.IsSwagger() {
+		scheme = i.document.SecurityDefinitions[name]
+	} else {
+		scheme = i.document.Components.SecuritySchemes[name]
+	}
+
+	if scheme != nil && scheme.Ref != "" {
+		resolved := &SecurityScheme{}
+		if err := i.document.resolve(scheme.Ref, resolved); err != nil {
+			return nil
+		}
+		scheme = resolved
+	}
+
+	return scheme
+}
+
+func (i *importer) parameterExample(parameter *Parameter) string {
+	if parameter.Example != nil {
+		return exampleString(parameter.Example)
+	}
+
+	for _, example := range sortedExamples(parameter.Examples) {
+		if value := i.exampleValue(example); value != nil {
+			return exampleString(value)
+		}
+	}
+
+	if parameter.XExample != nil {
+		return exampleString(parameter.XExample)
+	}
+
+	if parameter.Schema != nil {
+		return exampleString(i.schemaExample(parameter.Schema, 0, map[string]bool{}))
+	}
+
+	if parameter.Default != nil {
+		return exampleString(parameter.Default)
+	}
+
+	if len(parameter.Enum) > 0 {
+		return exampleString(parameter.Enum[0])
+	}
+
+	return ""
+}
+
+func (i *importer) mediaTypeExample(mediaType MediaType) interface{} {
+	if mediaType.Example != nil {
+		return mediaType.Example
+	}
+
+	for _, example := range sortedExamples(mediaType.Examples) {
+		if value := i.exampleValue(example); value != nil {
+			return value
+		}
+	}
+
+	return i.schemaExample(mediaType.Schema, 0, map[string]bool{})
+}
+
+func (i *importer) exampleValue(example Example) interface{} {
+	if example.Ref != "" {
+		resolved := Example{}
+		if err := i.document.resolve(example.Ref, &resolved); err != nil {
+			return nil
+		}
+		return resolved.Value
+	}
+	return example.Value
+}
+
+// resolveSchema follows schema references.
+func (i *importer) resolveSchema(schema *Schema, depth int) *Schema {
+	for schema != nil && schema.Ref != "" && depth < maxExampleDepth {
+		resolved := &Schema{}
+		if err := i.document.resolve(schema.Ref, resolved); err != nil {
+			return nil
+		}
+		schema = resolved
+		depth++
+	}
+	return schema
+}
+
+// schemaExample generates an example value for the schema.
+func (i *importer) schemaExample(schema *Schema, depth int, seen map[string]bool) interface{} {
+	if schema == nil || depth > maxExampleDepth {
+		return nil
+	}
+
+	if schema.Ref != "" {
+		if seen[schema.Ref] {
+			return nil
+		}
+		resolved := &Schema{}
+		if err := i.document.resolve(schema.Ref, resolved); err != nil {
+			return nil
+		}
+
+		nestedSeen := map[string]bool{schema.Ref: true}
+		for ref := range seen {
+			nestedSeen[ref] = true
+		}
+		return i.schemaExample(resolved, depth+1, nestedSeen)
+	}
+
+	switch {
+	case schema.Example != nil:
+		return schema.Example
+	case len(schema.Examples) > 0:
+		return schema.Examples[0]
+	case schema.Const != nil:
+		return schema.Const
+	case schema.Default != nil:
+		return schema.Default
+	case len(schema.Enum) > 0:
+		return schema.Enum[0]
+	}
+
+	if len(schema.AllOf) > 0 {
+		output := map[string]interface{}{}
+		for _, subSchema := range schema.AllOf {
+			if example, ok := i.schemaExample(subSchema, depth+1, seen).(map[string]interface{}); ok {
+				for key, value := range example {
+					output[key] = value
+				}
+			}
+		}
+		return output
+	}
+
+	if len(schema.OneOf) > 0 {
+		return i.schemaExample(schema.OneOf[0], depth+1, seen)
+	}
+
+	if len(schema.AnyOf) > 0 {
+		return i.schemaExample(schema.AnyOf[0], depth+1, seen)
+	}
+
+	switch schema.Type.First() {
+	case "array":
+		item := i.schemaExample(schema.Items, depth+1, seen)
+		if item == nil {
+			return []interface{}{}
+		}
+		return []interface{}{item}
+	case "string":
+		switch schema.Format {
+		case "date-time":
+			return "1970-01-01T00:00:00Z"
+		case "date":
+			return "1970-01-01"
+		case "email":
+			return "user@example.com"
+		case "uuid":
+			return "00000000-0000-0000-0000-000000000000"
+		case "uri", "url":
+			return "https://example.com"
+		case "binary", "byte":
+			return ""
+		}
+		return "string"
+	case "integer", "number":
+		return 0
+	case "boolean":
+		return false
+	case "object", "":
+		if len(schema.Properties) == 0 && schema.Type.First() == "" {
+			return nil
+		}
+
+		output := map[string]interface{}{}
+		for name, property := range schema.Properties {
+			output[name] = i.schemaExample(property, depth+1, seen)
+		}
+		return output
+	}
+
+	return nil
+}
+
+func sortedExamples(examples map[string]Example) []Example {
+	names := []string{}
+	for name := range examples {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	output := []Example{}
+	for _, name := range names {
+		output = append(output, examples[name])
+	}
+	return output
+}
+
+// exampleString turns an example value into the string we can use in a
+// parameter or header.
+func exampleString(value interface{}) string {
+	switch value := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return value
+	}
+
+	valueJSON, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(valueJSON)
+}
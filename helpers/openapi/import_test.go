@@ -0,0 +1,73 @@
+package openapi
+
+import (
+	"testing"
+
+	"github.com/jerbob92/hoppscotch-backend/helpers/hoppscotch"
+)
+
+func TestImportReferenceCycles(t *testing.T) {
+	tests := []struct {
+		name        string
+		document    string
+		contentType string
+		body        string
+	}{
+		{
+			name: "openapi 3 self reference",
+			document: `{"openapi": "3.0.0", "info": {"title": "Tree"},
+				"paths": {"/nodes": {"post": {"requestBody": {"content": {"application/json": {"schema": {"$ref": "#/components/schemas/Node"}}}}}}},
+				"components": {"schemas": {"Node": {"type": "object", "properties": {
+					"name": {"type": "string"},
+					"children": {"type": "array", "items": {"$ref": "#/components/schemas/Node"}}}}}}}`,
+			contentType: hoppscotch.ContentTypeJSON,
+			body:        "{\n  \"children\": [],\n  \"name\": \"string\"\n}",
+		},
+		{
+			name: "openapi 3 reference to itself",
+			document: `{"openapi": "3.0.0", "info": {"title": "Loop"},
+				"paths": {"/loop": {"post": {"requestBody": {"content": {"application/x-www-form-urlencoded": {"schema": {"$ref": "#/components/schemas/Loop"}}}}}}},
+				"components": {"schemas": {"Loop": {"$ref": "#/components/schemas/Loop"}}}}`,
+			contentType: hoppscotch.ContentTypeForm,
+			body:        "",
+		},
+		{
+			name: "swagger 2 mutual references",
+			document: `{"swagger": "2.0", "info": {"title": "Pair"}, "host": "example.com",
+				"paths": {"/a": {"post": {"parameters": [{"in": "body", "name": "body", "schema": {"$ref": "#/definitions/A"}}]}}},
+				"definitions": {
+					"A": {"type": "object", "properties": {"b": {"$ref": "#/definitions/B"}}},
+					"B": {"type": "object", "properties": {"a": {"$ref": "#/definitions/A"}, "id": {"type": "integer"}}}}}`,
+			contentType: hoppscotch.ContentTypeJSON,
+			body:        "{\n  \"b\": {\n    \"a\": null,\n    \"id\": 0\n  }\n}",
+		},
+		{
+			name: "swagger 2 self reference in items",
+			document: `{"swagger": "2.0", "info": {"title": "List"}, "host": "example.com",
+				"paths": {"/items": {"post": {"parameters": [{"in": "body", "name": "body", "schema": {"$ref": "#/definitions/Item"}}]}}},
+				"definitions": {"Item": {"type": "object", "properties": {"next": {"type": "array", "items": {"$ref": "#/definitions/Item"}}}}}}`,
+			contentType: hoppscotch.ContentTypeJSON,
+			body:        "{\n  \"next\": []\n}",
+		},
+	}
+
+	for _, test := range tests {
+		result, err := Import([]byte(test.document))
+		if err != nil {
+			t.Errorf("%s: %v", test.name, err)
+			continue
+		}
+		if len(result.Collection.Requests) != 1 {
+			t.Errorf("%s: got %d requests", test.name, len(result.Collection.Requests))
+			continue
+		}
+
+		body := result.Collection.Requests[0].Body
+		if body.ContentType == nil || *body.ContentType != test.contentType {
+			t.Errorf("%s: content type = %v, want %q", test.name, body.ContentType, test.contentType)
+		}
+		if body.Raw == nil || *body.Raw != test.body {
+			t.Errorf("%s: body = %v, want %q", test.name, body.Raw, test.body)
+		}
+	}
+}
@@ -0,0 +1,140 @@
+package postman
+
+// CollectionSchema is the schema URL of a Postman v2.1 collection.
+const CollectionSchema = "https://schema.getpostman.com/json/collection/v2.1.0/collection.json"
+
+type Collection struct {
+	Info     Info       `json:"info"`
+	Item     []Item     `json:"item"`
+	Auth     *Auth      `json:"auth,omitempty"`
+	Variable []Variable `json:"variable,omitempty"`
+}
+
+type Info struct {
+	PostmanID string `json:"_postman_id"`
+	Name      string `json:"name"`
+	Schema    string `json:"schema"`
+}
+
+// Item is either a folder (Item is set) or a request (Request is set).
+type Item struct {
+	Name     string     `json:"name"`
+	Item     []Item     `json:"item,omitempty"`
+	Request  *Request   `json:"request,omitempty"`
+	Auth     *Auth      `json:"auth,omitempty"`
+	Response []Response `json:"response,omitempty"`
+	Event    []Event    `json:"event,omitempty"`
+}
+
+type Request struct {
+	Method string   `json:"method"`
+	Header []Header `json:"header"`
+	URL    URL      `json:"url"`
+	Body   *Body    `json:"body,omitempty"`
+	Auth   *Auth    `json:"auth,omitempty"`
+}
+
+type Header struct {
+	Key      string `json:"key"`
+	Value    string `json:"value"`
+	Disabled bool   `json:"disabled,omitempty"`
+}
+
+type URL struct {
+	Raw      string        `json:"raw"`
+	Protocol string        `json:"protocol,omitempty"`
+	Host     []string      `json:"host,omitempty"`
+	Port     string        `json:"port,omitempty"`
+	Path     []string      `json:"path,omitempty"`
+	Query    []QueryParam  `json:"query,omitempty"`
+	Variable []URLVariable `json:"variable,omitempty"`
+}
+
+type QueryParam struct {
+	Key      string `json:"key"`
+	Value    string `json:"value"`
+	Disabled bool   `json:"disabled,omitempty"`
+}
+
+type URLVariable struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+type Body struct {
+	Mode       string       `json:"mode"`
+	Raw        string       `json:"raw,omitempty"`
+	URLEncoded []FormParam  `json:"urlencoded,omitempty"`
+	FormData   []FormParam  `json:"formdata,omitempty"`
+	Options    *BodyOptions `json:"options,omitempty"`
+}
+
+type BodyOptions struct {
+	Raw BodyRawOptions `json:"raw"`
+}
+
+type BodyRawOptions struct {
+	Language string `json:"language"`
+}
+
+type FormParam struct {
+	Key      string `json:"key"`
+	Value    string `json:"value,omitempty"`
+	Src      string `json:"src,omitempty"`
+	Type     string `json:"type"`
+	Disabled bool   `json:"disabled,omitempty"`
+}
+
+type Auth struct {
+	Type   string          `json:"type"`
+	Basic  []AuthAttribute `json:"basic,omitempty"`
+	Bearer []AuthAttribute `json:"bearer,omitempty"`
+	APIKey []AuthAttribute `json:"apikey,omitempty"`
+	OAuth2 []AuthAttribute `json:"oauth2,omitempty"`
+}
+
+type AuthAttribute struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+	Type  string `json:"type"`
+}
+
+type Event struct {
+	Listen string `json:"listen"`
+	Script Script `json:"script"`
+}
+
+type Script struct {
+	Type string   `json:"type"`
+	Exec []string `json:"exec"`
+}
+
+type Response struct {
+	Name            string   `json:"name"`
+	OriginalRequest *Request `json:"originalRequest,omitempty"`
+	Status          string   `json:"status"`
+	Code            int      `json:"code"`
+	Header          []Header `json:"header"`
+	Body            string   `json:"body"`
+}
+
+type Variable struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+	Type  string `json:"type,omitempty"`
+}
+
+// Environment is a Postman environment file.
+type Environment struct {
+	ID     string             `json:"id"`
+	Name   string             `json:"name"`
+	Values []EnvironmentValue `json:"values"`
+	Scope  string             `json:"_postman_variable_scope"`
+}
+
+type EnvironmentValue struct {
+	Key     string `json:"key"`
+	Value   string `json:"value"`
+	Type    string `json:"type"`
+	Enabled bool   `json:"enabled"`
+}
@@ -4,4 +4,7 @@ const (
 	InternalError        = 100000
 	InputValidationError = 100001
 	Unauthorized         = 100002
+	Forbidden            = 100003
+	UpstreamError        = 100004
+	NotFound             = 100005
 )
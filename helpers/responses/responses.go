@@ -96,6 +96,18 @@ func RenderInternalError(c *gin.Context, code int, obj interface{}) (int, interf
 		if errorObj.ErrorCode == Unauthorized {
 			code = http.StatusUnauthorized
 		}
+
+		if errorObj.ErrorCode == Forbidden {
+			code = http.StatusForbidden
+		}
+
+		if errorObj.ErrorCode == UpstreamError {
+			code = http.StatusBadGateway
+		}
+
+		if errorObj.ErrorCode == NotFound {
+			code = http.StatusNotFound
+		}
 	}
 
 	if requestError, ok := obj.(RequestInternalError); ok {
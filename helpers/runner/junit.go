@@ -0,0 +1,141 @@
+package runner
+
+import (
+	"encoding/xml"
+	"strconv"
+	"strings"
+)
+
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Errors   int              `xml:"errors,attr"`
+	Time     string           `xml:"time,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	Errors    int             `xml:"errors,attr"`
+	Time      string          `xml:"time,attr"`
+	Timestamp string          `xml:"timestamp,attr"`
+	TestCases []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitMessage `xml:"failure,omitempty"`
+	Error     *junitMessage `xml:"error,omitempty"`
+	Skipped   *junitMessage `xml:"skipped,omitempty"`
+}
+
+type junitMessage struct {
+	Message string `xml:"message,attr"`
+	Text    string `xml:",chardata"`
+}
+
+func junitSeconds(milliseconds float64) string {
+	return strconv.FormatFloat(milliseconds/1000, 'f', 3, 64)
+}
+
+// JUnit returns the report as JUnit XML, every request is a test suite and
+// every test of the request a test case. Requests without tests have a test
+// case for the status, unsupported scripts a skipped test case.
+func (r *Report) JUnit() (string, error) {
+	suites := junitTestSuites{
+		Name:   r.Name,
+		Time:   junitSeconds(r.Duration),
+		Suites: []junitTestSuite{},
+	}
+
+	for _, request := range r.Requests {
+		name := request.Name
+		if request.Path != "" {
+			name = request.Path + " / " + request.Name
+		}
+
+		suite := junitTestSuite{
+			Name:      name,
+			Time:      junitSeconds(request.Duration),
+			Timestamp: r.StartedAt.Format("2006-01-02T15:04:05"),
+			TestCases: []junitTestCase{},
+		}
+
+		for _, test := range request.Tests {
+			testCase := junitTestCase{
+				Name:      test.Name,
+				ClassName: name,
+				Time:      junitSeconds(0),
+			}
+			if testCase.Name == "" {
+				testCase.Name = "Expectations"
+			}
+
+			failed := []string{}
+			for _, expectation := range test.Expectations {
+				if !expectation.Passed {
+					failed = append(failed, expectation.Message)
+				}
+			}
+			if test.Error != "" {
+				failed = append(failed, test.Error)
+			}
+			if len(failed) > 0 {
+				testCase.Failure = &junitMessage{Message: failed[0], Text: strings.Join(failed, "\n")}
+				suite.Failures++
+			}
+
+			suite.TestCases = append(suite.TestCases, testCase)
+		}
+
+		if request.Unsupported != "" {
+			suite.TestCases = append(suite.TestCases, junitTestCase{
+				Name:      "Scripts",
+				ClassName: name,
+				Time:      junitSeconds(0),
+				Skipped:   &junitMessage{Message: request.Unsupported, Text: request.Unsupported},
+			})
+		}
+
+		if request.Error != "" {
+			suite.TestCases = append(suite.TestCases, junitTestCase{
+				Name:      "Request",
+				ClassName: name,
+				Time:      junitSeconds(request.Duration),
+				Error:     &junitMessage{Message: request.Error, Text: request.Error},
+			})
+			suite.Errors++
+		} else if len(request.Tests) == 0 {
+			testCase := junitTestCase{
+				Name:      "Status",
+				ClassName: name,
+				Time:      junitSeconds(request.Duration),
+			}
+			if !request.Passed {
+				message := "Status " + strconv.Itoa(request.Status)
+				testCase.Failure = &junitMessage{Message: message, Text: message}
+				suite.Failures++
+			}
+			suite.TestCases = append(suite.TestCases, testCase)
+		}
+
+		suite.Tests = len(suite.TestCases)
+		suites.Tests += suite.Tests
+		suites.Failures += suite.Failures
+		suites.Errors += suite.Errors
+		suites.Suites = append(suites.Suites, suite)
+	}
+
+	output, err := xml.MarshalIndent(suites, "", "  ")
+	if err != nil {
+		return "", err
+	}
+
+	return xml.Header + string(output) + "\n", nil
+}
@@ -0,0 +1,157 @@
+package runner
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+type tokenKind int
+
+const (
+	tokenIdentifier tokenKind = iota
+	tokenNumber
+	tokenString
+	tokenPunctuation
+	tokenEOF
+)
+
+type token struct {
+	kind  tokenKind
+	value string
+	line  int
+}
+
+// punctuation is ordered so the longest operators are matched first.
+var punctuation = []string{
+	"===", "!==", "=>", "==", "!=", "<=", ">=", "&&", "||",
+	"(", ")", "{", "}", "[", "]", ".", ",", ";", ":", "!", "<", ">", "+", "-", "*", "/", "=",
+}
+
+// tokenize splits a script into tokens, comments are left out.
+func tokenize(script string) ([]token, error) {
+	tokens := []token{}
+	runes := []rune(script)
+	line := 1
+
+	for i := 0; i < len(runes); {
+		r := runes[i]
+
+		switch {
+		case r == '\n':
+			line++
+			i++
+		case unicode.IsSpace(r):
+			i++
+		case r == '/' && i+1 < len(runes) && runes[i+1] == '/':
+			for i < len(runes) && runes[i] != '\n' {
+				i++
+			}
+		case r == '/' && i+1 < len(runes) && runes[i+1] == '*':
+			start := line
+			for i += 2; i+1 < len(runes) && !(runes[i] == '*' && runes[i+1] == '/'); i++ {
+				if runes[i] == '\n' {
+					line++
+				}
+			}
+			if i+1 >= len(runes) {
+				return nil, errors.New("line " + strconv.Itoa(start) + ": unterminated comment")
+			}
+			i += 2
+		case r == '"' || r == '\'' || r == '`':
+			value, length, err := readString(runes[i:])
+			if err != nil {
+				return nil, errors.New("line " + strconv.Itoa(line) + ": " + err.Error())
+			}
+			tokens = append(tokens, token{kind: tokenString, value: value, line: line})
+			line += strings.Count(string(runes[i:i+length]), "\n")
+			i += length
+		case unicode.IsDigit(r):
+			start := i
+			for i < len(runes) && (unicode.IsDigit(runes[i]) || runes[i] == '.') {
+				i++
+			}
+			tokens = append(tokens, token{kind: tokenNumber, value: string(runes[start:i]), line: line})
+		case r == '_' || r == '$' || unicode.IsLetter(r):
+			start := i
+			for i < len(runes) && (runes[i] == '_' || runes[i] == '$' || unicode.IsLetter(runes[i]) || unicode.IsDigit(runes[i])) {
+				i++
+			}
+			tokens = append(tokens, token{kind: tokenIdentifier, value: string(runes[start:i]), line: line})
+		default:
+			matched := false
+			for _, operator := range punctuation {
+				if startsWith(runes[i:], operator) {
+					tokens = append(tokens, token{kind: tokenPunctuation, value: operator, line: line})
+					i += len(operator)
+					matched = true
+					break
+				}
+			}
+			if !matched {
+				return nil, errors.New("line " + strconv.Itoa(line) + ": unexpected character " + string(r))
+			}
+		}
+	}
+
+	tokens = append(tokens, token{kind: tokenEOF, line: line})
+	return tokens, nil
+}
+
+// startsWith returns whether the runes start with the operator, operators
+// only contain ASCII characters.
+func startsWith(runes []rune, operator string) bool {
+	if len(runes) < len(operator) {
+		return false
+	}
+	for index := 0; index < len(operator); index++ {
+		if runes[index] != rune(operator[index]) {
+			return false
+		}
+	}
+	return true
+}
+
+// readString reads a quoted string, template strings can't contain
+// placeholders.
+func readString(runes []rune) (string, int, error) {
+	quote := runes[0]
+	output := &strings.Builder{}
+	for i := 1; i < len(runes); i++ {
+		r := runes[i]
+		switch {
+		case r == quote:
+			return output.String(), i + 1, nil
+		case r == '\n' && quote != '`':
+			return "", 0, errors.New("unterminated string")
+		case r == '$' && quote == '`' && i+1 < len(runes) && runes[i+1] == '{':
+			return "", 0, errors.New("template placeholders are not supported")
+		case r == '\\' && i+1 < len(runes):
+			i++
+			switch runes[i] {
+			case 'n':
+				output.WriteRune('\n')
+			case 't':
+				output.WriteRune('\t')
+			case 'r':
+				output.WriteRune('\r')
+			case 'u':
+				if i+4 < len(runes) {
+					code, err := strconv.ParseUint(string(runes[i+1:i+5]), 16, 32)
+					if err == nil {
+						output.WriteRune(rune(code))
+						i += 4
+						continue
+					}
+				}
+				output.WriteRune('u')
+			default:
+				output.WriteRune(runes[i])
+			}
+		default:
+			output.WriteRune(r)
+		}
+	}
+	return "", 0, errors.New("unterminated string")
+}
@@ -0,0 +1,206 @@
+package runner
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/jerbob92/hoppscotch-backend/helpers/executor"
+	"github.com/jerbob92/hoppscotch-backend/helpers/hoppscotch"
+)
+
+// Step is a request of a collection run, with the properties of its
+// collection already applied.
+type Step struct {
+	ID        uint
+	Path      string
+	Request   *hoppscotch.RESTRequest
+	Variables map[string]string
+}
+
+// RequestResult is the result of a single request of a run. A request without
+// tests fails when the status isn't below 400, with tests the tests decide.
+// Scripts that are not supported are skipped, Unsupported tells why.
+type RequestResult struct {
+	ID          uint         `json:"id"`
+	Name        string       `json:"name"`
+	Path        string       `json:"path"`
+	Method      string       `json:"method"`
+	URL         string       `json:"url"`
+	Status      int          `json:"status"`
+	Duration    float64      `json:"duration"`
+	Passed      bool         `json:"passed"`
+	Error       string       `json:"error,omitempty"`
+	Unsupported string       `json:"unsupported,omitempty"`
+	Tests       []TestResult `json:"tests"`
+}
+
+// Report is the result of a collection run, durations are in milliseconds.
+type Report struct {
+	Name                 string            `json:"name"`
+	StartedAt            time.Time         `json:"startedAt"`
+	Duration             float64           `json:"duration"`
+	Passed               bool              `json:"passed"`
+	Total                int               `json:"total"`
+	Failed               int               `json:"failed"`
+	ExpectationsPassed   int               `json:"expectationsPassed"`
+	ExpectationsFailed   int               `json:"expectationsFailed"`
+	Requests             []RequestResult   `json:"requests"`
+	EnvironmentVariables map[string]string `json:"environmentVariables"`
+}
+
+// Run sends the requests in order. Variables set by the scripts of a request
+// are available to the requests after it, the variables of the environment
+// override the variables of the collections.
+func Run(ctx context.Context, name string, steps []Step, environment map[string]string, options executor.Options) *Report {
+	report := &Report{
+		Name:                 name,
+		StartedAt:            time.Now(),
+		Passed:               true,
+		Requests:             []RequestResult{},
+		EnvironmentVariables: map[string]string{},
+	}
+	for key, value := range environment {
+		report.EnvironmentVariables[key] = value
+	}
+
+	for _, step := range steps {
+		if ctx.Err() != nil {
+			break
+		}
+
+		result := runStep(ctx, step, report.EnvironmentVariables, options)
+		report.Requests = append(report.Requests, result)
+		report.Total++
+		if !result.Passed {
+			report.Failed++
+			report.Passed = false
+		}
+		for _, test := range result.Tests {
+			for _, expectation := range test.Expectations {
+				if expectation.Passed {
+					report.ExpectationsPassed++
+				} else {
+					report.ExpectationsFailed++
+				}
+			}
+		}
+	}
+
+	report.Duration = float64(time.Since(report.StartedAt)) / float64(time.Millisecond)
+
+	return report
+}
+
+func runStep(ctx context.Context, step Step, environment map[string]string, options executor.Options) RequestResult {
+	result := RequestResult{
+		ID:     step.ID,
+		Name:   step.Request.Name,
+		Path:   step.Path,
+		Method: step.Request.Method,
+		Tests:  []TestResult{},
+	}
+
+	variables := map[string]string{}
+	for key, value := range step.Variables {
+		variables[key] = value
+	}
+	for key, value := range environment {
+		variables[key] = value
+	}
+
+	// Changes made by scripts are kept for the next requests.
+	setVariables := func(before map[string]string) {
+		for key, value := range variables {
+			if previous, ok := before[key]; !ok || previous != value {
+				environment[key] = value
+			}
+		}
+		for key := range before {
+			if _, ok := variables[key]; !ok {
+				delete(environment, key)
+			}
+		}
+	}
+
+	if step.Request.PreRequestScript != "" {
+		before := copyVariables(variables)
+		_, err := RunScript(step.Request.PreRequestScript, variables, nil)
+		setVariables(before)
+		var unsupportedErr *UnsupportedError
+		if errors.As(err, &unsupportedErr) {
+			result.Unsupported = "pre-request script: " + unsupportedErr.Err.Error()
+		} else if err != nil {
+			result.Error = "pre-request script: " + err.Error()
+			return result
+		}
+	}
+
+	prepared := hoppscotch.Prepare(step.Request, variables)
+	result.Method = prepared.Method
+	result.URL = prepared.URL
+
+	response, err := executor.Execute(ctx, prepared, options)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	result.Status = response.Status
+	result.Duration = response.Timings.Total
+
+	result.Passed = response.Status < 400
+	if step.Request.TestScript == "" {
+		return result
+	}
+
+	before := copyVariables(variables)
+	tests, err := RunScript(step.Request.TestScript, variables, &ScriptResponse{
+		Status:  response.Status,
+		Headers: response.Headers,
+		Body:    response.Body,
+	})
+	setVariables(before)
+	result.Tests = tests
+	var unsupportedErr *UnsupportedError
+	if errors.As(err, &unsupportedErr) {
+		if result.Unsupported != "" {
+			result.Unsupported += "\n"
+		}
+		result.Unsupported += "test script: " + unsupportedErr.Err.Error()
+		return result
+	}
+	if err != nil {
+		result.Error = "test script: " + err.Error()
+		result.Passed = false
+		return result
+	}
+
+	if len(tests) > 0 {
+		result.Passed = true
+		for _, test := range tests {
+			if !test.Passed {
+				result.Passed = false
+			}
+		}
+	}
+
+	return result
+}
+
+func copyVariables(variables map[string]string) map[string]string {
+	output := map[string]string{}
+	for key, value := range variables {
+		output[key] = value
+	}
+	return output
+}
+
+// JSON returns the report as indented JSON.
+func (r *Report) JSON() (string, error) {
+	output, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(output), nil
+}
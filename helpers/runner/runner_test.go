@@ -0,0 +1,79 @@
+package runner
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jerbob92/hoppscotch-backend/helpers/executor"
+	"github.com/jerbob92/hoppscotch-backend/helpers/hoppscotch"
+)
+
+func TestRun(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/missing" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"token": "abc", "auth": "` + r.Header.Get("Authorization") + `"}`))
+	}))
+	defer server.Close()
+
+	policy, err := executor.NewPolicy([]string{"127.0.0.1"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	options := executor.Options{Policy: policy, Timeout: 5 * time.Second}
+
+	login := hoppscotch.NewRESTRequest("Login", "POST", "<<url>>/login")
+	login.TestScript = `pw.test("Token", () => { pw.expect(pw.response.status).toBe(200); pw.env.set("token", pw.response.body.token) })`
+
+	profile := hoppscotch.NewRESTRequest("Profile", "GET", "<<url>>/profile")
+	profile.Headers = []hoppscotch.KeyValue{{Key: "Authorization", Value: "Bearer <<token>>", Active: true}}
+	profile.TestScript = `pw.expect(pw.response.body.auth).toBe("Bearer abc")`
+
+	unsupported := hoppscotch.NewRESTRequest("Unsupported", "GET", "<<url>>/profile")
+	unsupported.TestScript = `for (const a of [1]) { pw.expect(a).toBe(2) }`
+
+	missing := hoppscotch.NewRESTRequest("Missing", "GET", "<<url>>/missing")
+
+	report := Run(context.Background(), "Smoke test", []Step{
+		{ID: 1, Request: login},
+		{ID: 2, Request: profile},
+		{ID: 3, Request: unsupported},
+		{ID: 4, Request: missing},
+	}, map[string]string{"url": server.URL}, options)
+
+	if report.Total != 4 || report.Failed != 1 || report.Passed {
+		t.Fatalf("total = %d, failed = %d, passed = %v, want 4, 1 and false: %+v", report.Total, report.Failed, report.Passed, report.Requests)
+	}
+	if !report.Requests[0].Passed || !report.Requests[1].Passed {
+		t.Errorf("the first two requests should pass: %+v", report.Requests[:2])
+	}
+	if report.EnvironmentVariables["token"] != "abc" {
+		t.Errorf("token = %q, want abc", report.EnvironmentVariables["token"])
+	}
+
+	result := report.Requests[2]
+	if !result.Passed || result.Error != "" || !strings.HasPrefix(result.Unsupported, "test script: ") {
+		t.Errorf("an unsupported script should be skipped, not fail: %+v", result)
+	}
+	if report.Requests[3].Passed || report.Requests[3].Status != http.StatusNotFound {
+		t.Errorf("the missing request should fail on its status: %+v", report.Requests[3])
+	}
+
+	junit, err := report.JUnit()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(junit, "<skipped message=\"test script: ") {
+		t.Errorf("the unsupported script should be a skipped test case:\n%s", junit)
+	}
+	if !strings.Contains(junit, `<testsuites name="Smoke test" tests="5" failures="1" errors="0"`) {
+		t.Errorf("unexpected totals:\n%s", junit)
+	}
+}
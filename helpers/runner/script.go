@@ -0,0 +1,1159 @@
+package runner
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/jerbob92/hoppscotch-backend/helpers/executor"
+	"github.com/jerbob92/hoppscotch-backend/helpers/hoppscotch"
+)
+
+// Scripts are run by a small interpreter that knows the part of JavaScript
+// that Hoppscotch scripts normally use: pw.test, pw.expect with its
+// matchers, pw.env, pw.response, variables, comparisons, if statements,
+// functions and JSON.
+
+const (
+	// maxScriptSize is the maximum size of a script in bytes.
+	maxScriptSize = 64 * 1024
+
+	// maxNesting limits how deep statements, expressions and function calls
+	// can be nested, so a script can't overflow the stack.
+	maxNesting = 256
+
+	// maxSteps limits the statements a script runs, recursive functions
+	// could run for a very long time otherwise.
+	maxSteps = 100000
+)
+
+// UnsupportedError is returned when a script uses JavaScript the interpreter
+// doesn't know. The script is checked before it runs, so an unsupported
+// script is not run at all.
+type UnsupportedError struct {
+	Err error
+}
+
+func (e *UnsupportedError) Error() string {
+	return "the script is not supported: " + e.Err.Error()
+}
+
+func (e *UnsupportedError) Unwrap() error {
+	return e.Err
+}
+
+// Expectation is the result of a single pw.expect call.
+type Expectation struct {
+	Message string `json:"message"`
+	Passed  bool   `json:"passed"`
+}
+
+// TestResult is a pw.test block, expectations outside of a block are part of
+// a test without a name. The error is set when the block stopped because of
+// an error.
+type TestResult struct {
+	Name         string        `json:"name"`
+	Passed       bool          `json:"passed"`
+	Expectations []Expectation `json:"expectations"`
+	Error        string        `json:"error,omitempty"`
+}
+
+// ScriptResponse is the response test scripts can read with pw.response.
+type ScriptResponse struct {
+	Status  int
+	Headers []executor.Header
+	Body    string
+}
+
+// namespace is a built-in object like pw or pw.env, the value is its path.
+type namespace string
+
+// function is a function literal, the body is the range of tokens between
+// the braces, or the expression of an arrow function without braces.
+type function struct {
+	params     []string
+	start      int
+	end        int
+	expression bool
+}
+
+// expectation is the value of pw.expect(), its members are the matchers.
+type expectation struct {
+	actual  interface{}
+	negated bool
+}
+
+type matcher struct {
+	expectation expectation
+	name        string
+}
+
+type interpreter struct {
+	tokens    []token
+	pos       int
+	end       int
+	variables map[string]string
+	locals    map[string]interface{}
+	response  interface{}
+	tests     []*TestResult
+	current   *TestResult
+
+	// skip is above 0 while tokens are only parsed, see skipping.
+	skip          int
+	returning     bool
+	returnValue   interface{}
+	functionDepth int
+	depth         int
+	steps         int
+}
+
+// RunScript runs a pre-request or test script, pw.env changes are made to the
+// variables. The response is nil for pre-request scripts. The error is set
+// when the script could not be run to the end, the tests that ran are
+// returned anyway.
+func RunScript(source string, variables map[string]string, response *ScriptResponse) ([]TestResult, error) {
+	if len(source) > maxScriptSize {
+		return []TestResult{}, &UnsupportedError{Err: errors.New("scripts can be at most " + strconv.Itoa(maxScriptSize) + " bytes")}
+	}
+
+	tokens, err := tokenize(source)
+	if err != nil {
+		return []TestResult{}, &UnsupportedError{Err: err}
+	}
+
+	i := &interpreter{
+		tokens:    tokens,
+		end:       len(tokens) - 1,
+		variables: variables,
+		locals:    map[string]interface{}{},
+	}
+	if response != nil {
+		i.response = scriptResponseValue(response)
+	}
+
+	if err := i.check(); err != nil {
+		return []TestResult{}, &UnsupportedError{Err: err}
+	}
+
+	for i.pos < i.end && err == nil {
+		err = i.statement()
+	}
+
+	results := []TestResult{}
+	for _, test := range i.tests {
+		results = append(results, *test)
+	}
+
+	return results, err
+}
+
+func scriptResponseValue(response *ScriptResponse) map[string]interface{} {
+	headers := []interface{}{}
+	for _, header := range response.Headers {
+		headers = append(headers, map[string]interface{}{"key": header.Key, "value": header.Value})
+	}
+
+	var body interface{} = response.Body
+	var parsed interface{}
+	if err := json.Unmarshal([]byte(response.Body), &parsed); err == nil {
+		body = parsed
+	}
+
+	return map[string]interface{}{
+		"status":  float64(response.Status),
+		"headers": headers,
+		"body":    body,
+	}
+}
+
+// check parses the whole script without running it, so a script the
+// interpreter doesn't understand is reported before anything has run.
+func (i *interpreter) check() error {
+	i.skip++
+	defer func() {
+		i.skip--
+		i.pos = 0
+	}()
+
+	for i.pos < i.end {
+		if err := i.statement(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// skipping returns whether the tokens are only parsed and not run. That is
+// the case for branches that are not taken, the right side of && and || when
+// the left side decides, the rest of a function after return, and while the
+// script is checked.
+func (i *interpreter) skipping() bool {
+	return i.skip > 0 || i.returning
+}
+
+func (i *interpreter) enter() error {
+	if i.depth >= maxNesting {
+		return i.errorf("the script is nested too deeply")
+	}
+	i.depth++
+	return nil
+}
+
+func (i *interpreter) leave() {
+	i.depth--
+}
+
+// step counts a statement or function call that runs.
+func (i *interpreter) step() error {
+	i.steps++
+	if i.steps > maxSteps {
+		return i.errorf("the script ran more than " + strconv.Itoa(maxSteps) + " statements and calls")
+	}
+	return nil
+}
+
+func (i *interpreter) peek() token {
+	if i.pos >= i.end {
+		return i.tokens[len(i.tokens)-1]
+	}
+	return i.tokens[i.pos]
+}
+
+func (i *interpreter) next() token {
+	t := i.peek()
+	if i.pos < i.end {
+		i.pos++
+	}
+	return t
+}
+
+func (i *interpreter) is(value string) bool {
+	t := i.peek()
+	return (t.kind == tokenPunctuation || t.kind == tokenIdentifier) && t.value == value
+}
+
+func (i *interpreter) errorf(message string) error {
+	return errors.New("line " + strconv.Itoa(i.peek().line) + ": " + message)
+}
+
+func (i *interpreter) expect(value string) error {
+	if !i.is(value) {
+		found := i.peek().value
+		if i.peek().kind == tokenEOF {
+			found = "end of script"
+		}
+		return i.errorf("expected " + value + " but found " + found)
+	}
+	i.next()
+	return nil
+}
+
+func (i *interpreter) statement() error {
+	if err := i.enter(); err != nil {
+		return err
+	}
+	defer i.leave()
+
+	if !i.skipping() {
+		if err := i.step(); err != nil {
+			return err
+		}
+	}
+
+	switch {
+	case i.is(";"):
+		i.next()
+		return nil
+	case i.is("{"):
+		return i.block()
+	case i.is("if"):
+		return i.ifStatement()
+	case i.is("return"):
+		return i.returnStatement()
+	}
+
+	if i.is("const") || i.is("let") || i.is("var") {
+		i.next()
+		name := i.next()
+		if name.kind != tokenIdentifier {
+			return i.errorf("expected a variable name")
+		}
+		if err := i.expect("="); err != nil {
+			return err
+		}
+		value, err := i.expression()
+		if err != nil {
+			return err
+		}
+		if !i.skipping() {
+			i.locals[name.value] = value
+		}
+	} else {
+		_, err := i.expression()
+		if err != nil {
+			return err
+		}
+	}
+
+	if i.is(";") {
+		i.next()
+	}
+	return nil
+}
+
+func (i *interpreter) block() error {
+	i.next()
+	for !i.is("}") {
+		if i.peek().kind == tokenEOF {
+			return i.errorf("expected } but found end of script")
+		}
+		if err := i.statement(); err != nil {
+			return err
+		}
+	}
+	i.next()
+	return nil
+}
+
+func (i *interpreter) ifStatement() error {
+	i.next()
+	if err := i.expect("("); err != nil {
+		return err
+	}
+	condition, err := i.expression()
+	if err != nil {
+		return err
+	}
+	if err := i.expect(")"); err != nil {
+		return err
+	}
+
+	taken := truthy(condition)
+	if err := i.branch(taken); err != nil {
+		return err
+	}
+
+	if i.is("else") {
+		i.next()
+		return i.branch(!taken)
+	}
+	return nil
+}
+
+// branch runs the next statement, or only parses it when the branch is not
+// taken.
+func (i *interpreter) branch(taken bool) error {
+	if !taken {
+		i.skip++
+		defer func() { i.skip-- }()
+	}
+	return i.statement()
+}
+
+func (i *interpreter) returnStatement() error {
+	if i.functionDepth == 0 {
+		return i.errorf("return is only allowed in functions")
+	}
+
+	line := i.next().line
+	var value interface{}
+	if !i.is(";") && !i.is("}") && i.peek().kind != tokenEOF && i.peek().line == line {
+		var err error
+		value, err = i.expression()
+		if err != nil {
+			return err
+		}
+	}
+	if i.is(";") {
+		i.next()
+	}
+
+	// The rest of the function is skipped until the call returns.
+	if !i.skipping() {
+		i.returning = true
+		i.returnValue = value
+	}
+	return nil
+}
+
+func (i *interpreter) expression() (interface{}, error) {
+	if err := i.enter(); err != nil {
+		return nil, err
+	}
+	defer i.leave()
+
+	return i.or()
+}
+
+func (i *interpreter) or() (interface{}, error) {
+	left, err := i.and()
+	if err != nil {
+		return nil, err
+	}
+	for i.is("||") {
+		i.next()
+
+		// The right side is not evaluated when the left side is truthy.
+		decided := truthy(left)
+		if decided {
+			i.skip++
+		}
+		right, err := i.and()
+		if decided {
+			i.skip--
+		}
+		if err != nil {
+			return nil, err
+		}
+		if !decided {
+			left = right
+		}
+	}
+	return left, nil
+}
+
+func (i *interpreter) and() (interface{}, error) {
+	left, err := i.equality()
+	if err != nil {
+		return nil, err
+	}
+	for i.is("&&") {
+		i.next()
+
+		// The right side is not evaluated when the left side is falsy.
+		decided := !truthy(left)
+		if decided {
+			i.skip++
+		}
+		right, err := i.equality()
+		if decided {
+			i.skip--
+		}
+		if err != nil {
+			return nil, err
+		}
+		if !decided {
+			left = right
+		}
+	}
+	return left, nil
+}
+
+func (i *interpreter) equality() (interface{}, error) {
+	left, err := i.comparison()
+	if err != nil {
+		return nil, err
+	}
+	for i.is("===") || i.is("==") || i.is("!==") || i.is("!=") {
+		operator := i.next().value
+		right, err := i.comparison()
+		if err != nil {
+			return nil, err
+		}
+		equal := valuesEqual(left, right)
+		if operator == "!==" || operator == "!=" {
+			equal = !equal
+		}
+		left = equal
+	}
+	return left, nil
+}
+
+func (i *interpreter) comparison() (interface{}, error) {
+	left, err := i.additive()
+	if err != nil {
+		return nil, err
+	}
+	for i.is("<") || i.is(">") || i.is("<=") || i.is(">=") {
+		operator := i.next().value
+		right, err := i.additive()
+		if err != nil {
+			return nil, err
+		}
+
+		var comparison int
+		leftString, leftIsString := left.(string)
+		rightString, rightIsString := right.(string)
+		if leftIsString && rightIsString {
+			comparison = strings.Compare(leftString, rightString)
+		} else {
+			leftNumber, leftOK := left.(float64)
+			rightNumber, rightOK := right.(float64)
+			if !leftOK || !rightOK {
+				left = false
+				continue
+			}
+			if leftNumber < rightNumber {
+				comparison = -1
+			} else if leftNumber > rightNumber {
+				comparison = 1
+			}
+		}
+
+		switch operator {
+		case "<":
+			left = comparison < 0
+		case ">":
+			left = comparison > 0
+		case "<=":
+			left = comparison <= 0
+		case ">=":
+			left = comparison >= 0
+		}
+	}
+	return left, nil
+}
+
+func (i *interpreter) additive() (interface{}, error) {
+	left, err := i.multiplicative()
+	if err != nil {
+		return nil, err
+	}
+	for i.is("+") || i.is("-") {
+		operator := i.next().value
+		right, err := i.multiplicative()
+		if err != nil {
+			return nil, err
+		}
+
+		_, leftIsString := left.(string)
+		_, rightIsString := right.(string)
+		if operator == "+" && (leftIsString || rightIsString) {
+			left = formatValue(left) + formatValue(right)
+			continue
+		}
+
+		leftNumber, leftOK := left.(float64)
+		rightNumber, rightOK := right.(float64)
+		if !leftOK || !rightOK {
+			if i.skipping() {
+				left = nil
+				continue
+			}
+			return nil, i.errorf("can only " + operator + " numbers")
+		}
+		if operator == "+" {
+			left = leftNumber + rightNumber
+		} else {
+			left = leftNumber - rightNumber
+		}
+	}
+	return left, nil
+}
+
+func (i *interpreter) multiplicative() (interface{}, error) {
+	left, err := i.unary()
+	if err != nil {
+		return nil, err
+	}
+	for i.is("*") || i.is("/") {
+		operator := i.next().value
+		right, err := i.unary()
+		if err != nil {
+			return nil, err
+		}
+
+		leftNumber, leftOK := left.(float64)
+		rightNumber, rightOK := right.(float64)
+		if !leftOK || !rightOK {
+			if i.skipping() {
+				left = nil
+				continue
+			}
+			return nil, i.errorf("can only " + operator + " numbers")
+		}
+		if operator == "*" {
+			left = leftNumber * rightNumber
+		} else {
+			left = leftNumber / rightNumber
+		}
+	}
+	return left, nil
+}
+
+func (i *interpreter) unary() (interface{}, error) {
+	if err := i.enter(); err != nil {
+		return nil, err
+	}
+	defer i.leave()
+
+	if i.is("!") {
+		i.next()
+		value, err := i.unary()
+		if err != nil {
+			return nil, err
+		}
+		return !truthy(value), nil
+	}
+
+	if i.is("-") {
+		i.next()
+		value, err := i.unary()
+		if err != nil {
+			return nil, err
+		}
+		number, ok := value.(float64)
+		if !ok {
+			if i.skipping() {
+				return nil, nil
+			}
+			return nil, i.errorf("can only negate numbers")
+		}
+		return -number, nil
+	}
+
+	return i.postfix()
+}
+
+func (i *interpreter) postfix() (interface{}, error) {
+	value, err := i.primary()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		switch {
+		case i.is("."):
+			i.next()
+			name := i.next()
+			if name.kind != tokenIdentifier {
+				return nil, i.errorf("expected a property name")
+			}
+			value, err = i.member(value, name.value)
+		case i.is("["):
+			i.next()
+			var index interface{}
+			index, err = i.expression()
+			if err != nil {
+				return nil, err
+			}
+			if err := i.expect("]"); err != nil {
+				return nil, err
+			}
+			value, err = i.member(value, formatValue(index))
+		case i.is("("):
+			i.next()
+			var arguments []interface{}
+			arguments, err = i.arguments()
+			if err != nil {
+				return nil, err
+			}
+			value, err = i.call(value, arguments)
+		default:
+			return value, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+func (i *interpreter) arguments() ([]interface{}, error) {
+	arguments := []interface{}{}
+	for !i.is(")") {
+		argument, err := i.expression()
+		if err != nil {
+			return nil, err
+		}
+		arguments = append(arguments, argument)
+		if !i.is(",") {
+			break
+		}
+		i.next()
+	}
+	return arguments, i.expect(")")
+}
+
+func (i *interpreter) primary() (interface{}, error) {
+	if function, ok, err := i.function(); ok || err != nil {
+		return function, err
+	}
+
+	t := i.next()
+	switch t.kind {
+	case tokenNumber:
+		number, err := strconv.ParseFloat(t.value, 64)
+		if err != nil {
+			return nil, i.errorf("invalid number " + t.value)
+		}
+		return number, nil
+	case tokenString:
+		return t.value, nil
+	case tokenIdentifier:
+		switch t.value {
+		case "true":
+			return true, nil
+		case "false":
+			return false, nil
+		case "null", "undefined":
+			return nil, nil
+		case "pw", "JSON", "console":
+			return namespace(t.value), nil
+		}
+		value, ok := i.locals[t.value]
+		if !ok {
+			if i.skipping() {
+				return nil, nil
+			}
+			return nil, errors.New("line " + strconv.Itoa(t.line) + ": " + t.value + " is not defined")
+		}
+		return value, nil
+	case tokenPunctuation:
+		if t.value == "(" {
+			value, err := i.expression()
+			if err != nil {
+				return nil, err
+			}
+			return value, i.expect(")")
+		}
+		if t.value == "[" {
+			list := []interface{}{}
+			for !i.is("]") {
+				item, err := i.expression()
+				if err != nil {
+					return nil, err
+				}
+				list = append(list, item)
+				if !i.is(",") {
+					break
+				}
+				i.next()
+			}
+			return list, i.expect("]")
+		}
+	case tokenEOF:
+		return nil, i.errorf("unexpected end of script")
+	}
+
+	return nil, errors.New("line " + strconv.Itoa(t.line) + ": unexpected " + t.value)
+}
+
+// function parses a function literal, like () => {}, item => item.id or
+// function (a, b) {}, without running it. The body is only checked. A named
+// function is declared as a variable.
+func (i *interpreter) function() (interface{}, bool, error) {
+	start := i.pos
+	if i.is("async") {
+		i.next()
+	}
+
+	body := function{params: []string{}}
+	name := ""
+	arrow := true
+	switch {
+	case i.is("function"):
+		i.next()
+		if i.peek().kind == tokenIdentifier {
+			name = i.next().value
+		}
+		if !i.is("(") {
+			i.pos = start
+			return nil, false, nil
+		}
+		i.next()
+		params, err := i.parameters()
+		if err != nil {
+			return nil, true, err
+		}
+		body.params = params
+		arrow = false
+	case i.peek().kind == tokenIdentifier && i.punctuationAt(i.pos+1, "=>"):
+		body.params = append(body.params, i.next().value)
+	case i.is("(") && i.isArrowFunction():
+		i.next()
+		params, err := i.parameters()
+		if err != nil {
+			return nil, true, err
+		}
+		body.params = params
+	default:
+		i.pos = start
+		return nil, false, nil
+	}
+
+	if arrow {
+		if err := i.expect("=>"); err != nil {
+			return nil, true, err
+		}
+	}
+
+	i.skip++
+	i.functionDepth++
+	var err error
+	switch {
+	case i.is("{"):
+		body.start = i.pos + 1
+		err = i.block()
+		body.end = i.pos - 1
+	case arrow:
+		body.start = i.pos
+		body.expression = true
+		_, err = i.expression()
+		body.end = i.pos
+	default:
+		err = i.errorf("expected { after the function")
+	}
+	i.functionDepth--
+	i.skip--
+	if err != nil {
+		return nil, true, err
+	}
+
+	if name != "" && !i.skipping() {
+		i.locals[name] = body
+	}
+
+	return body, true, nil
+}
+
+func (i *interpreter) punctuationAt(pos int, value string) bool {
+	return pos < i.end && i.tokens[pos].kind == tokenPunctuation && i.tokens[pos].value == value
+}
+
+// isArrowFunction returns whether the parenthesis at the current position
+// starts the parameters of an arrow function.
+func (i *interpreter) isArrowFunction() bool {
+	pos := i.pos + 1
+	for pos < i.end && (i.tokens[pos].kind == tokenIdentifier || i.punctuationAt(pos, ",")) {
+		pos++
+	}
+	return i.punctuationAt(pos, ")") && i.punctuationAt(pos+1, "=>")
+}
+
+// parameters reads the parameter names after the opening parenthesis,
+// default values and destructuring are not supported.
+func (i *interpreter) parameters() ([]string, error) {
+	params := []string{}
+	for !i.is(")") {
+		name := i.next()
+		if name.kind != tokenIdentifier {
+			return nil, i.errorf("expected a parameter name")
+		}
+		params = append(params, name.value)
+		if !i.is(",") {
+			break
+		}
+		i.next()
+	}
+	return params, i.expect(")")
+}
+
+// callFunction runs a function literal with the arguments as its parameters,
+// variables declared in the function are not visible outside of it.
+func (i *interpreter) callFunction(body function, arguments []interface{}) (interface{}, error) {
+	if err := i.step(); err != nil {
+		return nil, err
+	}
+
+	locals := i.locals
+	i.locals = map[string]interface{}{}
+	for key, value := range locals {
+		i.locals[key] = value
+	}
+	for index, param := range body.params {
+		var value interface{}
+		if index < len(arguments) {
+			value = arguments[index]
+		}
+		i.locals[param] = value
+	}
+
+	pos, end := i.pos, i.end
+	i.pos, i.end = body.start, body.end
+	i.functionDepth++
+
+	var value interface{}
+	var err error
+	if body.expression {
+		value, err = i.expression()
+	} else {
+		for i.pos < i.end && err == nil {
+			err = i.statement()
+		}
+		value = i.returnValue
+	}
+
+	i.functionDepth--
+	i.returning, i.returnValue = false, nil
+	i.pos, i.end = pos, end
+	i.locals = locals
+
+	return value, err
+}
+
+func (i *interpreter) member(value interface{}, name string) (interface{}, error) {
+	// Only the names of built-ins are checked when skipping.
+	if _, ok := value.(namespace); !ok && i.skipping() {
+		return nil, nil
+	}
+
+	switch typedValue := value.(type) {
+	case namespace:
+		path := string(typedValue) + "." + name
+		switch path {
+		case "pw.response":
+			if i.skipping() {
+				return nil, nil
+			}
+			if i.response == nil {
+				return nil, i.errorf("pw.response is only available in test scripts")
+			}
+			return i.response, nil
+		case "pw.env", "pw.test", "pw.expect", "pw.env.set", "pw.env.get", "pw.env.unset", "pw.env.resolve", "pw.env.getResolve", "JSON.parse", "JSON.stringify", "console.log", "console.info", "console.warn", "console.error":
+			return namespace(path), nil
+		}
+		return nil, i.errorf(path + " is not supported")
+	case expectation:
+		if name == "not" {
+			typedValue.negated = !typedValue.negated
+			return typedValue, nil
+		}
+		return matcher{expectation: typedValue, name: name}, nil
+	case map[string]interface{}:
+		return typedValue[name], nil
+	case []interface{}:
+		if name == "length" {
+			return float64(len(typedValue)), nil
+		}
+		index, err := strconv.Atoi(name)
+		if err != nil || index < 0 || index >= len(typedValue) {
+			return nil, nil
+		}
+		return typedValue[index], nil
+	case string:
+		if name == "length" {
+			return float64(len([]rune(typedValue))), nil
+		}
+		return nil, nil
+	case nil:
+		return nil, i.errorf("cannot read property " + name + " of undefined")
+	}
+	return nil, nil
+}
+
+func (i *interpreter) call(value interface{}, arguments []interface{}) (interface{}, error) {
+	if i.skipping() {
+		return nil, nil
+	}
+
+	argument := func(index int) interface{} {
+		if index < len(arguments) {
+			return arguments[index]
+		}
+		return nil
+	}
+
+	switch typedValue := value.(type) {
+	case namespace:
+		switch typedValue {
+		case "pw.test":
+			body, ok := argument(1).(function)
+			if !ok {
+				return nil, i.errorf("pw.test needs a name and a function")
+			}
+			return nil, i.test(formatValue(argument(0)), body)
+		case "pw.expect":
+			return expectation{actual: argument(0)}, nil
+		case "pw.env.set":
+			key, ok := argument(0).(string)
+			if !ok {
+				return nil, i.errorf("pw.env.set needs a string key")
+			}
+			stringValue, ok := argument(1).(string)
+			if !ok {
+				return nil, i.errorf("pw.env.set needs a string value")
+			}
+			i.variables[key] = stringValue
+			return nil, nil
+		case "pw.env.unset":
+			delete(i.variables, formatValue(argument(0)))
+			return nil, nil
+		case "pw.env.get":
+			if value, ok := i.variables[formatValue(argument(0))]; ok {
+				return value, nil
+			}
+			return nil, nil
+		case "pw.env.getResolve":
+			if value, ok := i.variables[formatValue(argument(0))]; ok {
+				return hoppscotch.ReplaceVariables(value, i.variables), nil
+			}
+			return nil, nil
+		case "pw.env.resolve":
+			return hoppscotch.ReplaceVariables(formatValue(argument(0)), i.variables), nil
+		case "JSON.parse":
+			var parsed interface{}
+			if err := json.Unmarshal([]byte(formatValue(argument(0))), &parsed); err != nil {
+				return nil, i.errorf("JSON.parse: " + err.Error())
+			}
+			return parsed, nil
+		case "JSON.stringify":
+			return jsonValue(argument(0)), nil
+		case "console.log", "console.info", "console.warn", "console.error":
+			return nil, nil
+		}
+	case matcher:
+		return nil, i.match(typedValue, arguments)
+	case function:
+		return i.callFunction(typedValue, arguments)
+	}
+
+	return nil, i.errorf("this is not a function")
+}
+
+// test runs the body of a pw.test block. Errors stop the block, but not the
+// rest of the script.
+func (i *interpreter) test(name string, body function) error {
+	if i.current != nil {
+		return i.errorf("pw.test can't be nested")
+	}
+
+	test := &TestResult{Name: name, Passed: true, Expectations: []Expectation{}}
+	i.tests = append(i.tests, test)
+	i.current = test
+
+	_, err := i.callFunction(body, nil)
+	if err != nil {
+		test.Error = err.Error()
+		test.Passed = false
+	}
+	i.current = nil
+
+	return nil
+}
+
+func (i *interpreter) match(m matcher, arguments []interface{}) error {
+	actual := m.expectation.actual
+	var expected interface{}
+	if len(arguments) > 0 {
+		expected = arguments[0]
+	}
+
+	not := ""
+	if m.expectation.negated {
+		not = "not "
+	}
+
+	var passed bool
+	var message string
+	switch m.name {
+	case "toBe":
+		passed = valuesEqual(actual, expected)
+		message = "Expected '" + formatValue(actual) + "' to " + not + "be '" + formatValue(expected) + "'"
+	case "toBeLevel2xx", "toBeLevel3xx", "toBeLevel4xx", "toBeLevel5xx":
+		level := float64(m.name[9] - '0')
+		status, ok := actual.(float64)
+		passed = ok && status >= level*100 && status < (level+1)*100
+		message = "Expected '" + formatValue(actual) + "' to " + not + "be " + m.name[9:10] + "00-level status"
+	case "toBeType":
+		passed = typeOf(actual) == formatValue(expected)
+		message = "Expected '" + formatValue(actual) + "' to " + not + "be type '" + formatValue(expected) + "'"
+	case "toHaveLength":
+		length := -1.0
+		switch typedActual := actual.(type) {
+		case string:
+			length = float64(len([]rune(typedActual)))
+		case []interface{}:
+			length = float64(len(typedActual))
+		}
+		passed = valuesEqual(length, expected)
+		message = "Expected the length to " + not + "be '" + formatValue(expected) + "'"
+	case "toInclude":
+		switch typedActual := actual.(type) {
+		case string:
+			passed = strings.Contains(typedActual, formatValue(expected))
+		case []interface{}:
+			for _, item := range typedActual {
+				if valuesEqual(item, expected) {
+					passed = true
+				}
+			}
+		}
+		message = "Expected '" + formatValue(actual) + "' to " + not + "include '" + formatValue(expected) + "'"
+	default:
+		return i.errorf("the matcher " + m.name + " is not supported")
+	}
+
+	if m.expectation.negated {
+		passed = !passed
+	}
+
+	test := i.current
+	if test == nil {
+		// Expectations outside of a test are part of an unnamed test.
+		if len(i.tests) == 0 || i.tests[0].Name != "" {
+			i.tests = append([]*TestResult{{Passed: true, Expectations: []Expectation{}}}, i.tests...)
+		}
+		test = i.tests[0]
+	}
+
+	test.Expectations = append(test.Expectations, Expectation{Message: message, Passed: passed})
+	if !passed {
+		test.Passed = false
+	}
+
+	return nil
+}
+
+func truthy(value interface{}) bool {
+	switch typedValue := value.(type) {
+	case nil:
+		return false
+	case bool:
+		return typedValue
+	case float64:
+		return typedValue != 0
+	case string:
+		return typedValue != ""
+	}
+	return true
+}
+
+func valuesEqual(left interface{}, right interface{}) bool {
+	return reflect.DeepEqual(left, right)
+}
+
+func typeOf(value interface{}) string {
+	switch value.(type) {
+	case nil:
+		return "undefined"
+	case bool:
+		return "boolean"
+	case float64:
+		return "number"
+	case string:
+		return "string"
+	case function, namespace, matcher:
+		return "function"
+	}
+	return "object"
+}
+
+// formatValue converts a value to a string the way JavaScript would show it
+// in a message.
+func formatValue(value interface{}) string {
+	switch typedValue := value.(type) {
+	case nil:
+		return "undefined"
+	case string:
+		return typedValue
+	case float64:
+		return strconv.FormatFloat(typedValue, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(typedValue)
+	}
+	return jsonValue(value)
+}
+
+func jsonValue(value interface{}) string {
+	switch value.(type) {
+	case function, namespace, matcher, expectation:
+		return "undefined"
+	}
+	output, err := json.Marshal(value)
+	if err != nil {
+		return "undefined"
+	}
+	return string(output)
+}
@@ -0,0 +1,295 @@
+package runner
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jerbob92/hoppscotch-backend/helpers/executor"
+)
+
+func testResponse() *ScriptResponse {
+	return &ScriptResponse{
+		Status:  200,
+		Headers: []executor.Header{{Key: "Content-Type", Value: "application/json"}},
+		Body:    `{"user": {"name": "Alice", "tags": ["a", "b"]}, "empty": null}`,
+	}
+}
+
+// expectationResults returns the passed state of every expectation, in
+// order.
+func expectationResults(tests []TestResult) []bool {
+	results := []bool{}
+	for _, test := range tests {
+		for _, expectation := range test.Expectations {
+			results = append(results, expectation.Passed)
+		}
+	}
+	return results
+}
+
+func TestRunScript(t *testing.T) {
+	tests := []struct {
+		name   string
+		script string
+		want   []bool
+	}{
+		{
+			name:   "status",
+			script: `pw.test("Status", () => { pw.expect(pw.response.status).toBe(200); pw.expect(pw.response.status).toBeLevel2xx(); });`,
+			want:   []bool{true, true},
+		},
+		{
+			name:   "body",
+			script: `const body = pw.response.body; pw.expect(body.user.name).toBe("Alice"); pw.expect(body.user.tags).toHaveLength(2); pw.expect(body.user.tags).toInclude("b")`,
+			want:   []bool{true, true, true},
+		},
+		{
+			name:   "not",
+			script: `pw.expect(pw.response.status).not.toBe(404)`,
+			want:   []bool{true},
+		},
+		{
+			name:   "and short-circuits",
+			script: `const e = pw.response.body.empty; pw.expect(e && e.a).toBe(null); pw.expect(pw.response.body.user && pw.response.body.user.name).toBe("Alice")`,
+			want:   []bool{true, true},
+		},
+		{
+			name:   "or short-circuits",
+			script: `const u = pw.response.body.user; pw.expect(u || u.missing.name).toBe(u); pw.expect(null || "default").toBe("default")`,
+			want:   []bool{true, true},
+		},
+		{
+			name: "if else",
+			script: `
+				if (pw.response.status === 200) {
+					pw.expect(1).toBe(1);
+				} else {
+					pw.expect(1).toBe(2);
+				}
+				if (pw.response.status === 404) pw.expect(1).toBe(2)
+				else if (pw.response.status === 200) pw.expect(2).toBe(2)`,
+			want: []bool{true, true},
+		},
+		{
+			name:   "branch not taken is not run",
+			script: `if (false) { pw.expect(undefinedVariable.a).toBe(1) }`,
+			want:   []bool{},
+		},
+		{
+			name: "functions with parameters",
+			script: `
+				function checkName(user, name) {
+					pw.expect(user.name).toBe(name);
+				}
+				const isOK = (status) => status >= 200 && status < 300;
+				const double = n => n * 2;
+				checkName(pw.response.body.user, "Alice");
+				pw.expect(isOK(pw.response.status)).toBe(true);
+				pw.expect(double(21)).toBe(42);`,
+			want: []bool{true, true, true},
+		},
+		{
+			name: "return",
+			script: `
+				const sign = function (n) {
+					if (n < 0) {
+						return "negative";
+					}
+					return "positive";
+					pw.expect(1).toBe(2);
+				};
+				pw.expect(sign(-1)).toBe("negative");
+				pw.expect(sign(1)).toBe("positive");`,
+			want: []bool{true, true},
+		},
+		{
+			name: "recursion",
+			script: `
+				const factorial = n => n <= 1 && 1 || n * factorial(n - 1);
+				pw.expect(factorial(5)).toBe(120);`,
+			want: []bool{true},
+		},
+		{
+			name:   "variables in functions don't leak",
+			script: `const inner = 1; const f = () => { const inner = 2; return inner }; pw.expect(f()).toBe(2); pw.expect(inner).toBe(1)`,
+			want:   []bool{true, true},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			results, err := RunScript(test.script, map[string]string{}, testResponse())
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			got := expectationResults(results)
+			if len(got) != len(test.want) {
+				t.Fatalf("got %v, want %v (%+v)", got, test.want, results)
+			}
+			for index := range got {
+				if got[index] != test.want[index] {
+					t.Fatalf("got %v, want %v (%+v)", got, test.want, results)
+				}
+			}
+		})
+	}
+}
+
+func TestRunScriptTestErrors(t *testing.T) {
+	results, err := RunScript(`
+		pw.test("broken", () => { pw.expect(missing).toBe(1) });
+		pw.test("fine", () => { pw.expect(1).toBe(1) });`, map[string]string{}, testResponse())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("got %d tests, want 2", len(results))
+	}
+	if results[0].Passed || !strings.Contains(results[0].Error, "missing is not defined") {
+		t.Errorf("the first test should fail with an error, got %+v", results[0])
+	}
+	if !results[1].Passed {
+		t.Errorf("the second test should pass, got %+v", results[1])
+	}
+}
+
+func TestRunScriptEnvironment(t *testing.T) {
+	variables := map[string]string{"host": "example.com", "remove": "x"}
+	_, err := RunScript(`
+		pw.env.set("token", "abc");
+		pw.env.set("url", "https://<<host>>/");
+		pw.env.unset("remove");
+		if (pw.env.get("token") === "abc") pw.env.set("checked", pw.env.resolve("<<host>>"));`, variables, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if variables["token"] != "abc" || variables["checked"] != "example.com" {
+		t.Errorf("unexpected variables %v", variables)
+	}
+	if _, ok := variables["remove"]; ok {
+		t.Error("remove should be unset")
+	}
+}
+
+func TestRunScriptUnsupported(t *testing.T) {
+	scripts := map[string]string{
+		"loop":                  `for (let i = 0; i < 3; i++) { pw.expect(i).toBe(i) }`,
+		"template placeholder":  "const a = `${pw.response.status}`",
+		"unknown api":           `pw.sendRequest("https://example.com")`,
+		"object literal":        `const a = {b: 1}`,
+		"default parameter":     `const f = (a = 1) => a`,
+		"return outside":        `return 1`,
+		"unterminated function": `pw.test("a", () => { pw.expect(1).toBe(1)`,
+		"too large":             strings.Repeat(" ", maxScriptSize+1),
+	}
+
+	for name, script := range scripts {
+		t.Run(name, func(t *testing.T) {
+			variables := map[string]string{}
+			// Nothing may run before the script is rejected.
+			results, err := RunScript(`pw.env.set("ran", "yes");`+script, variables, testResponse())
+			var unsupportedErr *UnsupportedError
+			if !errors.As(err, &unsupportedErr) {
+				t.Fatalf("expected UnsupportedError, got %v", err)
+			}
+			if len(results) != 0 || variables["ran"] != "" {
+				t.Errorf("the script should not have run, got %+v and %v", results, variables)
+			}
+		})
+	}
+}
+
+func TestRunScriptLimits(t *testing.T) {
+	scripts := map[string]string{
+		"parentheses": "pw.expect(" + strings.Repeat("(", 5000) + "1" + strings.Repeat(")", 5000) + ").toBe(1)",
+		"not":         "pw.expect(" + strings.Repeat("!", 20000) + "true).toBe(true)",
+		"minus":       "pw.expect(" + strings.Repeat("-", 20000) + "1).toBe(1)",
+		"blocks":      strings.Repeat("{", 5000) + strings.Repeat("}", 5000),
+		"functions":   strings.Repeat("() => ", 5000) + "1",
+	}
+
+	for name, script := range scripts {
+		t.Run(name, func(t *testing.T) {
+			_, err := RunScript(script, map[string]string{}, testResponse())
+			if err == nil || !strings.Contains(err.Error(), "nested too deeply") {
+				t.Errorf("expected a nesting error, got %v", err)
+			}
+		})
+	}
+
+	t.Run("endless recursion", func(t *testing.T) {
+		_, err := RunScript(`const f = n => f(n) + f(n); f(1)`, map[string]string{}, testResponse())
+		if err == nil {
+			t.Error("expected an error")
+		}
+	})
+
+	t.Run("exponential recursion", func(t *testing.T) {
+		start := time.Now()
+		_, err := RunScript(`function f(n) { if (n > 0) { f(n - 1); f(n - 1) } } f(30)`, map[string]string{}, testResponse())
+		if err == nil || !strings.Contains(err.Error(), "statements and calls") {
+			t.Errorf("expected a step limit error, got %v", err)
+		}
+		if time.Since(start) > 5*time.Second {
+			t.Errorf("the step limit took %v", time.Since(start))
+		}
+	})
+}
+
+func TestTokenizeIsLinear(t *testing.T) {
+	script := strings.Repeat(`pw.expect(pw.response.status).toBe(200);`+"\n", maxScriptSize/42)
+
+	start := time.Now()
+	if _, err := tokenize(script); err != nil {
+		t.Fatal(err)
+	}
+	if time.Since(start) > time.Second {
+		t.Errorf("tokenizing %d bytes took %v", len(script), time.Since(start))
+	}
+
+	start = time.Now()
+	results, err := RunScript(script, map[string]string{}, testResponse())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(expectationResults(results)) != maxScriptSize/42 {
+		t.Errorf("got %d expectations", len(expectationResults(results)))
+	}
+	if time.Since(start) > 2*time.Second {
+		t.Errorf("running %d bytes took %v", len(script), time.Since(start))
+	}
+}
+
+func TestTokenize(t *testing.T) {
+	tokens, err := tokenize("a === 'b\\n' // comment\n/* block\ncomment */ c >= 1.5")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []token{
+		{kind: tokenIdentifier, value: "a", line: 1},
+		{kind: tokenPunctuation, value: "===", line: 1},
+		{kind: tokenString, value: "b\n", line: 1},
+		{kind: tokenIdentifier, value: "c", line: 3},
+		{kind: tokenPunctuation, value: ">=", line: 3},
+		{kind: tokenNumber, value: "1.5", line: 3},
+		{kind: tokenEOF, line: 3},
+	}
+	if len(tokens) != len(want) {
+		t.Fatalf("got %+v, want %+v", tokens, want)
+	}
+	for index := range want {
+		if tokens[index] != want[index] {
+			t.Errorf("token %d: got %+v, want %+v", index, tokens[index], want[index])
+		}
+	}
+
+	for _, script := range []string{`"unterminated`, "/* unterminated", "a # b"} {
+		if _, err := tokenize(script); err == nil {
+			t.Errorf("%q: expected an error", script)
+		}
+	}
+}
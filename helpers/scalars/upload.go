@@ -0,0 +1,37 @@
+package scalars
+
+import (
+	"fmt"
+	"mime/multipart"
+)
+
+// Upload is a file that has been uploaded with a multipart GraphQL request. It
+// has to be added to a schema via "scalar Upload".
+type Upload struct {
+	File     multipart.File
+	Filename string
+	Size     int64
+}
+
+// ImplementsGraphQLType maps this custom Go type
+// to the graphql scalar type in the schema.
+func (Upload) ImplementsGraphQLType(name string) bool {
+	return name == "Upload"
+}
+
+// UnmarshalGraphQL is a custom unmarshaler for Upload
+//
+// The value is set by the multipart request handler, it can't be sent as a
+// literal in the query.
+func (u *Upload) UnmarshalGraphQL(input interface{}) error {
+	switch input := input.(type) {
+	case Upload:
+		*u = input
+		return nil
+	case *Upload:
+		*u = *input
+		return nil
+	default:
+		return fmt.Errorf("wrong type for Upload: %T", input)
+	}
+}
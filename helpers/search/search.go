@@ -0,0 +1,229 @@
+package search
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/jerbob92/hoppscotch-backend/helpers/hoppscotch"
+)
+
+const (
+	FieldTitle   = "title"
+	FieldURL     = "url"
+	FieldHeaders = "headers"
+	FieldBody    = "body"
+)
+
+// fieldWeights are used to rank matches when the database has no full-text
+// ranking.
+var fieldWeights = map[string]float64{
+	FieldTitle:   4,
+	FieldURL:     3,
+	FieldHeaders: 1,
+	FieldBody:    1,
+}
+
+// snippetBefore and snippetAfter are the amount of characters around the
+// first match that are shown in a snippet.
+const (
+	snippetBefore = 40
+	snippetAfter  = 80
+)
+
+// ParseTerms splits a search query in lowercase words, punctuation is
+// ignored so the terms are safe to use in full-text queries.
+func ParseTerms(query string) []string {
+	terms := []string{}
+	seen := map[string]bool{}
+	for _, term := range strings.FieldsFunc(strings.ToLower(query), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		if !seen[term] {
+			seen[term] = true
+			terms = append(terms, term)
+		}
+	}
+	return terms
+}
+
+type Field struct {
+	Name  string
+	Value string
+}
+
+// Document contains the searchable fields of a request.
+type Document struct {
+	Fields []Field
+}
+
+// NewDocument creates the document of a request, a request that can't be
+// parsed is searched on its title only.
+func NewDocument(title string, request *hoppscotch.RESTRequest) Document {
+	document := Document{
+		Fields: []Field{{Name: FieldTitle, Value: title}},
+	}
+
+	if request == nil {
+		return document
+	}
+
+	url := request.Endpoint
+	for _, param := range request.Params {
+		if param.Key != "" {
+			url += " " + param.Key + "=" + param.Value
+		}
+	}
+	document.Fields = append(document.Fields, Field{Name: FieldURL, Value: url})
+
+	headers := []string{}
+	for _, header := range request.Headers {
+		if header.Key != "" {
+			headers = append(headers, header.Key+": "+header.Value)
+		}
+	}
+	document.Fields = append(document.Fields, Field{Name: FieldHeaders, Value: strings.Join(headers, "\n")})
+
+	body := ""
+	if request.Body.Raw != nil {
+		body = *request.Body.Raw
+	}
+	if len(request.Body.FormData) > 0 {
+		fields := []string{}
+		for _, field := range request.Body.FormData {
+			fields = append(fields, field.Key+": "+field.Value)
+		}
+		body = strings.Join(fields, "\n")
+	}
+	document.Fields = append(document.Fields, Field{Name: FieldBody, Value: body})
+
+	return document
+}
+
+// Score ranks the document by the weighted amount of matches.
+func (d Document) Score(terms []string) float64 {
+	score := 0.0
+	for _, field := range d.Fields {
+		value := strings.ToLower(field.Value)
+		for _, term := range terms {
+			score += fieldWeights[field.Name] * float64(strings.Count(value, term))
+		}
+	}
+	return score
+}
+
+type Range struct {
+	Start  int
+	Length int
+}
+
+// Highlight is a snippet of a field around its first match. The ranges are
+// the positions of the matches in the snippet, counted in characters.
+type Highlight struct {
+	Field   string
+	Snippet string
+	Ranges  []Range
+}
+
+// Highlights returns a highlight for every field that matches one of the
+// terms.
+func (d Document) Highlights(terms []string) []Highlight {
+	highlights := []Highlight{}
+	for _, field := range d.Fields {
+		if highlight, ok := highlightField(field, terms); ok {
+			highlights = append(highlights, highlight)
+		}
+	}
+	return highlights
+}
+
+func highlightField(field Field, terms []string) (Highlight, bool) {
+	value := []rune(field.Value)
+	matches := findMatches(value, terms)
+	if len(matches) == 0 {
+		return Highlight{}, false
+	}
+
+	start := matches[0].Start - snippetBefore
+	if start < 0 {
+		start = 0
+	}
+	end := matches[0].Start + snippetAfter
+	if end > len(value) {
+		end = len(value)
+	}
+
+	snippet := []rune{}
+	offset := start
+	if start > 0 {
+		snippet = append(snippet, '…')
+		offset--
+	}
+	for _, r := range value[start:end] {
+		// Keep the snippet on one line, without changing the positions.
+		if r == '\n' || r == '\r' || r == '\t' {
+			r = ' '
+		}
+		snippet = append(snippet, r)
+	}
+	if end < len(value) {
+		snippet = append(snippet, '…')
+	}
+
+	ranges := []Range{}
+	for _, match := range matches {
+		if match.Start < start || match.Start+match.Length > end {
+			continue
+		}
+		ranges = append(ranges, Range{Start: match.Start - offset, Length: match.Length})
+	}
+
+	return Highlight{
+		Field:   field.Name,
+		Snippet: string(snippet),
+		Ranges:  ranges,
+	}, true
+}
+
+// findMatches returns the non overlapping matches of the terms in the value,
+// case insensitive and ordered by position.
+func findMatches(value []rune, terms []string) []Range {
+	lower := make([]rune, len(value))
+	for i, r := range value {
+		lower[i] = unicode.ToLower(r)
+	}
+
+	matches := []Range{}
+	for _, term := range terms {
+		termRunes := []rune(term)
+		if len(termRunes) == 0 {
+			continue
+		}
+		for i := 0; i+len(termRunes) <= len(lower); i++ {
+			if string(lower[i:i+len(termRunes)]) == term {
+				matches = append(matches, Range{Start: i, Length: len(termRunes)})
+				i += len(termRunes) - 1
+			}
+		}
+	}
+
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i].Start == matches[j].Start {
+			return matches[i].Length > matches[j].Length
+		}
+		return matches[i].Start < matches[j].Start
+	})
+
+	// Drop matches that overlap a previous, longer match.
+	output := []Range{}
+	end := -1
+	for _, match := range matches {
+		if match.Start < end {
+			continue
+		}
+		output = append(output, match)
+		end = match.Start + match.Length
+	}
+
+	return output
+}
@@ -1,13 +1,18 @@
 package main
 
 import (
-	"github.com/jerbob92/hoppscotch-backend/db"
-	"github.com/jerbob92/hoppscotch-backend/models"
+	"context"
+	"errors"
+	"flag"
 	"log"
+	"os"
 
 	"github.com/jerbob92/hoppscotch-backend/api"
+	"github.com/jerbob92/hoppscotch-backend/api/controllers/graphql/resolvers"
 	"github.com/jerbob92/hoppscotch-backend/config"
+	"github.com/jerbob92/hoppscotch-backend/db"
 	"github.com/jerbob92/hoppscotch-backend/fb"
+	"github.com/jerbob92/hoppscotch-backend/models"
 )
 
 func init() {
@@ -23,10 +28,122 @@ func main() {
 	if err := models.AutoMigrate(); err != nil {
 		log.Fatal(err)
 	}
+	if err := models.CreateSearchIndexes(); err != nil {
+		// Search still works without the index, with LIKE queries.
+		log.Printf("could not create full-text search index, falling back to LIKE search: %s", err)
+	}
+
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "migrate-requests":
+			migrated, err := models.MigrateRequests()
+			if err != nil {
+				log.Fatal(err)
+			}
+			log.Printf("migrated %d requests to the current version", migrated)
+		case "run-collection":
+			passed, err := runCollection(os.Args[2:])
+			if err != nil {
+				log.Fatal(err)
+			}
+			if !passed {
+				os.Exit(1)
+			}
+		default:
+			log.Fatalf("unknown command %s, available commands: migrate-requests, run-collection", os.Args[1])
+		}
+		return
+	}
+
 	if err := fb.Initialize(); err != nil {
 		log.Fatal(err)
 	}
+	resolvers.StartMonitorScheduler()
 	if err := api.StartAPI(); err != nil {
 		log.Fatal(err)
 	}
 }
+
+// runCollection runs a team collection from the command line, the reports
+// are written to the given files. It returns whether all requests passed.
+func runCollection(args []string) (bool, error) {
+	flags := flag.NewFlagSet("run-collection", flag.ExitOnError)
+	collectionID := flags.Uint("collection", 0, "ID of the collection to run")
+	environmentID := flags.Uint("environment", 0, "ID of the team environment to use")
+	jsonPath := flags.String("json", "", "file to write the JSON report to")
+	junitPath := flags.String("junit", "", "file to write the JUnit XML report to")
+	if err := flags.Parse(args); err != nil {
+		return false, err
+	}
+
+	if *collectionID == 0 {
+		return false, errors.New("the -collection flag is required")
+	}
+
+	collection := &models.TeamCollection{}
+	if err := db.DB.Where("id = ?", *collectionID).First(collection).Error; err != nil {
+		return false, err
+	}
+
+	var environment *models.TeamEnvironment
+	if *environmentID != 0 {
+		environment = &models.TeamEnvironment{}
+		if err := db.DB.Where("id = ? AND team_id = ?", *environmentID, collection.TeamID).First(environment).Error; err != nil {
+			return false, err
+		}
+	}
+
+	options, err := config.ExecutorOptions()
+	if err != nil {
+		return false, err
+	}
+
+	report, err := resolvers.RunTeamCollection(context.Background(), db.DB, collection, environment, options)
+	if err != nil {
+		return false, err
+	}
+
+	if *jsonPath != "" {
+		reportJSON, err := report.JSON()
+		if err != nil {
+			return false, err
+		}
+		if err := os.WriteFile(*jsonPath, []byte(reportJSON), 0644); err != nil {
+			return false, err
+		}
+	}
+
+	if *junitPath != "" {
+		reportJUnit, err := report.JUnit()
+		if err != nil {
+			return false, err
+		}
+		if err := os.WriteFile(*junitPath, []byte(reportJUnit), 0644); err != nil {
+			return false, err
+		}
+	}
+
+	for _, request := range report.Requests {
+		status := "PASS"
+		if !request.Passed {
+			status = "FAIL"
+		}
+		log.Printf("%s %s / %s (%d)", status, request.Path, request.Name, request.Status)
+		if request.Error != "" {
+			log.Printf("  %s", request.Error)
+		}
+		for _, test := range request.Tests {
+			for _, expectation := range test.Expectations {
+				if !expectation.Passed {
+					log.Printf("  %s: %s", test.Name, expectation.Message)
+				}
+			}
+			if test.Error != "" {
+				log.Printf("  %s: %s", test.Name, test.Error)
+			}
+		}
+	}
+	log.Printf("%d of %d requests failed", report.Failed, report.Total)
+
+	return report.Passed, nil
+}
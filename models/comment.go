@@ -0,0 +1,35 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Comment is a note on a request or a collection. Comments without a parent
+// start a thread, replies always have the first comment of the thread as
+// parent.
+type Comment struct {
+	gorm.Model
+	TeamID           uint `gorm:"index"`
+	Team             Team
+	TeamRequestID    *uint `gorm:"index"`
+	TeamCollectionID *uint `gorm:"index"`
+	ParentID         *uint `gorm:"index"`
+	UserID           uint
+	User             User
+	Body             string
+	EditedAt         *time.Time
+
+	// Resolving is done on the thread, so only on comments without a parent.
+	ResolvedAt   *time.Time
+	ResolvedByID *uint
+}
+
+// CommentMention is a team member that is mentioned in a comment.
+type CommentMention struct {
+	gorm.Model
+	CommentID uint `gorm:"index"`
+	UserID    uint
+	User      User
+}
@@ -3,5 +3,5 @@ package models
 import "github.com/jerbob92/hoppscotch-backend/db"
 
 func AutoMigrate() error {
-	return db.DB.AutoMigrate(&Shortcode{}, &Team{}, &TeamCollection{}, &TeamInvitation{}, &TeamMember{}, &TeamRequest{}, &TeamEnvironment{}, &User{})
+	return db.DB.AutoMigrate(&Shortcode{}, &Team{}, &TeamCollection{}, &TeamInvitation{}, &TeamMember{}, &TeamRequest{}, &TeamRequestRevision{}, &TeamRequestExample{}, &Comment{}, &CommentMention{}, &TeamEnvironment{}, &Monitor{}, &MonitorRun{}, &MonitorRunRequest{}, &MockServer{}, &User{}, &UserCollection{}, &UserRequest{}, &UserEnvironment{}, &UserHistory{}, &UserSettings{})
 }
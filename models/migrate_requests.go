@@ -0,0 +1,55 @@
+package models
+
+import (
+	"log"
+
+	"github.com/jerbob92/hoppscotch-backend/db"
+	"github.com/jerbob92/hoppscotch-backend/helpers/hoppscotch"
+	"gorm.io/gorm"
+)
+
+// migrateRequestsBatchSize is the amount of requests loaded at once by
+// MigrateRequests.
+const migrateRequestsBatchSize = 500
+
+// MigrateRequests upgrades the stored request data of all requests to the
+// current version. Requests that can't be migrated are logged and left as
+// they are. It returns the amount of migrated requests.
+func MigrateRequests() (int, error) {
+	migrated := 0
+	requests := []*TeamRequest{}
+	result := db.DB.Model(&TeamRequest{}).FindInBatches(&requests, migrateRequestsBatchSize, func(tx *gorm.DB, batch int) error {
+		// This is a change of format, not of content, so the version and
+		// updated at of the request are left as they are.
+		batchMigrated, err := migrateRequestBatch(requests, func(id uint, data string) error {
+			return db.DB.Model(&TeamRequest{}).Where("id = ?", id).UpdateColumn("request", data).Error
+		})
+		migrated += batchMigrated
+		return err
+	})
+
+	return migrated, result.Error
+}
+
+// migrateRequestBatch migrates the requests and stores the changed ones with
+// update. It returns the amount of migrated requests.
+func migrateRequestBatch(requests []*TeamRequest, update func(id uint, data string) error) (int, error) {
+	migrated := 0
+	for _, request := range requests {
+		data, changed, err := hoppscotch.MigrateRequestJSON(request.Request)
+		if err != nil {
+			log.Printf("could not migrate request %d: %s", request.ID, err)
+			continue
+		}
+		if !changed {
+			continue
+		}
+
+		err = update(request.ID, data)
+		if err != nil {
+			return migrated, err
+		}
+		migrated++
+	}
+	return migrated, nil
+}
@@ -0,0 +1,51 @@
+package models
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestMigrateRequestBatch(t *testing.T) {
+	requests := []*TeamRequest{
+		{Request: `{"name": "legacy", "url": "https://example.com", "method": "GET"}`},
+		{Request: `{"v": "1", "name": "current", "endpoint": "https://example.com", "method": "GET"}`},
+		{Request: `{"v": "9", "name": "future", "endpoint": "https://example.com", "method": "GET"}`},
+		{Request: `not json`},
+		{Request: `{"name": "legacy graphql", "url": "https://example.com/graphql", "query": "{ a }"}`},
+	}
+	for i := range requests {
+		requests[i].ID = uint(i + 1)
+	}
+
+	updated := map[uint]string{}
+	migrated, err := migrateRequestBatch(requests, func(id uint, data string) error {
+		updated[id] = data
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if migrated != 2 || len(updated) != 2 {
+		t.Fatalf("migrated = %d, updated = %v, want the legacy requests only", migrated, updated)
+	}
+	if !strings.Contains(updated[1], `"v":"1"`) || !strings.Contains(updated[5], `"v":"2"`) {
+		t.Errorf("unexpected migrated data %v", updated)
+	}
+}
+
+func TestMigrateRequestBatchUpdateError(t *testing.T) {
+	requests := []*TeamRequest{
+		{Request: `{"name": "a", "url": "https://example.com", "method": "GET"}`},
+		{Request: `{"name": "b", "url": "https://example.com", "method": "GET"}`},
+	}
+
+	updateErr := errors.New("connection lost")
+	migrated, err := migrateRequestBatch(requests, func(id uint, data string) error {
+		return updateErr
+	})
+	if !errors.Is(err, updateErr) || migrated != 0 {
+		t.Errorf("migrated = %d, err = %v, want 0 and the update error", migrated, err)
+	}
+}
@@ -0,0 +1,22 @@
+package models
+
+import "gorm.io/gorm"
+
+// MockServer answers requests with the saved examples of the requests of a
+// team, or of a collection and its child collections.
+type MockServer struct {
+	gorm.Model
+	TeamID uint `gorm:"index"`
+	Team   Team
+
+	// TeamCollectionID is 0 for a mock server of the whole team.
+	TeamCollectionID uint
+	Name             string
+	Slug             string `gorm:"uniqueIndex;size:32"`
+	Enabled          bool
+
+	// Delay is the latency added to every response, in milliseconds.
+	Delay        int
+	MatchHeaders bool
+	CreatedByID  uint
+}
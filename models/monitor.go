@@ -0,0 +1,72 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type MonitorStatus string
+
+const (
+	MonitorPending MonitorStatus = "PENDING"
+	MonitorPassing MonitorStatus = "PASSING"
+	MonitorFailing MonitorStatus = "FAILING"
+)
+
+// Monitor runs a collection with an environment on a cron schedule.
+type Monitor struct {
+	gorm.Model
+	TeamID            uint `gorm:"index"`
+	Team              Team
+	TeamCollectionID  uint
+	TeamCollection    TeamCollection
+	TeamEnvironmentID *uint
+	Name              string
+	Schedule          string
+	Enabled           bool
+	Status            MonitorStatus
+	CreatedByID       uint
+
+	// NextRunAt is the time of the next run, nil when the schedule has no
+	// next run.
+	NextRunAt *time.Time `gorm:"index"`
+	LastRunAt *time.Time
+
+	// Version is incremented on every update, a run is claimed by updating
+	// the monitor at the version it was loaded with.
+	Version uint `gorm:"not null;default:1"`
+}
+
+// MonitorRun is a single run of a monitor, durations are in milliseconds.
+type MonitorRun struct {
+	gorm.Model
+	MonitorID  uint `gorm:"index"`
+	Monitor    Monitor
+	StartedAt  time.Time `gorm:"index"`
+	FinishedAt time.Time
+	Passed     bool
+	Total      int
+	Failed     int
+	Duration   float64
+
+	// Error is set when the collection could not be run at all.
+	Error string
+}
+
+// MonitorRunRequest is the outcome of a single request of a monitor run.
+type MonitorRunRequest struct {
+	gorm.Model
+	MonitorRunID  uint `gorm:"index"`
+	MonitorRun    MonitorRun
+	TeamRequestID uint
+	Name          string
+	Path          string
+	Method        string
+	URL           string
+	Status        int
+	Duration      float64
+	Passed        bool
+	Error         string
+	Unsupported   string
+}
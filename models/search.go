@@ -0,0 +1,51 @@
+package models
+
+import (
+	"github.com/jerbob92/hoppscotch-backend/db"
+)
+
+// FullTextSearch is true when the database has a full-text index on the
+// requests, otherwise searching falls back to LIKE queries.
+var FullTextSearch = false
+
+// PostgresSearchVector is the expression of the full-text index on Postgres,
+// queries must use the exact same expression to use the index.
+const PostgresSearchVector = "to_tsvector('simple', coalesce(team_requests.title, '') || ' ' || coalesce(team_requests.request, ''))"
+
+// CreateSearchIndexes creates the full-text index on the title and data of
+// the requests for the configured driver, when it doesn't exist yet.
+func CreateSearchIndexes() error {
+	statements := []string{}
+
+	switch db.Driver() {
+	case "mysql":
+		var count int64
+		err := db.DB.Raw("SELECT COUNT(*) FROM information_schema.statistics WHERE table_schema = DATABASE() AND table_name = 'team_requests' AND index_name = 'idx_team_requests_search'").Scan(&count).Error
+		if err != nil {
+			return err
+		}
+		if count == 0 {
+			statements = append(statements, "ALTER TABLE team_requests ADD FULLTEXT INDEX idx_team_requests_search (title, request)")
+		}
+	case "postgres":
+		statements = append(statements, "CREATE INDEX IF NOT EXISTS idx_team_requests_search ON team_requests USING GIN ("+PostgresSearchVector+")")
+	case "mssql":
+		// A full-text index needs a catalog and a unique single column key.
+		statements = append(statements,
+			"IF NOT EXISTS (SELECT 1 FROM sys.fulltext_catalogs WHERE name = 'hoppscotch_search') CREATE FULLTEXT CATALOG hoppscotch_search",
+			"IF NOT EXISTS (SELECT 1 FROM sys.indexes WHERE name = 'ux_team_requests_search_key') CREATE UNIQUE INDEX ux_team_requests_search_key ON team_requests (id)",
+			"IF NOT EXISTS (SELECT 1 FROM sys.fulltext_indexes WHERE object_id = OBJECT_ID('team_requests')) CREATE FULLTEXT INDEX ON team_requests (title, request) KEY INDEX ux_team_requests_search_key ON hoppscotch_search WITH CHANGE_TRACKING AUTO",
+		)
+	default:
+		return nil
+	}
+
+	for _, statement := range statements {
+		if err := db.DB.Exec(statement).Error; err != nil {
+			return err
+		}
+	}
+
+	FullTextSearch = true
+	return nil
+}
@@ -8,4 +8,11 @@ type TeamCollection struct {
 	Team     Team
 	Title    string
 	ParentID uint
+
+	// Properties is a JSON string with the headers, auth and variables that
+	// child collections and requests inherit.
+	Properties string
+
+	// Version is incremented on every update, for optimistic concurrency.
+	Version uint `gorm:"not null;default:1"`
 }
@@ -8,4 +8,7 @@ type TeamEnvironment struct {
 	Team      Team
 	Name      string
 	Variables string
+
+	// Version is incremented on every update, for optimistic concurrency.
+	Version uint `gorm:"not null;default:1"`
 }
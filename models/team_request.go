@@ -10,4 +10,7 @@ type TeamRequest struct {
 	TeamCollection   TeamCollection
 	Request          string
 	Title            string
+
+	// Version is incremented on every update, for optimistic concurrency.
+	Version uint `gorm:"not null;default:1"`
 }
@@ -0,0 +1,16 @@
+package models
+
+import "gorm.io/gorm"
+
+// TeamRequestExample is a saved example response of a request.
+type TeamRequestExample struct {
+	gorm.Model
+	TeamRequestID uint `gorm:"index"`
+	TeamRequest   TeamRequest
+	Name          string
+	Status        int
+
+	// Headers is a JSON list of key/value pairs.
+	Headers string
+	Body    string
+}
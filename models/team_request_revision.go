@@ -0,0 +1,16 @@
+package models
+
+import "gorm.io/gorm"
+
+type TeamRequestRevision struct {
+	gorm.Model
+	TeamRequestID uint `gorm:"index"`
+	TeamRequest   TeamRequest
+	Request       string
+	Title         string
+
+	// UserID is the author of the change, it is nil for the state of the
+	// request from before revisions were kept.
+	UserID *uint
+	User   *User
+}
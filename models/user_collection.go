@@ -0,0 +1,25 @@
+package models
+
+import "gorm.io/gorm"
+
+type ReqType string
+
+const (
+	RESTReqType ReqType = "REST"
+	GQLReqType  ReqType = "GQL"
+)
+
+// UserCollection is a personal collection of a user, it only holds requests
+// of its type.
+type UserCollection struct {
+	gorm.Model
+	UserID   uint `gorm:"index"`
+	User     User
+	Type     ReqType
+	Title    string
+	ParentID uint
+
+	// Properties is a JSON string with the headers, auth and variables that
+	// child collections and requests inherit.
+	Properties string
+}
@@ -0,0 +1,14 @@
+package models
+
+import "gorm.io/gorm"
+
+// UserEnvironment is a personal environment of a user. Every user has at most
+// one global environment, which has no name.
+type UserEnvironment struct {
+	gorm.Model
+	UserID    uint `gorm:"index"`
+	User      User
+	Name      string
+	Variables string
+	IsGlobal  bool
+}
@@ -0,0 +1,15 @@
+package models
+
+import "gorm.io/gorm"
+
+// UserHistory is a request a user has sent, with metadata of its response.
+// Starred entries are kept when the history is trimmed.
+type UserHistory struct {
+	gorm.Model
+	UserID           uint `gorm:"index"`
+	User             User
+	ReqType          ReqType
+	Request          string
+	ResponseMetadata string
+	IsStarred        bool
+}
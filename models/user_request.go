@@ -0,0 +1,15 @@
+package models
+
+import "gorm.io/gorm"
+
+// UserRequest is a request in a personal collection of a user.
+type UserRequest struct {
+	gorm.Model
+	UserID           uint `gorm:"index"`
+	User             User
+	UserCollectionID uint `gorm:"index"`
+	UserCollection   UserCollection
+	Type             ReqType
+	Title            string
+	Request          string
+}
@@ -0,0 +1,13 @@
+package models
+
+import "gorm.io/gorm"
+
+// UserSettings is the settings document of the frontend for a user.
+type UserSettings struct {
+	gorm.Model
+	UserID uint `gorm:"uniqueIndex"`
+	User   User
+
+	// Properties is a JSON object, its fields are defined by the frontend.
+	Properties string
+}